@@ -0,0 +1,183 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.1
+// source: domain/DomainService.proto
+
+package domain_pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DomainService_AddDomainRule_FullMethodName    = "/domain.DomainService/AddDomainRule"
+	DomainService_ListDomainRules_FullMethodName  = "/domain.DomainService/ListDomainRules"
+	DomainService_RemoveDomainRule_FullMethodName = "/domain.DomainService/RemoveDomainRule"
+)
+
+// DomainServiceClient is the client API for DomainService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DomainServiceClient interface {
+	AddDomainRule(ctx context.Context, in *AddDomainRuleRequest, opts ...grpc.CallOption) (*DomainRule, error)
+	ListDomainRules(ctx context.Context, in *ListDomainRulesRequest, opts ...grpc.CallOption) (*ListDomainRulesResponse, error)
+	RemoveDomainRule(ctx context.Context, in *RemoveDomainRuleRequest, opts ...grpc.CallOption) (*RemoveDomainRuleResponse, error)
+}
+
+type domainServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDomainServiceClient(cc grpc.ClientConnInterface) DomainServiceClient {
+	return &domainServiceClient{cc}
+}
+
+func (c *domainServiceClient) AddDomainRule(ctx context.Context, in *AddDomainRuleRequest, opts ...grpc.CallOption) (*DomainRule, error) {
+	out := new(DomainRule)
+	err := c.cc.Invoke(ctx, DomainService_AddDomainRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainServiceClient) ListDomainRules(ctx context.Context, in *ListDomainRulesRequest, opts ...grpc.CallOption) (*ListDomainRulesResponse, error) {
+	out := new(ListDomainRulesResponse)
+	err := c.cc.Invoke(ctx, DomainService_ListDomainRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainServiceClient) RemoveDomainRule(ctx context.Context, in *RemoveDomainRuleRequest, opts ...grpc.CallOption) (*RemoveDomainRuleResponse, error) {
+	out := new(RemoveDomainRuleResponse)
+	err := c.cc.Invoke(ctx, DomainService_RemoveDomainRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DomainServiceServer is the server API for DomainService service.
+// All implementations must embed UnimplementedDomainServiceServer
+// for forward compatibility
+type DomainServiceServer interface {
+	AddDomainRule(context.Context, *AddDomainRuleRequest) (*DomainRule, error)
+	ListDomainRules(context.Context, *ListDomainRulesRequest) (*ListDomainRulesResponse, error)
+	RemoveDomainRule(context.Context, *RemoveDomainRuleRequest) (*RemoveDomainRuleResponse, error)
+	mustEmbedUnimplementedDomainServiceServer()
+}
+
+// UnimplementedDomainServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDomainServiceServer struct {
+}
+
+func (UnimplementedDomainServiceServer) AddDomainRule(context.Context, *AddDomainRuleRequest) (*DomainRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddDomainRule not implemented")
+}
+func (UnimplementedDomainServiceServer) ListDomainRules(context.Context, *ListDomainRulesRequest) (*ListDomainRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDomainRules not implemented")
+}
+func (UnimplementedDomainServiceServer) RemoveDomainRule(context.Context, *RemoveDomainRuleRequest) (*RemoveDomainRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveDomainRule not implemented")
+}
+func (UnimplementedDomainServiceServer) mustEmbedUnimplementedDomainServiceServer() {}
+
+// UnsafeDomainServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DomainServiceServer will
+// result in compilation errors.
+type UnsafeDomainServiceServer interface {
+	mustEmbedUnimplementedDomainServiceServer()
+}
+
+func RegisterDomainServiceServer(s grpc.ServiceRegistrar, srv DomainServiceServer) {
+	s.RegisterService(&DomainService_ServiceDesc, srv)
+}
+
+func _DomainService_AddDomainRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDomainRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainServiceServer).AddDomainRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DomainService_AddDomainRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainServiceServer).AddDomainRule(ctx, req.(*AddDomainRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainService_ListDomainRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDomainRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainServiceServer).ListDomainRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DomainService_ListDomainRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainServiceServer).ListDomainRules(ctx, req.(*ListDomainRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainService_RemoveDomainRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveDomainRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainServiceServer).RemoveDomainRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DomainService_RemoveDomainRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainServiceServer).RemoveDomainRule(ctx, req.(*RemoveDomainRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DomainService_ServiceDesc is the grpc.ServiceDesc for DomainService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DomainService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "domain.DomainService",
+	HandlerType: (*DomainServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddDomainRule",
+			Handler:    _DomainService_AddDomainRule_Handler,
+		},
+		{
+			MethodName: "ListDomainRules",
+			Handler:    _DomainService_ListDomainRules_Handler,
+		},
+		{
+			MethodName: "RemoveDomainRule",
+			Handler:    _DomainService_RemoveDomainRule_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "domain/DomainService.proto",
+}
@@ -1,3 +1,4 @@
 package api
 
 //go:generate protoc --go_out=url/pb --go-grpc_out=url/pb url/URLService.proto
+//go:generate protoc --go_out=domain/pb --go-grpc_out=domain/pb domain/DomainService.proto
@@ -19,8 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	EventService_CreateURLAlias_FullMethodName     = "/url.EventService/CreateURLAlias"
-	EventService_GetOriginalByAlias_FullMethodName = "/url.EventService/GetOriginalByAlias"
+	EventService_CreateURLAlias_FullMethodName      = "/url.EventService/CreateURLAlias"
+	EventService_CreateURLAliasBatch_FullMethodName = "/url.EventService/CreateURLAliasBatch"
+	EventService_GetOriginalByAlias_FullMethodName  = "/url.EventService/GetOriginalByAlias"
+	EventService_GetURLStats_FullMethodName         = "/url.EventService/GetURLStats"
+	EventService_UpdateOriginal_FullMethodName      = "/url.EventService/UpdateOriginal"
+	EventService_DeleteByAlias_FullMethodName       = "/url.EventService/DeleteByAlias"
 )
 
 // EventServiceClient is the client API for EventService service.
@@ -28,7 +32,11 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type EventServiceClient interface {
 	CreateURLAlias(ctx context.Context, in *CreateURLAliasRequest, opts ...grpc.CallOption) (*CreateURLAliasResponse, error)
+	CreateURLAliasBatch(ctx context.Context, in *CreateURLAliasBatchRequest, opts ...grpc.CallOption) (*CreateURLAliasBatchResponse, error)
 	GetOriginalByAlias(ctx context.Context, in *GetOriginalByAliasRequest, opts ...grpc.CallOption) (*GetOriginalByAliasResponse, error)
+	GetURLStats(ctx context.Context, in *GetURLStatsRequest, opts ...grpc.CallOption) (*GetURLStatsResponse, error)
+	UpdateOriginal(ctx context.Context, in *UpdateOriginalRequest, opts ...grpc.CallOption) (*UpdateOriginalResponse, error)
+	DeleteByAlias(ctx context.Context, in *DeleteByAliasRequest, opts ...grpc.CallOption) (*DeleteByAliasResponse, error)
 }
 
 type eventServiceClient struct {
@@ -48,6 +56,15 @@ func (c *eventServiceClient) CreateURLAlias(ctx context.Context, in *CreateURLAl
 	return out, nil
 }
 
+func (c *eventServiceClient) CreateURLAliasBatch(ctx context.Context, in *CreateURLAliasBatchRequest, opts ...grpc.CallOption) (*CreateURLAliasBatchResponse, error) {
+	out := new(CreateURLAliasBatchResponse)
+	err := c.cc.Invoke(ctx, EventService_CreateURLAliasBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *eventServiceClient) GetOriginalByAlias(ctx context.Context, in *GetOriginalByAliasRequest, opts ...grpc.CallOption) (*GetOriginalByAliasResponse, error) {
 	out := new(GetOriginalByAliasResponse)
 	err := c.cc.Invoke(ctx, EventService_GetOriginalByAlias_FullMethodName, in, out, opts...)
@@ -57,12 +74,43 @@ func (c *eventServiceClient) GetOriginalByAlias(ctx context.Context, in *GetOrig
 	return out, nil
 }
 
+func (c *eventServiceClient) GetURLStats(ctx context.Context, in *GetURLStatsRequest, opts ...grpc.CallOption) (*GetURLStatsResponse, error) {
+	out := new(GetURLStatsResponse)
+	err := c.cc.Invoke(ctx, EventService_GetURLStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) UpdateOriginal(ctx context.Context, in *UpdateOriginalRequest, opts ...grpc.CallOption) (*UpdateOriginalResponse, error) {
+	out := new(UpdateOriginalResponse)
+	err := c.cc.Invoke(ctx, EventService_UpdateOriginal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) DeleteByAlias(ctx context.Context, in *DeleteByAliasRequest, opts ...grpc.CallOption) (*DeleteByAliasResponse, error) {
+	out := new(DeleteByAliasResponse)
+	err := c.cc.Invoke(ctx, EventService_DeleteByAlias_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EventServiceServer is the server API for EventService service.
 // All implementations must embed UnimplementedEventServiceServer
 // for forward compatibility
 type EventServiceServer interface {
 	CreateURLAlias(context.Context, *CreateURLAliasRequest) (*CreateURLAliasResponse, error)
+	CreateURLAliasBatch(context.Context, *CreateURLAliasBatchRequest) (*CreateURLAliasBatchResponse, error)
 	GetOriginalByAlias(context.Context, *GetOriginalByAliasRequest) (*GetOriginalByAliasResponse, error)
+	GetURLStats(context.Context, *GetURLStatsRequest) (*GetURLStatsResponse, error)
+	UpdateOriginal(context.Context, *UpdateOriginalRequest) (*UpdateOriginalResponse, error)
+	DeleteByAlias(context.Context, *DeleteByAliasRequest) (*DeleteByAliasResponse, error)
 	mustEmbedUnimplementedEventServiceServer()
 }
 
@@ -73,9 +121,21 @@ type UnimplementedEventServiceServer struct {
 func (UnimplementedEventServiceServer) CreateURLAlias(context.Context, *CreateURLAliasRequest) (*CreateURLAliasResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateURLAlias not implemented")
 }
+func (UnimplementedEventServiceServer) CreateURLAliasBatch(context.Context, *CreateURLAliasBatchRequest) (*CreateURLAliasBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateURLAliasBatch not implemented")
+}
 func (UnimplementedEventServiceServer) GetOriginalByAlias(context.Context, *GetOriginalByAliasRequest) (*GetOriginalByAliasResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOriginalByAlias not implemented")
 }
+func (UnimplementedEventServiceServer) GetURLStats(context.Context, *GetURLStatsRequest) (*GetURLStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURLStats not implemented")
+}
+func (UnimplementedEventServiceServer) UpdateOriginal(context.Context, *UpdateOriginalRequest) (*UpdateOriginalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOriginal not implemented")
+}
+func (UnimplementedEventServiceServer) DeleteByAlias(context.Context, *DeleteByAliasRequest) (*DeleteByAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteByAlias not implemented")
+}
 func (UnimplementedEventServiceServer) mustEmbedUnimplementedEventServiceServer() {}
 
 // UnsafeEventServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -107,6 +167,24 @@ func _EventService_CreateURLAlias_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EventService_CreateURLAliasBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateURLAliasBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).CreateURLAliasBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventService_CreateURLAliasBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).CreateURLAliasBatch(ctx, req.(*CreateURLAliasBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EventService_GetOriginalByAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetOriginalByAliasRequest)
 	if err := dec(in); err != nil {
@@ -125,6 +203,60 @@ func _EventService_GetOriginalByAlias_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EventService_GetURLStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetURLStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).GetURLStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventService_GetURLStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).GetURLStats(ctx, req.(*GetURLStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_UpdateOriginal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOriginalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).UpdateOriginal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventService_UpdateOriginal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).UpdateOriginal(ctx, req.(*UpdateOriginalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_DeleteByAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteByAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).DeleteByAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventService_DeleteByAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).DeleteByAlias(ctx, req.(*DeleteByAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // EventService_ServiceDesc is the grpc.ServiceDesc for EventService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -136,10 +268,26 @@ var EventService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateURLAlias",
 			Handler:    _EventService_CreateURLAlias_Handler,
 		},
+		{
+			MethodName: "CreateURLAliasBatch",
+			Handler:    _EventService_CreateURLAliasBatch_Handler,
+		},
 		{
 			MethodName: "GetOriginalByAlias",
 			Handler:    _EventService_GetOriginalByAlias_Handler,
 		},
+		{
+			MethodName: "GetURLStats",
+			Handler:    _EventService_GetURLStats_Handler,
+		},
+		{
+			MethodName: "UpdateOriginal",
+			Handler:    _EventService_UpdateOriginal_Handler,
+		},
+		{
+			MethodName: "DeleteByAlias",
+			Handler:    _EventService_DeleteByAlias_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "url/URLService.proto",
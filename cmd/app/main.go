@@ -1,8 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"github.com/romandnk/shortener/internal/app"
-	"go.uber.org/fx"
+	"os"
 )
 
 //	@title			URL shortener project
@@ -14,7 +15,14 @@ import (
 //	@license.name	romandnk
 //	@license.url	https://github.com/romandnk/shortener
 
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						X-API-Key
+
 // @BasePath	/api/v1/
 func main() {
-	fx.New(app.NewApp()).Run()
+	if err := app.Run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
@@ -3,32 +3,63 @@ package config
 import (
 	"fmt"
 	"github.com/ilyakaznacheev/cleanenv"
+	purgejob "github.com/romandnk/shortener/internal/job/purge"
+	redirectroute "github.com/romandnk/shortener/internal/server/http/v1/redirect"
+	authservice "github.com/romandnk/shortener/internal/service/auth"
+	clickservice "github.com/romandnk/shortener/internal/service/click"
+	domainservice "github.com/romandnk/shortener/internal/service/domain"
+	urlservice "github.com/romandnk/shortener/internal/service/url"
+	"github.com/romandnk/shortener/internal/storage"
+	"github.com/romandnk/shortener/pkg/generator"
 	"github.com/romandnk/shortener/pkg/grpcserver"
+	"github.com/romandnk/shortener/pkg/health"
 	"github.com/romandnk/shortener/pkg/httpserver"
 	zaplogger "github.com/romandnk/shortener/pkg/logger/zap"
+	"github.com/romandnk/shortener/pkg/ratelimit"
 	"github.com/romandnk/shortener/pkg/storage/postgres"
 	"github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/romandnk/shortener/pkg/tracing"
 	"go.uber.org/fx"
+	"os"
 )
 
 var Module = fx.Module("config", fx.Provide(NewConfig))
 
-const configPath string = "./config/config.yaml"
+const (
+	configPath    string = "./config/config.yaml"
+	configPathEnv string = "CONFIG_PATH"
+)
 
 type Config struct {
 	//fx.Out     `yaml:"-"`
-	ZapLogger  zaplogger.Config  `yaml:"zap_logger"`
-	Postgres   postgres.Config   `yaml:"postgres"`
-	Redis      redis.Config      `yaml:"redis"`
-	HTTPServer httpserver.Config `yaml:"http_server"`
-	GRPCServer grpcserver.Config `yaml:"grpc_server"`
-	DBType     string            `yaml:"db_type"`
+	ZapLogger    zaplogger.Config     `yaml:"zap_logger"`
+	Postgres     postgres.Config      `yaml:"postgres"`
+	Redis        redis.Config         `yaml:"redis"`
+	Cache        storage.CacheConfig  `yaml:"cache"`
+	HTTPServer   httpserver.Config    `yaml:"http_server"`
+	GRPCServer   grpcserver.Config    `yaml:"grpc_server"`
+	Redirect     redirectroute.Config `yaml:"redirect"`
+	URLService   urlservice.Config    `yaml:"url_service"`
+	Generator    generator.Config     `yaml:"alias_generator"`
+	ClickService clickservice.Config  `yaml:"click_service"`
+	PurgeJob     purgejob.Config      `yaml:"purge_job"`
+	Auth         authservice.Config   `yaml:"auth"`
+	DomainRules  domainservice.Config `yaml:"domain_rules"`
+	RateLimit    ratelimit.Config     `yaml:"rate_limit"`
+	Tracing      tracing.Config       `yaml:"tracing"`
+	Health       health.Config        `yaml:"health"`
+	DBType       string               `yaml:"db_type" env:"DB_TYPE" env-default:"postgres"`
 }
 
 func NewConfig() (*Config, error) {
 	var cfg Config
 
-	err := cleanenv.ReadConfig(configPath, &cfg)
+	path := configPath
+	if p, ok := os.LookupEnv(configPathEnv); ok {
+		path = p
+	}
+
+	err := cleanenv.ReadConfig(path, &cfg)
 	if err != nil {
 		return &cfg, err
 	}
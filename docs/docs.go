@@ -22,16 +22,174 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/domains": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all domain rules sorted by pattern.",
+                "tags": [
+                    "Domain"
+                ],
+                "summary": "List domain rules",
+                "responses": {
+                    "200": {
+                        "description": "Domain rules were received successfully",
+                        "schema": {
+                            "$ref": "#/definitions/domainroute.ListDomainRulesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key is missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "API key is not an admin one",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Block or allow links to hosts matching the pattern. An existing pattern is moved to the list. Links to blocked hosts stop redirecting immediately.",
+                "tags": [
+                    "Domain"
+                ],
+                "summary": "Add domain rule",
+                "parameters": [
+                    {
+                        "description": "Required JSON body with host pattern and list",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domainroute.AddDomainRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Domain rule was saved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/domainroute.DomainRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "API key is missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "API key is not an admin one",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/domains/:pattern": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove the rule of the pattern, links to matching hosts are resolved again unless another rule matches.",
+                "tags": [
+                    "Domain"
+                ],
+                "summary": "Remove domain rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Required path param with host pattern",
+                        "name": "pattern",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Domain rule was removed successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "API key is missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "API key is not an admin one",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Domain rule is not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/urls": {
             "post": {
-                "description": "Create short new URL alias if not exists.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create short new URL alias if not exists. In idempotent mode the existing alias is returned with 200.",
                 "tags": [
                     "URL"
                 ],
                 "summary": "Create short URL alias",
                 "parameters": [
                     {
-                        "description": "Required JSON body with original url",
+                        "description": "Required JSON body with original url, optional custom alias and expiration",
                         "name": "params",
                         "in": "body",
                         "required": true,
@@ -41,6 +199,12 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "URL alias already exists",
+                        "schema": {
+                            "$ref": "#/definitions/urlroute.CreateURLAliasResponse"
+                        }
+                    },
                     "201": {
                         "description": "URL alias was created successfully",
                         "schema": {
@@ -48,7 +212,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid input data",
+                        "description": "Invalid input data, reason is set if the URL is rejected by the policy",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "API key is missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Custom aliases are disabled",
                         "schema": {
                             "$ref": "#/definitions/httpresponse.Response"
                         }
@@ -91,6 +267,216 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpresponse.Response"
                         }
                     },
+                    "410": {
+                        "description": "URL alias is expired",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Point existing URL alias to another original URL. Expiration of the alias is kept.",
+                "tags": [
+                    "URL"
+                ],
+                "summary": "Update original URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Required path param with url alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Required JSON body with new original url",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/urlroute.UpdateOriginalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Original URL was updated successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input data, reason is set if the URL is rejected by the policy",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "API key is missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "URL alias is not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete URL alias, the alias stops redirecting immediately.",
+                "tags": [
+                    "URL"
+                ],
+                "summary": "Delete URL alias",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Required path param with url alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "URL alias was deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "API key is missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "URL alias is not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/:alias/stats": {
+            "get": {
+                "description": "Get total clicks of the URL alias and clicks per UTC day. Clicks are written asynchronously and appear with a small delay.",
+                "tags": [
+                    "URL"
+                ],
+                "summary": "Get URL click stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Required path param with url alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stats were received successfully",
+                        "schema": {
+                            "$ref": "#/definitions/urlroute.GetURLStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "URL alias is not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create aliases of many URLs with a fixed number of storage calls. Results are returned in order of URLs, invalid or conflicting URLs get an error and do not fail the batch.",
+                "tags": [
+                    "URL"
+                ],
+                "summary": "Create short URL aliases in batch",
+                "parameters": [
+                    {
+                        "description": "Required JSON body with urls in the same format as for a single url",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/urlroute.CreateURLAliasBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch was processed",
+                        "schema": {
+                            "$ref": "#/definitions/urlroute.CreateURLAliasBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "API key is missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/httpresponse.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal error",
                         "schema": {
@@ -102,6 +488,41 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "domainroute.AddDomainRuleRequest": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "description": "block or allow",
+                    "type": "string"
+                },
+                "pattern": {
+                    "description": "host like example.com or *. followed by a host to match it and its subdomains",
+                    "type": "string"
+                }
+            }
+        },
+        "domainroute.DomainRule": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                }
+            }
+        },
+        "domainroute.ListDomainRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domainroute.DomainRule"
+                    }
+                }
+            }
+        },
         "httpresponse.Response": {
             "type": "object",
             "properties": {
@@ -110,14 +531,76 @@ const docTemplate = `{
                 },
                 "message": {
                     "type": "string"
+                },
+                "reason": {
+                    "description": "machine readable reason of rejected input, e.g. SCHEME_NOT_ALLOWED or PRIVATE_HOST",
+                    "type": "string"
+                }
+            }
+        },
+        "urlroute.CreateURLAliasBatchRequest": {
+            "type": "object",
+            "properties": {
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/urlroute.CreateURLAliasRequest"
+                    }
+                }
+            }
+        },
+        "urlroute.CreateURLAliasBatchResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "description": "results in order of requested urls",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/urlroute.CreateURLAliasBatchResult"
+                    }
+                }
+            }
+        },
+        "urlroute.CreateURLAliasBatchResult": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "reason why the url was not shortened",
+                    "type": "string"
+                },
+                "existing": {
+                    "description": "true if the original url was already shortened and its alias is returned",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "machine readable reason if the url is rejected by the policy",
+                    "type": "string"
                 }
             }
         },
         "urlroute.CreateURLAliasRequest": {
             "type": "object",
             "properties": {
+                "alias": {
+                    "description": "optional custom alias, generated if empty",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "optional expiration time in RFC 3339 format, cannot be set together with ttl",
+                    "type": "string"
+                },
                 "original_url": {
                     "type": "string"
+                },
+                "ttl": {
+                    "description": "optional time to live, e.g. \"24h\" or \"90m\"",
+                    "type": "string"
                 }
             }
         },
@@ -126,6 +609,21 @@ const docTemplate = `{
             "properties": {
                 "alias": {
                     "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "urlroute.DailyClicks": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "UTC day in 2006-01-02 format",
+                    "type": "string"
                 }
             }
         },
@@ -136,6 +634,39 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "urlroute.GetURLStatsResponse": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "daily": {
+                    "description": "days without clicks are omitted",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/urlroute.DailyClicks"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "urlroute.UpdateOriginalRequest": {
+            "type": "object",
+            "properties": {
+                "original_url": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         }
     }
 }`
@@ -3,29 +3,36 @@ package app
 import (
 	"context"
 	"github.com/romandnk/shortener/config"
-	"github.com/romandnk/shortener/internal/constant"
+	purgejob "github.com/romandnk/shortener/internal/job/purge"
+	"github.com/romandnk/shortener/internal/metrics"
+	domaingrpc "github.com/romandnk/shortener/internal/server/grpc/domain"
 	"github.com/romandnk/shortener/internal/server/grpc/interceptor"
 	urlgrpc "github.com/romandnk/shortener/internal/server/grpc/url"
 	"github.com/romandnk/shortener/internal/server/http/middleware"
 	v1 "github.com/romandnk/shortener/internal/server/http/v1"
+	redirectroute "github.com/romandnk/shortener/internal/server/http/v1/redirect"
 	"github.com/romandnk/shortener/internal/service"
 	"github.com/romandnk/shortener/internal/storage"
 	"github.com/romandnk/shortener/pkg/generator"
 	"github.com/romandnk/shortener/pkg/grpcserver"
+	"github.com/romandnk/shortener/pkg/health"
 	"github.com/romandnk/shortener/pkg/httpserver"
 	"github.com/romandnk/shortener/pkg/logger"
 	zaplogger "github.com/romandnk/shortener/pkg/logger/zap"
-	"github.com/romandnk/shortener/pkg/storage/postgres"
-	"github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/romandnk/shortener/pkg/ratelimit"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/romandnk/shortener/pkg/tracing"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"maps"
 	"net"
 	"net/http"
 	"os/signal"
 	"strconv"
-	"sync/atomic"
+	"sync"
 	"syscall"
+	"time"
 )
 
 func NewApp() fx.Option {
@@ -33,16 +40,19 @@ func NewApp() fx.Option {
 		MutualContextModule(),
 		config.Module,
 		LoggerModule(),
-		StartCheckModule(),
-		PostgresModule(),
-		RedisModule(),
+		TracingModule(),
+		MetricsModule(),
 		ShortURLGeneratorModule(),
-		storage.Module,
+		StorageModule(),
+		RateLimitModule(),
 		middleware.Module,
-		service.Module,
+		ServiceModule(),
 		v1.Module,
 		HTTPServerModule(),
 		GRPCServerModule(),
+		PurgeJobModule(),
+		// must follow server modules, so readiness fails before servers stop
+		HealthModule(),
 
 		CheckInitializedModules(),
 	)
@@ -61,45 +71,187 @@ func LoggerModule() fx.Option {
 	)
 }
 
-func StartCheckModule() fx.Option {
-	return fx.Module("start with atomic",
-		fx.Provide(func() *atomic.Bool {
-			return &atomic.Bool{}
+// HealthModule checks dependencies for readiness probes. On stop the probes
+// start failing and servers keep serving for the shutdown delay, so traffic is drained.
+func HealthModule() fx.Option {
+	return fx.Module("health",
+		fx.Provide(
+			func(cfg *config.Config) health.Config {
+				return cfg.Health
+			},
+			func(cfg health.Config, appCfg *config.Config, storage *storage.Storage) *health.Health {
+				h := health.New(cfg)
+				h.Register(appCfg.DBType, health.CheckFunc(storage.Ping))
+				return h
+			},
+		),
+		fx.Invoke(func(lc fx.Lifecycle, h *health.Health, cfg health.Config) {
+			lc.Append(fx.Hook{
+				OnStop: func(ctx context.Context) error {
+					h.Shutdown()
+					select {
+					case <-time.After(cfg.ShutdownDelay):
+						return nil
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				},
+			})
 		}),
 	)
 }
 
-func PostgresModule() fx.Option {
-	return fx.Module("postgres",
+func StorageModule() fx.Option {
+	return fx.Module("storage",
 		fx.Provide(
-			func(cfg *config.Config) postgres.Config {
-				return cfg.Postgres
+			func(cfg *config.Config) storage.Config {
+				return storage.Config{
+					DBType:   cfg.DBType,
+					Postgres: cfg.Postgres,
+					Redis:    cfg.Redis,
+					Cache:    cfg.Cache,
+				}
+			},
+			func(ctx context.Context, lc fx.Lifecycle, cfg storage.Config, metrics *metrics.Metrics, logger logger.Logger) (*storage.Storage, error) {
+				repo, err := storage.NewStorage(ctx, lc, cfg, logger)
+				if err != nil {
+					return repo, err
+				}
+				return storage.Instrument(repo, cfg.DBType, metrics), nil
 			},
-			postgres.New,
 		),
-		fx.Invoke(func(lc fx.Lifecycle, pg *postgres.Postgres) {
+	)
+}
+
+// TracingModule sets the global tracer provider, spans left
+// in the batch are flushed after servers are stopped.
+func TracingModule() fx.Option {
+	return fx.Module("tracing",
+		fx.Provide(
+			func(cfg *config.Config) tracing.Config {
+				return cfg.Tracing
+			},
+		),
+		fx.Invoke(func(ctx context.Context, lc fx.Lifecycle, cfg tracing.Config) error {
+			shutdown, err := tracing.New(ctx, cfg)
+			if err != nil {
+				return err
+			}
 			lc.Append(fx.Hook{
-				OnStop: func(ctx context.Context) error {
-					pg.Close()
-					return nil
-				},
+				OnStop: shutdown,
 			})
+			return nil
+		}),
+	)
+}
+
+// MetricsModule collects metrics exposed at /services/metrics.
+func MetricsModule() fx.Option {
+	return fx.Module("metrics",
+		fx.Provide(metrics.New),
+		fx.Invoke(func(m *metrics.Metrics, services *service.Services, storage *storage.Storage) error {
+			if err := m.Register(metrics.NewAliasCollector(services.URL.Stats)); err != nil {
+				return err
+			}
+			if storage.Postgres != nil {
+				return m.Register(metrics.NewPgxPoolCollector(storage.Postgres.Pool.Stat))
+			}
+			return nil
 		}),
 	)
 }
 
-func RedisModule() fx.Option {
-	return fx.Module("redis",
+// RateLimitModule limits requests per client, the redis backend
+// opens its own connection since the storage may be another database.
+func RateLimitModule() fx.Option {
+	return fx.Module("rate limit",
 		fx.Provide(
-			func(cfg *config.Config) redis.Config {
-				return cfg.Redis
+			func(cfg *config.Config) ratelimit.Config {
+				return cfg.RateLimit
+			},
+			func(ctx context.Context, lc fx.Lifecycle, cfg ratelimit.Config, appCfg *config.Config) (ratelimit.Limiter, error) {
+				if err := cfg.Validate(); err != nil {
+					return nil, err
+				}
+				if !cfg.Enabled {
+					return ratelimit.Unlimited{}, nil
+				}
+				if cfg.Backend == ratelimit.BackendMemory {
+					return ratelimit.NewMemoryLimiter(cfg.Rate, cfg.Burst), nil
+				}
+
+				db, err := redisdb.New(ctx, appCfg.Redis)
+				if err != nil {
+					return nil, err
+				}
+				lc.Append(fx.Hook{
+					OnStop: func(ctx context.Context) error {
+						return db.Close()
+					},
+				})
+
+				return ratelimit.NewRedisLimiter(db, cfg.Rate, cfg.Burst), nil
 			},
-			redis.New,
 		),
-		fx.Invoke(func(lc fx.Lifecycle, rdb *redis.Redis) {
+	)
+}
+
+func ServiceModule() fx.Option {
+	return fx.Module("services",
+		fx.Provide(
+			// aliases are validated with the alphabet and the length they are generated with
+			func(cfg *config.Config) (service.Config, error) {
+				alphabet, err := cfg.Generator.Validate()
+				if err != nil {
+					return service.Config{}, err
+				}
+				if err = cfg.ClickService.Validate(); err != nil {
+					return service.Config{}, err
+				}
+				urlCfg := cfg.URLService
+				urlCfg.Alphabet, urlCfg.AliasLength = alphabet, cfg.Generator.Length
+
+				return service.Config{
+					URL:    urlCfg,
+					Click:  cfg.ClickService,
+					Auth:   cfg.Auth,
+					Domain: cfg.DomainRules,
+				}, nil
+			},
+			service.NewServices,
+		),
+		fx.Invoke(func(lc fx.Lifecycle, services *service.Services) {
+			ctx, cancel := context.WithCancel(context.Background())
+			var wg sync.WaitGroup
+			done := make(chan struct{})
+
 			lc.Append(fx.Hook{
-				OnStop: func(ctx context.Context) error {
-					return rdb.Close()
+				OnStart: func(context.Context) error {
+					wg.Add(2)
+					go func() {
+						defer wg.Done()
+						services.Click.Run(ctx)
+					}()
+					go func() {
+						defer wg.Done()
+						services.Domain.Run(ctx)
+					}()
+					go func() {
+						wg.Wait()
+						close(done)
+					}()
+					return nil
+				},
+				// servers are stopped before, so no clicks are recorded while the buffer is drained
+				// storage is stopped after, so a running reload of domain rules does not use closed storage
+				OnStop: func(stopCtx context.Context) error {
+					cancel()
+					select {
+					case <-done:
+						return nil
+					case <-stopCtx.Done():
+						return stopCtx.Err()
+					}
 				},
 			})
 		}),
@@ -121,12 +273,15 @@ func MutualContextModule() fx.Option {
 func ShortURLGeneratorModule() fx.Option {
 	return fx.Module("generator",
 		fx.Provide(
-			fx.Annotate(
-				generator.NewGen,
-				fx.As(new(generator.Generator)),
-			),
-			func() int {
-				return constant.AliasLength
+			// generated aliases fit the length accepted by the url service
+			func(cfg *config.Config) generator.Config {
+				genCfg := cfg.Generator
+				genCfg.MaxLength = cfg.URLService.MaxAliasLength
+				return genCfg
+			},
+			// counter based strategies take numbers from the chosen storage
+			func(cfg generator.Config, repo *storage.Storage) (generator.Generator, error) {
+				return generator.New(cfg, generator.CounterFunc(repo.Sequence.NextAliasID))
 			},
 		),
 	)
@@ -138,6 +293,9 @@ func HTTPServerModule() fx.Option {
 			func(cfg *config.Config) httpserver.Config {
 				return cfg.HTTPServer
 			},
+			func(cfg *config.Config) redirectroute.Config {
+				return cfg.Redirect
+			},
 			httpserver.NewServer,
 		),
 		fx.Invoke(func(lc fx.Lifecycle, srv *httpserver.Server, cfg httpserver.Config, logger logger.Logger) {
@@ -165,16 +323,51 @@ func GRPCServerModule() fx.Option {
 			func(cfg *config.Config) grpcserver.Config {
 				return cfg.GRPCServer
 			},
-			func(logger logger.Logger) []grpc.ServerOption {
+			func(logger logger.Logger, services *service.Services, limiter ratelimit.Limiter, metrics *metrics.Metrics) []grpc.ServerOption {
 				return []grpc.ServerOption{
-					grpc.UnaryInterceptor(interceptor.LoggingInterceptor(logger)),
+					grpc.ChainUnaryInterceptor(
+						interceptor.TracingInterceptor(),
+						interceptor.LoggingInterceptor(logger, metrics),
+						interceptor.IPRateLimitInterceptor(limiter, logger),
+						interceptor.AuthInterceptor(services.Auth, anonymousMethods()),
+						interceptor.KeyRateLimitInterceptor(limiter, logger),
+						interceptor.AdminInterceptor(services.Auth, domaingrpc.AdminMethods),
+					),
 				}
 			},
-			grpcserver.NewServer,
+			// fx does not fill variadic parameters, so options are passed explicitly
+			func(cfg grpcserver.Config, opts []grpc.ServerOption) *grpcserver.Server {
+				return grpcserver.NewServer(cfg, opts...)
+			},
 		),
 		fx.Invoke(
 			func(srv *grpcserver.Server, services *service.Services) {
-				urlgrpc.Register(srv.Srv, services.URL)
+				urlgrpc.Register(srv.Srv, services.URL, services.Click)
+				domaingrpc.Register(srv.Srv, services.Domain)
+			},
+			// health statuses follow readiness checks, Watch streams get changes once per cache ttl
+			func(lc fx.Lifecycle, srv *grpcserver.Server, h *health.Health, cfg health.Config) {
+				srv.Health.SetCheck(func(ctx context.Context) bool {
+					return h.Check(ctx).OK()
+				})
+
+				interval := cfg.CacheTTL
+				if interval <= 0 {
+					interval = time.Second
+				}
+				ctx, cancel := context.WithCancel(context.Background())
+
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error {
+						srv.Health.Update(ctx)
+						go srv.Health.Run(ctx, interval)
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						cancel()
+						return nil
+					},
+				})
 			},
 			func(lc fx.Lifecycle, srv *grpcserver.Server, cfg grpcserver.Config, logger logger.Logger) {
 				lc.Append(fx.Hook{
@@ -196,17 +389,59 @@ func GRPCServerModule() fx.Option {
 	)
 }
 
+// anonymousMethods adds health checks to url methods callable without an api key.
+func anonymousMethods() map[string]bool {
+	methods := maps.Clone(urlgrpc.AnonymousMethods)
+	methods[grpcserver.HealthCheckMethod] = true
+	return methods
+}
+
+// PurgeJobModule removes expired urls in background
+// unless the storage expires them by itself or the job is disabled.
+func PurgeJobModule() fx.Option {
+	return fx.Module("purge job",
+		fx.Provide(
+			func(cfg *config.Config) purgejob.Config {
+				return cfg.PurgeJob
+			},
+		),
+		fx.Invoke(func(lc fx.Lifecycle, cfg purgejob.Config, storage *storage.Storage, logger logger.Logger) {
+			if storage.Purger == nil || cfg.Interval <= 0 {
+				return
+			}
+
+			job := purgejob.NewJob(cfg, storage.Purger, logger)
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					go func() {
+						defer close(done)
+						job.Run(ctx)
+					}()
+					return nil
+				},
+				OnStop: func(stopCtx context.Context) error {
+					cancel()
+					select {
+					case <-done:
+						return nil
+					case <-stopCtx.Done():
+						return stopCtx.Err()
+					}
+				},
+			})
+		}),
+	)
+}
+
 func CheckInitializedModules() fx.Option {
 	return fx.Module("check modules",
 		fx.Invoke(
 			func(cfg *config.Config) {},
 			func(logger logger.Logger) {},
-			func(ok *atomic.Bool, pg *postgres.Postgres) {
-				if pg.Pool != nil {
-					ok.Store(true)
-				}
-			},
-			func(storage *storage.Storage) {},
+			func(h *health.Health) {},
 			func(mw *middleware.MW) {},
 			func(service *service.Services) {},
 			func(h http.Handler) {},
@@ -1,12 +1,335 @@
 package app
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	urlpb "github.com/romandnk/shortener/api/url/pb"
+	"github.com/romandnk/shortener/internal/service"
+	"github.com/romandnk/shortener/pkg/health"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func TestValidateApp(t *testing.T) {
 	err := fx.ValidateApp(NewApp())
 	require.NoError(t, err)
 }
+
+func freePort(t *testing.T) int {
+	lsn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lsn.Close()
+
+	return lsn.Addr().(*net.TCPAddr).Port
+}
+
+func TestApp_MemoryStorage(t *testing.T) {
+	httpPort := freePort(t)
+	grpcPort := freePort(t)
+
+	t.Setenv("CONFIG_PATH", "../../config/config.yaml")
+	t.Setenv("DB_TYPE", "memory")
+	t.Setenv("CLICK_SERVICE_IP_SECRET", "secret")
+	t.Setenv("AUTH_ENABLED", "false")
+	t.Setenv("HTTP_SERVER_HOST", "127.0.0.1")
+	t.Setenv("HTTP_SERVER_PORT", strconv.Itoa(httpPort))
+	t.Setenv("GRPC_SERVER_HOST", "127.0.0.1")
+	t.Setenv("GRPC_SERVER_PORT", strconv.Itoa(grpcPort))
+
+	app := fxtest.New(t, NewApp())
+	app.RequireStart()
+	defer app.RequireStop()
+
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d/api/v1/urls/", httpPort)
+
+	body, err := json.Marshal(map[string]string{"original_url": "https://google.com"})
+	require.NoError(t, err)
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Post(baseURL, "application/json", bytes.NewReader(body))
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created struct {
+		Alias string `json:"alias"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	require.NotEmpty(t, created.Alias)
+
+	resp, err = http.Get(baseURL + created.Alias)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err = client.Get(fmt.Sprintf("http://127.0.0.1:%d/%s", httpPort, created.Alias))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	require.Equal(t, "https://google.com/", resp.Header.Get("Location"))
+
+	conn, err := grpc.Dial(net.JoinHostPort("127.0.0.1", strconv.Itoa(grpcPort)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	grpcClient := urlpb.NewEventServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := grpcClient.GetOriginalByAlias(ctx, &urlpb.GetOriginalByAliasRequest{Alias: created.Alias}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	require.Equal(t, "https://google.com/", res.GetOriginal())
+
+	// http get, redirect and grpc resolutions are written asynchronously
+	require.Eventually(t, func() bool {
+		stats, err := grpcClient.GetURLStats(ctx, &urlpb.GetURLStatsRequest{Alias: created.Alias})
+		return err == nil && stats.GetTotal() == 3 && len(stats.GetDaily()) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	resp, err = http.Get(fmt.Sprintf("http://127.0.0.1:%d/services/metrics", httpPort))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	metrics, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(metrics), `shortener_http_requests_total{code="201",method="POST",route="/api/v1/urls/"} 1`)
+	require.Contains(t, string(metrics), `shortener_grpc_requests_total{code="OK",method="/url.EventService/GetOriginalByAlias"} 1`)
+	require.Contains(t, string(metrics), `shortener_storage_operation_duration_seconds_count{backend="memory",operation="CreateURL",status="ok"} 1`)
+	require.Contains(t, string(metrics), `shortener_alias_generated_total 1`)
+}
+
+func TestApp_Auth(t *testing.T) {
+	httpPort := freePort(t)
+	grpcPort := freePort(t)
+
+	t.Setenv("CONFIG_PATH", "../../config/config.yaml")
+	t.Setenv("DB_TYPE", "memory")
+	t.Setenv("CLICK_SERVICE_IP_SECRET", "secret")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("HTTP_SERVER_HOST", "127.0.0.1")
+	t.Setenv("HTTP_SERVER_PORT", strconv.Itoa(httpPort))
+	t.Setenv("GRPC_SERVER_HOST", "127.0.0.1")
+	t.Setenv("GRPC_SERVER_PORT", strconv.Itoa(grpcPort))
+
+	var services *service.Services
+	app := fxtest.New(t, NewApp(), fx.Populate(&services))
+	app.RequireStart()
+	defer app.RequireStop()
+
+	key, err := services.Auth.CreateKey(context.Background(), "test")
+	require.NoError(t, err)
+
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d/api/v1/urls/", httpPort)
+
+	body, err := json.Marshal(map[string]string{"original_url": "https://google.com"})
+	require.NoError(t, err)
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Post(baseURL, "application/json", bytes.NewReader(body))
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodPost, baseURL, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+key)
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created struct {
+		Alias string `json:"alias"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+
+	// resolving stays anonymous
+	resp, err = http.Get(baseURL + created.Alias)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	conn, err := grpc.Dial(net.JoinHostPort("127.0.0.1", strconv.Itoa(grpcPort)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	grpcClient := urlpb.NewEventServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = grpcClient.DeleteByAlias(ctx, &urlpb.DeleteByAliasRequest{Alias: created.Alias}, grpc.WaitForReady(true))
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	res, err := grpcClient.GetOriginalByAlias(ctx, &urlpb.GetOriginalByAliasRequest{Alias: created.Alias})
+	require.NoError(t, err)
+	require.Equal(t, "https://google.com/", res.GetOriginal())
+
+	_, err = grpcClient.DeleteByAlias(metadata.AppendToOutgoingContext(ctx, "x-api-key", key),
+		&urlpb.DeleteByAliasRequest{Alias: created.Alias})
+	require.NoError(t, err)
+}
+
+func TestApp_RateLimit(t *testing.T) {
+	httpPort := freePort(t)
+	grpcPort := freePort(t)
+
+	t.Setenv("CONFIG_PATH", "../../config/config.yaml")
+	t.Setenv("DB_TYPE", "memory")
+	t.Setenv("CLICK_SERVICE_IP_SECRET", "secret")
+	t.Setenv("AUTH_ENABLED", "false")
+	t.Setenv("RATE_LIMIT_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_BACKEND", "memory")
+	t.Setenv("RATE_LIMIT_RATE", "0.01")
+	t.Setenv("RATE_LIMIT_BURST", "2")
+	t.Setenv("HTTP_SERVER_HOST", "127.0.0.1")
+	t.Setenv("HTTP_SERVER_PORT", strconv.Itoa(httpPort))
+	t.Setenv("GRPC_SERVER_HOST", "127.0.0.1")
+	t.Setenv("GRPC_SERVER_PORT", strconv.Itoa(grpcPort))
+
+	app := fxtest.New(t, NewApp())
+	app.RequireStart()
+	defer app.RequireStop()
+
+	aliasURL := fmt.Sprintf("http://127.0.0.1:%d/api/v1/urls/testtest11", httpPort)
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	require.Eventually(t, func() bool {
+		resp, err = http.Get(aliasURL)
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+	resp.Body.Close()
+	require.NotEqual(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	resp, err = http.Get(aliasURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.NotEqual(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	resp, err = http.Get(aliasURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.NotEmpty(t, resp.Header.Get("Retry-After"))
+
+	conn, err := grpc.Dial(net.JoinHostPort("127.0.0.1", strconv.Itoa(grpcPort)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// http and grpc requests of one client share the bucket
+	var header metadata.MD
+	_, err = urlpb.NewEventServiceClient(conn).GetOriginalByAlias(ctx,
+		&urlpb.GetOriginalByAliasRequest{Alias: "testtest11"}, grpc.WaitForReady(true), grpc.Header(&header))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.NotEmpty(t, header.Get("retry-after"))
+}
+
+func TestApp_Readiness(t *testing.T) {
+	httpPort := freePort(t)
+	grpcPort := freePort(t)
+
+	t.Setenv("CONFIG_PATH", "../../config/config.yaml")
+	t.Setenv("DB_TYPE", "memory")
+	t.Setenv("CLICK_SERVICE_IP_SECRET", "secret")
+	t.Setenv("HEALTH_SHUTDOWN_DELAY", "1s")
+	t.Setenv("HTTP_SERVER_HOST", "127.0.0.1")
+	t.Setenv("HTTP_SERVER_PORT", strconv.Itoa(httpPort))
+	t.Setenv("GRPC_SERVER_HOST", "127.0.0.1")
+	t.Setenv("GRPC_SERVER_PORT", strconv.Itoa(grpcPort))
+
+	app := fxtest.New(t, NewApp())
+	app.RequireStart()
+
+	readinessURL := fmt.Sprintf("http://127.0.0.1:%d/services/readiness", httpPort)
+
+	readiness := func() (int, health.Report) {
+		resp, err := http.Get(readinessURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var report health.Report
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		return resp.StatusCode, report
+	}
+
+	var (
+		code   int
+		report health.Report
+	)
+	require.Eventually(t, func() bool {
+		code, report = readiness()
+		return code == http.StatusOK
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, health.Report{
+		Status: health.StatusOK,
+		Components: map[string]health.ComponentStatus{
+			"memory": {Status: health.StatusOK},
+		},
+	}, report)
+
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", grpcPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// health checks need no api key
+	healthResp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: urlpb.EventService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, healthResp.GetStatus())
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		app.RequireStop()
+	}()
+
+	require.Eventually(t, func() bool {
+		code, report = readiness()
+		return code == http.StatusServiceUnavailable
+	}, time.Second, 50*time.Millisecond)
+	require.Equal(t, health.StatusShuttingDown, report.Status)
+
+	healthResp, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthResp.GetStatus())
+
+	<-stopped
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/romandnk/shortener/config"
+	"github.com/romandnk/shortener/internal/metrics"
+	zaplogger "github.com/romandnk/shortener/pkg/logger/zap"
+	"go.uber.org/fx"
+	"io"
+)
+
+const usage = `usage: app [command] [flags]
+
+commands:
+  serve    run HTTP and gRPC servers, the default command
+  migrate  manage the postgres schema
+  create   shorten a url
+  resolve  print the original url of an alias
+  delete   delete an alias
+  export   print links as JSON lines
+  import   create links from JSON lines
+  keys     manage api keys
+
+run "app <command>" without flags to see its usage`
+
+var errUsage = errors.New(usage)
+
+// Run runs the command given by args without the program name, no command serves requests.
+func Run(args []string, in io.Reader, out io.Writer) error {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	switch args[0] {
+	case "serve":
+		fx.New(NewApp()).Run()
+		return nil
+	case "migrate":
+		return RunMigrate(args[1:], out)
+	case "keys":
+		return RunKeys(args[1:], out)
+	case "create", "resolve", "delete", "export", "import":
+		return RunLinks(args, in, out)
+	case "help", "-h", "-help", "--help":
+		_, err := fmt.Fprintln(out, usage)
+		return err
+	default:
+		return fmt.Errorf("unknown command %q\n\n%w", args[0], errUsage)
+	}
+}
+
+// runCommandApp builds the modules needed by one-off commands, which read the built values with fx.Populate,
+// and calls run between starting and stopping the app, so that storage connections are closed afterwards.
+// Servers are not included, so only background jobs of the services run.
+func runCommandApp(run func() error, opts ...fx.Option) error {
+	app := fx.New(append([]fx.Option{
+		fx.NopLogger,
+		MutualContextModule(),
+		config.Module,
+		LoggerModule(),
+		fx.Provide(metrics.New),
+		ShortURLGeneratorModule(),
+		StorageModule(),
+		ServiceModule(),
+		// logs go to stderr to keep them apart from the printed results
+		fx.Decorate(func(cfg zaplogger.Config) zaplogger.Config {
+			cfg.OutputPaths = []string{"stderr"}
+			return cfg
+		}),
+	}, opts...)...)
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	startCtx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+	if err := app.Start(startCtx); err != nil {
+		return err
+	}
+
+	err := run()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.StopTimeout())
+	defer cancel()
+
+	return errors.Join(err, app.Stop(stopCtx))
+}
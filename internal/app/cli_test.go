@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"testing"
+)
+
+func TestRunCommandApp(t *testing.T) {
+	t.Setenv("CONFIG_PATH", "../../config/config.yaml")
+	t.Setenv("DB_TYPE", "memory")
+	t.Setenv("CLICK_SERVICE_IP_SECRET", "secret")
+	t.Setenv("HTTP_SERVER_HOST", "127.0.0.1")
+	t.Setenv("HTTP_SERVER_PORT", "8080")
+	t.Setenv("GRPC_SERVER_HOST", "127.0.0.1")
+	t.Setenv("GRPC_SERVER_PORT", "9090")
+
+	var started, stopped bool
+	hooks := fx.Invoke(func(lc fx.Lifecycle) {
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				started = true
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				stopped = true
+				return nil
+			},
+		})
+	})
+
+	errRun := errors.New("run failed")
+	err := runCommandApp(func() error {
+		require.True(t, started)
+		require.False(t, stopped)
+		return errRun
+	}, hooks)
+	require.ErrorIs(t, err, errRun)
+	require.True(t, stopped)
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/romandnk/shortener/internal/service"
+	"go.uber.org/fx"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+const keysUsage = `usage: keys <command> [flags]
+
+commands:
+  create -name <name>  create a new api key, the key is printed once
+  list                 list api key names
+  revoke -name <name>  revoke the api key`
+
+var errKeysUsage = errors.New(keysUsage)
+
+// RunKeys manages api keys in the configured storage.
+func RunKeys(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errKeysUsage
+	}
+
+	var services *service.Services
+	return runCommandApp(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return runKeys(ctx, services.Auth, args, out)
+	}, fx.Populate(&services))
+}
+
+func runKeys(ctx context.Context, auth service.Auth, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("keys "+args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	name := fs.String("name", "", "api key name")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n\n%s", err, keysUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		key, err := auth.CreateKey(ctx, *name)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, key)
+		return err
+	case "list":
+		keys, err := auth.ListKeys(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tCREATED AT")
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s\t%s\n", key.Name, key.CreatedAt.Format(time.RFC3339))
+		}
+		return w.Flush()
+	case "revoke":
+		return auth.RevokeKey(ctx, *name)
+	default:
+		return errKeysUsage
+	}
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	mock_service "github.com/romandnk/shortener/internal/service/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestRunKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auth := mock_service.NewMockAuth(ctrl)
+	ctx := context.Background()
+
+	auth.EXPECT().CreateKey(ctx, "ci").Return("sk_key", nil)
+	var out bytes.Buffer
+	require.NoError(t, runKeys(ctx, auth, []string{"create", "-name", "ci"}, &out))
+	require.Equal(t, "sk_key\n", out.String())
+
+	auth.EXPECT().ListKeys(ctx).Return([]entity.APIKey{
+		{Name: "ci", CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+	out.Reset()
+	require.NoError(t, runKeys(ctx, auth, []string{"list"}, &out))
+	require.Equal(t, "NAME  CREATED AT\nci    2030-01-01T00:00:00Z\n", out.String())
+
+	auth.EXPECT().RevokeKey(ctx, "ci").Return(nil)
+	require.NoError(t, runKeys(ctx, auth, []string{"revoke", "-name", "ci"}, &out))
+
+	require.ErrorIs(t, runKeys(ctx, auth, []string{"rotate"}, &out), errKeysUsage)
+}
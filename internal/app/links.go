@@ -0,0 +1,201 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/romandnk/shortener/config"
+	"github.com/romandnk/shortener/internal/entity"
+	"github.com/romandnk/shortener/internal/service"
+	urlservice "github.com/romandnk/shortener/internal/service/url"
+	"go.uber.org/fx"
+	"io"
+	"time"
+)
+
+const linksUsage = `usage: <command> [flags]
+
+commands:
+  create -url <url> [-alias <alias>] [-ttl <duration> | -expires-at <RFC 3339 time>]
+                          shorten the url and print its alias
+  resolve -alias <alias>  print the original url of the alias
+  delete -alias <alias>   delete the alias
+  export [-page-size <n>] print not expired links as JSON lines
+  import                  create links from JSON lines read from stdin, the alias, input and expires_at are optional`
+
+var errLinksUsage = errors.New(linksUsage)
+
+// linkRecord is a link in export and import streams.
+type linkRecord struct {
+	Original  string     `json:"original"`
+	Alias     string     `json:"alias,omitempty"`
+	Input     string     `json:"input,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// RunLinks manages links with the url service over the configured storage.
+func RunLinks(args []string, in io.Reader, out io.Writer) error {
+	if len(args) == 0 {
+		return errLinksUsage
+	}
+
+	var (
+		ctx      context.Context
+		cfg      *config.Config
+		services *service.Services
+	)
+	return runCommandApp(func() error {
+		return runLinks(ctx, services.URL, cfg.URLService.MaxBatchSize, args, in, out)
+	}, fx.Populate(&ctx, &cfg, &services))
+}
+
+func runLinks(ctx context.Context, url service.URL, batchSize int, args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	original := fs.String("url", "", "original url")
+	alias := fs.String("alias", "", "url alias")
+	ttl := fs.Duration("ttl", 0, "time to live of the link")
+	expiresAt := fs.String("expires-at", "", "expiration time of the link")
+	pageSize := fs.Int("page-size", 1000, "number of links read at once")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n\n%s", err, linksUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		var expiration time.Time
+		if *expiresAt != "" {
+			var err error
+			expiration, err = time.Parse(time.RFC3339, *expiresAt)
+			if err != nil {
+				return fmt.Errorf("invalid expiration time: %w", err)
+			}
+		}
+		expiration, err := urlservice.ExpirationTime(expiration, *ttl)
+		if err != nil {
+			return err
+		}
+
+		created, _, err := url.CreateURLAlias(ctx, entity.URL{
+			Original:  *original,
+			Alias:     *alias,
+			ExpiresAt: expiration,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, created)
+		return err
+	case "resolve":
+		original, err := url.GetOriginalByAlias(ctx, *alias)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, original)
+		return err
+	case "delete":
+		return url.DeleteByAlias(ctx, *alias)
+	case "export":
+		return exportLinks(ctx, url, *pageSize, out)
+	case "import":
+		return importLinks(ctx, url, batchSize, in, out)
+	default:
+		return errLinksUsage
+	}
+}
+
+func exportLinks(ctx context.Context, url service.URL, pageSize int, out io.Writer) error {
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+
+	cursor := ""
+	for {
+		urls, next, err := url.ListURLs(ctx, cursor, pageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range urls {
+			record := linkRecord{Original: u.Original, Alias: u.Alias, Input: u.Input}
+			if !u.ExpiresAt.IsZero() {
+				expiresAt := u.ExpiresAt.UTC()
+				record.ExpiresAt = &expiresAt
+			}
+			if err = enc.Encode(record); err != nil {
+				return err
+			}
+		}
+
+		if next == "" {
+			return w.Flush()
+		}
+		cursor = next
+	}
+}
+
+// importLinks creates links in batches, failed links are reported and do not stop the import.
+func importLinks(ctx context.Context, url service.URL, batchSize int, in io.Reader, out io.Writer) error {
+	dec := json.NewDecoder(in)
+
+	var (
+		batch           []entity.URL
+		total, imported int
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		results, err := url.CreateURLAliasBatch(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for i, res := range results {
+			if res.Err != nil {
+				fmt.Fprintf(out, "link %d (%s): %v\n", total-len(batch)+i+1, batch[i].Original, res.Err)
+				continue
+			}
+			imported++
+		}
+
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		var record linkRecord
+		err := dec.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("link %d: %w", total+1, err)
+		}
+
+		// the submitted input is normalized again, so it is kept as it was on export
+		u := entity.URL{Original: record.Original, Alias: record.Alias}
+		if record.Input != "" {
+			u.Original = record.Input
+		}
+		if record.ExpiresAt != nil {
+			u.ExpiresAt = *record.ExpiresAt
+		}
+		batch = append(batch, u)
+		total++
+
+		if len(batch) == batchSize {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "imported %d of %d links\n", imported, total)
+	return err
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	mock_service "github.com/romandnk/shortener/internal/service/mock"
+	urlservice "github.com/romandnk/shortener/internal/service/url"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunLinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	url := mock_service.NewMockURL(ctrl)
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	url.EXPECT().CreateURLAlias(ctx, entity.URL{
+		Original:  "https://google.com",
+		Alias:     "google",
+		ExpiresAt: expiresAt,
+	}).Return("google", false, nil)
+	var out bytes.Buffer
+	require.NoError(t, runLinks(ctx, url, 2, []string{
+		"create", "-url", "https://google.com", "-alias", "google", "-expires-at", "2030-01-01T00:00:00Z",
+	}, nil, &out))
+	require.Equal(t, "google\n", out.String())
+
+	err := runLinks(ctx, url, 2, []string{
+		"create", "-url", "https://google.com", "-ttl", "1h", "-expires-at", "2030-01-01T00:00:00Z",
+	}, nil, &out)
+	require.ErrorIs(t, err, urlservice.ErrExpirationConflict)
+
+	url.EXPECT().GetOriginalByAlias(ctx, "google").Return("https://google.com", nil)
+	out.Reset()
+	require.NoError(t, runLinks(ctx, url, 2, []string{"resolve", "-alias", "google"}, nil, &out))
+	require.Equal(t, "https://google.com\n", out.String())
+
+	url.EXPECT().DeleteByAlias(ctx, "google").Return(urlservice.ErrOriginalURLNotFound)
+	err = runLinks(ctx, url, 2, []string{"delete", "-alias", "google"}, nil, &out)
+	require.ErrorIs(t, err, urlservice.ErrOriginalURLNotFound)
+
+	require.ErrorIs(t, runLinks(ctx, url, 2, []string{"rename"}, nil, &out), errLinksUsage)
+}
+
+func TestRunLinks_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	url := mock_service.NewMockURL(ctrl)
+	ctx := context.Background()
+
+	gomock.InOrder(
+		url.EXPECT().ListURLs(ctx, "", 2).Return([]entity.URL{
+			{Original: "https://google.com", Alias: "google"},
+			{Original: "https://ya.ru", Alias: "yandex", ExpiresAt: time.Date(2030, 1, 1, 3, 0, 0, 0, time.FixedZone("MSK", 3*3600))},
+		}, "yandex", nil),
+		url.EXPECT().ListURLs(ctx, "yandex", 2).Return([]entity.URL{
+			{Original: "https://go.dev/", Alias: "golang", Input: "HTTPS://Go.dev"},
+		}, "", nil),
+	)
+
+	var out bytes.Buffer
+	require.NoError(t, runLinks(ctx, url, 2, []string{"export", "-page-size", "2"}, nil, &out))
+	require.Equal(t, `{"original":"https://google.com","alias":"google"}
+{"original":"https://ya.ru","alias":"yandex","expires_at":"2030-01-01T00:00:00Z"}
+{"original":"https://go.dev/","alias":"golang","input":"HTTPS://Go.dev"}
+`, out.String())
+
+	url.EXPECT().ListURLs(ctx, "", 1000).Return(nil, "", urlservice.ErrInternalError)
+	require.ErrorIs(t, runLinks(ctx, url, 2, []string{"export"}, nil, &out), urlservice.ErrInternalError)
+}
+
+func TestRunLinks_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	url := mock_service.NewMockURL(ctrl)
+	ctx := context.Background()
+
+	in := strings.NewReader(`{"original":"https://google.com","alias":"google"}
+{"original":"https://ya.ru","expires_at":"2030-01-01T00:00:00Z"}
+{"original":"https://go.dev/","input":"HTTPS://Go.dev"}
+`)
+
+	gomock.InOrder(
+		url.EXPECT().CreateURLAliasBatch(ctx, []entity.URL{
+			{Original: "https://google.com", Alias: "google"},
+			{Original: "https://ya.ru", ExpiresAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}).Return([]urlservice.CreateResult{
+			{Alias: "google"},
+			{Err: errors.New("original url already exists")},
+		}, nil),
+		url.EXPECT().CreateURLAliasBatch(ctx, []entity.URL{
+			{Original: "HTTPS://Go.dev"},
+		}).Return([]urlservice.CreateResult{{Alias: "abcdefghig"}}, nil),
+	)
+
+	var out bytes.Buffer
+	require.NoError(t, runLinks(ctx, url, 2, []string{"import"}, in, &out))
+	require.Equal(t, "link 2 (https://ya.ru): original url already exists\nimported 2 of 3 links\n", out.String())
+
+	err := runLinks(ctx, url, 2, []string{"import"}, strings.NewReader("{"), &out)
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/romandnk/shortener/config"
+	"github.com/romandnk/shortener/migrations"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+	"go.uber.org/fx"
+	"io"
+)
+
+const migrateUsage = `usage: migrate <command> [flags]
+
+commands:
+  up                    apply all pending migrations
+  down [-steps <n>]     roll back n migrations, 1 by default, 0 rolls back all
+  version               print the schema version
+  force -version <v>    set the schema version and clear the dirty flag without running migrations`
+
+var errMigrateUsage = errors.New(migrateUsage)
+
+type migrator interface {
+	Up() error
+	Down(steps int) error
+	Version() (uint64, bool, error)
+	Force(version uint64) error
+	Latest() uint64
+}
+
+// RunMigrate manages the Postgres schema with the migrations embedded into the binary.
+func RunMigrate(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	var (
+		ctx context.Context
+		cfg *config.Config
+	)
+	app := fx.New(
+		fx.NopLogger,
+		MutualContextModule(),
+		config.Module,
+		fx.Populate(&ctx, &cfg),
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	db, err := postgres.New(ctx, cfg.Postgres)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m, err := postgres.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return runMigrate(m, args, out)
+}
+
+func runMigrate(m migrator, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	steps := fs.Int("steps", 1, "number of migrations to roll back")
+	version := fs.Uint64("version", 0, "schema version")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n\n%s", err, migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		if err := m.Up(); err != nil {
+			return err
+		}
+	case "down":
+		if err := m.Down(*steps); err != nil {
+			return err
+		}
+	case "version":
+	case "force":
+		// an omitted version must not wipe the schema version
+		forced := false
+		fs.Visit(func(f *flag.Flag) {
+			forced = forced || f.Name == "version"
+		})
+		if !forced {
+			return errMigrateUsage
+		}
+		if err := m.Force(*version); err != nil {
+			return err
+		}
+	default:
+		return errMigrateUsage
+	}
+
+	current, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "version: %d, latest: %d, dirty: %t\n", current, m.Latest(), dirty)
+	return err
+}
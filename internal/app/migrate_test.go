@@ -0,0 +1,63 @@
+package app
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type fakeMigrator struct {
+	version uint64
+	steps   int
+}
+
+func (m *fakeMigrator) Up() error {
+	m.version = m.Latest()
+	return nil
+}
+
+func (m *fakeMigrator) Down(steps int) error {
+	m.steps = steps
+	m.version -= uint64(steps)
+	return nil
+}
+
+func (m *fakeMigrator) Version() (uint64, bool, error) {
+	return m.version, false, nil
+}
+
+func (m *fakeMigrator) Force(version uint64) error {
+	m.version = version
+	return nil
+}
+
+func (m *fakeMigrator) Latest() uint64 {
+	return 4
+}
+
+func TestRunMigrate(t *testing.T) {
+	m := &fakeMigrator{version: 1}
+	var out bytes.Buffer
+
+	require.NoError(t, runMigrate(m, []string{"up"}, &out))
+	require.Equal(t, "version: 4, latest: 4, dirty: false\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runMigrate(m, []string{"down"}, &out))
+	require.Equal(t, "version: 3, latest: 4, dirty: false\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runMigrate(m, []string{"down", "-steps", "2"}, &out))
+	require.Equal(t, 2, m.steps)
+
+	out.Reset()
+	require.NoError(t, runMigrate(m, []string{"version"}, &out))
+	require.Equal(t, "version: 1, latest: 4, dirty: false\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runMigrate(m, []string{"force", "-version", "0"}, &out))
+	require.Equal(t, "version: 0, latest: 4, dirty: false\n", out.String())
+
+	require.ErrorIs(t, runMigrate(m, []string{"force"}, &out), errMigrateUsage)
+	require.ErrorIs(t, runMigrate(m, []string{"redo"}, &out), errMigrateUsage)
+}
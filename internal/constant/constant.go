@@ -2,18 +2,60 @@ package constant
 
 import "time"
 
-// length of short url alias
+// length of generated aliases unless alias_generator.length is set
 const AliasLength int = 10
 
+// longest alias the storage keeps, aliases are resolved regardless of the current length settings
+const MaxStoredAliasLength int = 128
+
 // db tables
 const (
-	URLSTable string = "urls"
+	URLSTable    string = "urls"
+	ClicksTable  string = "clicks"
+	APIKeysTable string = "api_keys"
+	DomainsTable string = "domain_rules"
 )
 
 // available databases
 const (
 	POSTGRES string = "postgres"
 	REDIS    string = "redis"
+	MEMORY   string = "memory"
 )
 
 const ZeroTTL time.Duration = 0
+
+// how long redis remembers that an expired alias existed
+const ExpiredAliasRetention time.Duration = 7 * 24 * time.Hour
+
+// redis keys of click analytics
+const (
+	ClicksKeyPrefix string = "clicks:"
+	ClicksStream    string = "clicks"
+	// approximate number of clicks kept in the redis stream
+	ClicksStreamMaxLen int64 = 1_000_000
+)
+
+// redis hashes of api keys, by name and by hash of the key
+const (
+	APIKeysByNameKey string = "api_keys:by_name"
+	APIKeysByHashKey string = "api_keys:by_hash"
+)
+
+// redis sets of domain rule patterns, by list
+const (
+	DomainBlockKey string = "domain_rules:block"
+	DomainAllowKey string = "domain_rules:allow"
+)
+
+// postgres sequence and redis counter of sequential aliases
+const AliasSequence string = "alias_seq"
+
+// redis keys of cached urls when postgres is the source of truth
+const URLCacheKeyPrefix string = "url_cache:"
+
+// how long invalidated aliases are not cached by reads, which may have got the original before the change
+const URLCacheInvalidationTTL time.Duration = 10 * time.Second
+
+// layout of days in click histograms
+const DayLayout string = "2006-01-02"
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// APIKey grants write access to the API, the key itself is never stored.
+type APIKey struct {
+	Name string
+	// Hash is a hex encoded sha256 of the key
+	Hash      string
+	CreatedAt time.Time
+}
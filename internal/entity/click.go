@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// Click is a single resolution of a short url alias.
+type Click struct {
+	Alias     string
+	ClickedAt time.Time
+	Referrer  string
+	UserAgent string
+	// IPHash is a salted hash of the client IP, raw addresses are never stored
+	IPHash string
+}
+
+type ClickStats struct {
+	Total int64
+	// Daily is sorted by day and contains only days with clicks
+	Daily []DailyClicks
+}
+
+type DailyClicks struct {
+	// Day is a UTC midnight
+	Day    time.Time
+	Clicks int64
+}
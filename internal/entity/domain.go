@@ -0,0 +1,16 @@
+package entity
+
+// DomainList is the list a domain rule belongs to.
+type DomainList string
+
+const (
+	DomainBlock DomainList = "block"
+	DomainAllow DomainList = "allow"
+)
+
+// DomainRule blocks or allows links to hosts matching the pattern, which is a host like example.com
+// or a wildcard like *.example.com matching example.com and all its subdomains.
+type DomainRule struct {
+	Pattern string
+	List    DomainList
+}
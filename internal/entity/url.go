@@ -1,6 +1,12 @@
 package entity
 
+import "time"
+
 type URL struct {
 	Original string
 	Alias    string
+	// Input is the url as it was submitted before normalization, empty if it equals Original
+	Input string
+	// ExpiresAt is zero if the url never expires
+	ExpiresAt time.Time
 }
@@ -0,0 +1,8 @@
+package purgejob
+
+import "time"
+
+type Config struct {
+	// Interval between removals of expired urls, zero disables the job.
+	Interval time.Duration `yaml:"interval" env:"PURGE_JOB_INTERVAL" env-default:"1m"`
+}
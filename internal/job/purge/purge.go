@@ -0,0 +1,52 @@
+package purgejob
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/storage"
+	"github.com/romandnk/shortener/pkg/logger"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Job periodically removes expired urls from the storage.
+type Job struct {
+	interval time.Duration
+	purger   storage.Purger
+	logger   logger.Logger
+}
+
+func NewJob(cfg Config, purger storage.Purger, logger logger.Logger) *Job {
+	return &Job{
+		interval: cfg.Interval,
+		purger:   purger,
+		logger:   logger,
+	}
+}
+
+// Run purges expired urls every interval until ctx is done.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.Purge(ctx)
+		}
+	}
+}
+
+// Purge removes expired urls once.
+func (j *Job) Purge(ctx context.Context) {
+	deleted, err := j.purger.DeleteExpired(ctx)
+	if err != nil {
+		j.logger.Error("Job.Purge - j.purger.DeleteExpired", zap.String("error", err.Error()))
+		return
+	}
+
+	if deleted > 0 {
+		j.logger.Info("Job.Purge - expired urls were deleted", zap.Int64("deleted", deleted))
+	}
+}
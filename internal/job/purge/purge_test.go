@@ -0,0 +1,105 @@
+package purgejob
+
+import (
+	"context"
+	"errors"
+	mock_storage "github.com/romandnk/shortener/internal/storage/mock"
+	mock_logger "github.com/romandnk/shortener/pkg/logger/mock"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestJob_Purge(t *testing.T) {
+	type purgerArgs struct {
+		deleted int64
+		error   error
+	}
+
+	type loggerArgs struct {
+		msg  string
+		args []any
+	}
+
+	testCases := []struct {
+		name       string
+		purgerArgs purgerArgs
+		loggerArgs loggerArgs
+		loggerMock func(m *mock_logger.MockLogger, args loggerArgs)
+	}{
+		{
+			name:       "OK",
+			purgerArgs: purgerArgs{deleted: 2},
+			loggerArgs: loggerArgs{
+				msg:  "Job.Purge - expired urls were deleted",
+				args: []any{zap.Int64("deleted", 2)},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Info(args.msg, args.args)
+			},
+		},
+		{
+			name: "nothing to delete",
+		},
+		{
+			name:       "storage error",
+			purgerArgs: purgerArgs{error: errors.New("connection refused")},
+			loggerArgs: loggerArgs{
+				msg:  "Job.Purge - j.purger.DeleteExpired",
+				args: []any{zap.String("error", "connection refused")},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+
+			purger := mock_storage.NewMockPurger(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			purger.EXPECT().DeleteExpired(ctx).Return(tc.purgerArgs.deleted, tc.purgerArgs.error)
+			if tc.loggerMock != nil {
+				tc.loggerMock(log, tc.loggerArgs)
+			}
+
+			job := NewJob(Config{Interval: time.Minute}, purger, log)
+			job.Purge(ctx)
+		})
+	}
+}
+
+func TestJob_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	purger := mock_storage.NewMockPurger(ctrl)
+	purger.EXPECT().DeleteExpired(gomock.Any()).DoAndReturn(func(context.Context) (int64, error) {
+		cancel()
+		return 0, nil
+	}).MinTimes(1)
+
+	job := NewJob(Config{Interval: time.Millisecond}, purger, mock_logger.NewMockLogger(ctrl))
+
+	done := make(chan struct{})
+	go func() {
+		job.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job was not stopped")
+	}
+}
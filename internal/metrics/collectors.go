@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	urlservice "github.com/romandnk/shortener/internal/service/url"
+)
+
+type aliasCollector struct {
+	stats       func() urlservice.Stats
+	generated   *prometheus.Desc
+	collisions  *prometheus.Desc
+	aliasLength *prometheus.Desc
+}
+
+// NewAliasCollector exposes alias generation counters of the url service.
+func NewAliasCollector(stats func() urlservice.Stats) prometheus.Collector {
+	return &aliasCollector{
+		stats: stats,
+		generated: prometheus.NewDesc(prometheus.BuildFQName(namespace, "alias", "generated_total"),
+			"Generated aliases including collided ones.", nil, nil),
+		collisions: prometheus.NewDesc(prometheus.BuildFQName(namespace, "alias", "collisions_total"),
+			"Generated aliases which collided with existing ones.", nil, nil),
+		aliasLength: prometheus.NewDesc(prometheus.BuildFQName(namespace, "alias", "length"),
+			"Current length of generated aliases.", nil, nil),
+	}
+}
+
+func (c *aliasCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.generated
+	ch <- c.collisions
+	ch <- c.aliasLength
+}
+
+func (c *aliasCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.generated, prometheus.CounterValue, float64(stats.Generated))
+	ch <- prometheus.MustNewConstMetric(c.collisions, prometheus.CounterValue, float64(stats.Collisions))
+	ch <- prometheus.MustNewConstMetric(c.aliasLength, prometheus.GaugeValue, float64(stats.AliasLength))
+}
+
+type pgxPoolCollector struct {
+	stat            func() *pgxpool.Stat
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	totalConns      *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+	canceledAcquire *prometheus.Desc
+}
+
+// NewPgxPoolCollector exposes connection pool stats.
+func NewPgxPoolCollector(stat func() *pgxpool.Stat) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "pgxpool", name), help, nil, nil)
+	}
+
+	return &pgxPoolCollector{
+		stat:            stat,
+		acquiredConns:   desc("acquired_conns", "Connections currently acquired from the pool."),
+		idleConns:       desc("idle_conns", "Idle connections in the pool."),
+		totalConns:      desc("total_conns", "Total connections in the pool."),
+		maxConns:        desc("max_conns", "Max size of the pool."),
+		acquireCount:    desc("acquire_total", "Successful acquires from the pool."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Total time spent on successful acquires."),
+		emptyAcquire:    desc("empty_acquire_total", "Acquires which waited for a connection since the pool was empty."),
+		canceledAcquire: desc("canceled_acquire_total", "Acquires canceled by context."),
+	}
+}
+
+func (c *pgxPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquire
+	ch <- c.canceledAcquire
+}
+
+func (c *pgxPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquire, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	urlservice "github.com/romandnk/shortener/internal/service/url"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_Observe(t *testing.T) {
+	m := New()
+
+	m.ObserveHTTP("GET", "/:alias", 302, 10*time.Millisecond)
+	m.ObserveHTTP("GET", "/:alias", 302, 20*time.Millisecond)
+	m.ObserveHTTP("GET", "", 404, time.Millisecond)
+	m.ObserveGRPC("/url.EventService/GetOriginalByAlias", "OK", time.Millisecond)
+	m.ObserveStorage("postgres", "CreateURL", true, time.Millisecond)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/:alias", "302")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", unmatchedRoute, "404")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.grpcRequests.WithLabelValues("/url.EventService/GetOriginalByAlias", "OK")))
+	require.Equal(t, 1, testutil.CollectAndCount(m.storageDuration))
+
+	expected := `
+# HELP shortener_storage_operation_duration_seconds Storage operation latencies by backend, operation and status.
+# TYPE shortener_storage_operation_duration_seconds histogram
+`
+	require.NoError(t, testutil.CollectAndCompare(m.storageDuration, strings.NewReader(expected+
+		`shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.0005"} 0
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.001"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.0025"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.005"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.01"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.025"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.05"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.1"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.25"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="0.5"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="1"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="2.5"} 1
+shortener_storage_operation_duration_seconds_bucket{backend="postgres",operation="CreateURL",status="error",le="+Inf"} 1
+shortener_storage_operation_duration_seconds_sum{backend="postgres",operation="CreateURL",status="error"} 0.001
+shortener_storage_operation_duration_seconds_count{backend="postgres",operation="CreateURL",status="error"} 1
+`)))
+}
+
+func TestCollectors(t *testing.T) {
+	aliases := NewAliasCollector(func() urlservice.Stats {
+		return urlservice.Stats{Generated: 10, Collisions: 2, AliasLength: 11}
+	})
+
+	expected := `
+# HELP shortener_alias_collisions_total Generated aliases which collided with existing ones.
+# TYPE shortener_alias_collisions_total counter
+shortener_alias_collisions_total 2
+# HELP shortener_alias_generated_total Generated aliases including collided ones.
+# TYPE shortener_alias_generated_total counter
+shortener_alias_generated_total 10
+# HELP shortener_alias_length Current length of generated aliases.
+# TYPE shortener_alias_length gauge
+shortener_alias_length 11
+`
+	require.NoError(t, testutil.CollectAndCompare(aliases, strings.NewReader(expected)))
+
+	// pool stats cannot be built outside of pgxpool, so only descriptions are checked
+	pool := NewPgxPoolCollector(func() *pgxpool.Stat { return nil })
+	descs := make(chan *prometheus.Desc, 10)
+	pool.Describe(descs)
+	close(descs)
+	require.Len(t, descs, 8)
+}
@@ -0,0 +1,83 @@
+package domaingrpc
+
+import (
+	"context"
+	"errors"
+	domainpb "github.com/romandnk/shortener/api/domain/pb"
+	"github.com/romandnk/shortener/internal/entity"
+	"github.com/romandnk/shortener/internal/service"
+	domainservice "github.com/romandnk/shortener/internal/service/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdminMethods can be called only with an admin api key.
+var AdminMethods = map[string]bool{
+	domainpb.DomainService_AddDomainRule_FullMethodName:    true,
+	domainpb.DomainService_ListDomainRules_FullMethodName:  true,
+	domainpb.DomainService_RemoveDomainRule_FullMethodName: true,
+}
+
+type domainHandler struct {
+	domain service.Domain
+	domainpb.UnimplementedDomainServiceServer
+}
+
+func Register(gRPCSServer *grpc.Server, domain service.Domain) {
+	domainpb.RegisterDomainServiceServer(gRPCSServer, &domainHandler{
+		domain: domain,
+	})
+}
+
+func (h domainHandler) AddDomainRule(ctx context.Context, req *domainpb.AddDomainRuleRequest) (*domainpb.DomainRule, error) {
+	rule, err := h.domain.AddRule(ctx, req.GetPattern(), entity.DomainList(req.GetList()))
+	if err != nil {
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	return &domainpb.DomainRule{
+		Pattern: rule.Pattern,
+		List:    string(rule.List),
+	}, nil
+}
+
+func (h domainHandler) ListDomainRules(ctx context.Context, _ *domainpb.ListDomainRulesRequest) (*domainpb.ListDomainRulesResponse, error) {
+	rules, err := h.domain.ListRules(ctx)
+	if err != nil {
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	resp := &domainpb.ListDomainRulesResponse{
+		Rules: make([]*domainpb.DomainRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, &domainpb.DomainRule{
+			Pattern: rule.Pattern,
+			List:    string(rule.List),
+		})
+	}
+
+	return resp, nil
+}
+
+func (h domainHandler) RemoveDomainRule(ctx context.Context, req *domainpb.RemoveDomainRuleRequest) (*domainpb.RemoveDomainRuleResponse, error) {
+	err := h.domain.RemoveRule(ctx, req.GetPattern())
+	if err != nil {
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	return &domainpb.RemoveDomainRuleResponse{}, nil
+}
+
+// errorCode maps errors of the domain service to gRPC codes.
+func errorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, domainservice.ErrInternalError):
+		return codes.Internal
+	case errors.Is(err, domainservice.ErrRuleNotFound):
+		return codes.NotFound
+	default:
+		return codes.InvalidArgument
+	}
+}
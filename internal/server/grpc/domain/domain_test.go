@@ -0,0 +1,171 @@
+package domaingrpc
+
+import (
+	"context"
+	"errors"
+	domainpb "github.com/romandnk/shortener/api/domain/pb"
+	"github.com/romandnk/shortener/internal/entity"
+	domainservice "github.com/romandnk/shortener/internal/service/domain"
+	mock_service "github.com/romandnk/shortener/internal/service/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"log"
+	"net"
+	"testing"
+)
+
+func startGRPCServer(domain *mock_service.MockDomain) (*grpc.Server, *bufconn.Listener) {
+	bufferSize := 1024 * 1024
+	listener := bufconn.Listen(bufferSize)
+
+	srv := grpc.NewServer()
+	Register(srv, domain)
+	go func() {
+		if err := srv.Serve(listener); err != nil {
+			log.Fatalf("failed to start grpc server: %v", err)
+		}
+	}()
+	return srv, listener
+}
+
+func newClient(t *testing.T, lis *bufconn.Listener) domainpb.DomainServiceClient {
+	conn, err := grpc.DialContext(context.Background(), "",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return domainpb.NewDomainServiceClient(conn)
+}
+
+func TestHandlerGRPCAddDomainRule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *domainpb.AddDomainRuleRequest
+		mock          func(m *mock_service.MockDomain)
+		expectedRule  *domainpb.DomainRule
+		expectedError error
+	}{
+		{
+			name:  "OK",
+			input: &domainpb.AddDomainRuleRequest{Pattern: "*.Evil.com", List: "block"},
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().AddRule(gomock.Any(), "*.Evil.com", entity.DomainBlock).
+					Return(entity.DomainRule{Pattern: "*.evil.com", List: entity.DomainBlock}, nil)
+			},
+			expectedRule: &domainpb.DomainRule{Pattern: "*.evil.com", List: "block"},
+		},
+		{
+			name:  "invalid pattern",
+			input: &domainpb.AddDomainRuleRequest{Pattern: "evil.*.com", List: "block"},
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().AddRule(gomock.Any(), "evil.*.com", entity.DomainBlock).
+					Return(entity.DomainRule{}, domainservice.ErrInvalidPattern)
+			},
+			expectedError: errors.New("rpc error: code = InvalidArgument desc = domain pattern must be a host or *. followed by a host"),
+		},
+		{
+			name:  "internal error",
+			input: &domainpb.AddDomainRuleRequest{Pattern: "evil.com", List: "block"},
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().AddRule(gomock.Any(), "evil.com", entity.DomainBlock).
+					Return(entity.DomainRule{}, domainservice.ErrInternalError)
+			},
+			expectedError: errors.New("rpc error: code = Internal desc = internal error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			domainService := mock_service.NewMockDomain(ctrl)
+			tc.mock(domainService)
+
+			srv, lis := startGRPCServer(domainService)
+			defer srv.Stop()
+			defer lis.Close()
+
+			res, err := newClient(t, lis).AddDomainRule(context.Background(), tc.input)
+			if tc.expectedError != nil {
+				require.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedRule.GetPattern(), res.GetPattern())
+			require.Equal(t, tc.expectedRule.GetList(), res.GetList())
+		})
+	}
+}
+
+func TestHandlerGRPCListDomainRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	domainService := mock_service.NewMockDomain(ctrl)
+	domainService.EXPECT().ListRules(gomock.Any()).Return([]entity.DomainRule{
+		{Pattern: "*.evil.com", List: entity.DomainBlock},
+		{Pattern: "go.dev", List: entity.DomainAllow},
+	}, nil)
+
+	srv, lis := startGRPCServer(domainService)
+	defer srv.Stop()
+	defer lis.Close()
+
+	res, err := newClient(t, lis).ListDomainRules(context.Background(), &domainpb.ListDomainRulesRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.GetRules(), 2)
+	require.Equal(t, "*.evil.com", res.GetRules()[0].GetPattern())
+	require.Equal(t, "block", res.GetRules()[0].GetList())
+	require.Equal(t, "go.dev", res.GetRules()[1].GetPattern())
+	require.Equal(t, "allow", res.GetRules()[1].GetList())
+}
+
+func TestHandlerGRPCRemoveDomainRule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		serviceError  error
+		expectedError error
+	}{
+		{
+			name:  "OK",
+			input: "*.evil.com",
+		},
+		{
+			name:          "rule is not found",
+			input:         "evil.com",
+			serviceError:  domainservice.ErrRuleNotFound,
+			expectedError: errors.New("rpc error: code = NotFound desc = domain rule is not found"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			domainService := mock_service.NewMockDomain(ctrl)
+			domainService.EXPECT().RemoveRule(gomock.Any(), tc.input).Return(tc.serviceError)
+
+			srv, lis := startGRPCServer(domainService)
+			defer srv.Stop()
+			defer lis.Close()
+
+			_, err := newClient(t, lis).RemoveDomainRule(context.Background(), &domainpb.RemoveDomainRuleRequest{Pattern: tc.input})
+			if tc.expectedError != nil {
+				require.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/service"
+	authservice "github.com/romandnk/shortener/internal/service/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+const (
+	apiKeyMetadata = "x-api-key"
+	bearerPrefix   = "bearer "
+)
+
+// AuthInterceptor requires an api key in metadata for all methods except anonymous ones.
+func AuthInterceptor(auth service.Auth, anonymous map[string]bool) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !auth.Enabled() || anonymous[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		key, err := auth.Authenticate(ctx, apiKey(ctx))
+		if err != nil {
+			code := codes.Unauthenticated
+			if errors.Is(err, authservice.ErrInternalError) {
+				code = codes.Internal
+			}
+			return nil, status.Error(code, err.Error())
+		}
+
+		return handler(authservice.WithAPIKey(ctx, key), req)
+	}
+}
+
+// AdminInterceptor requires an admin api key for admin methods, it follows AuthInterceptor
+// which puts the authenticated key into the context. Admin methods are denied if auth is disabled.
+func AdminInterceptor(auth service.Auth, admin map[string]bool) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !admin[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		if !auth.Enabled() {
+			return nil, status.Error(codes.PermissionDenied, authservice.ErrAuthDisabled.Error())
+		}
+
+		key, ok := authservice.APIKeyFromContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, authservice.ErrMissingAPIKey.Error())
+		}
+		if !auth.IsAdmin(key) {
+			return nil, status.Error(codes.PermissionDenied, authservice.ErrNotAdmin.Error())
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// apiKey takes the key from x-api-key metadata or from bearer authorization.
+func apiKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(apiKeyMetadata); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+
+	if values := md.Get("authorization"); len(values) > 0 {
+		authorization := values[0]
+		if len(authorization) > len(bearerPrefix) && strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+			return authorization[len(bearerPrefix):]
+		}
+	}
+
+	return ""
+}
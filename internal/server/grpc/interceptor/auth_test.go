@@ -0,0 +1,96 @@
+package interceptor
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	authservice "github.com/romandnk/shortener/internal/service/auth"
+	mock_service "github.com/romandnk/shortener/internal/service/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestAdminInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "resp", nil
+	}
+	adminMethod := "/domain.DomainService/AddRule"
+	admin := map[string]bool{adminMethod: true}
+
+	type mockBehaviour func(auth *mock_service.MockAuth)
+
+	testCases := []struct {
+		name         string
+		method       string
+		ctx          context.Context
+		mock         mockBehaviour
+		expectedCode codes.Code
+	}{
+		{
+			name:   "admin key",
+			method: adminMethod,
+			ctx:    authservice.WithAPIKey(context.Background(), entity.APIKey{Name: "ops"}),
+			mock: func(auth *mock_service.MockAuth) {
+				auth.EXPECT().Enabled().Return(true)
+				auth.EXPECT().IsAdmin(entity.APIKey{Name: "ops"}).Return(true)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:   "not an admin key",
+			method: adminMethod,
+			ctx:    authservice.WithAPIKey(context.Background(), entity.APIKey{Name: "ci"}),
+			mock: func(auth *mock_service.MockAuth) {
+				auth.EXPECT().Enabled().Return(true)
+				auth.EXPECT().IsAdmin(entity.APIKey{Name: "ci"}).Return(false)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:   "no api key",
+			method: adminMethod,
+			ctx:    context.Background(),
+			mock: func(auth *mock_service.MockAuth) {
+				auth.EXPECT().Enabled().Return(true)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:   "auth disabled",
+			method: adminMethod,
+			ctx:    context.Background(),
+			mock: func(auth *mock_service.MockAuth) {
+				auth.EXPECT().Enabled().Return(false)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "not an admin method",
+			method:       "/url.EventService/CreateURLAlias",
+			ctx:          context.Background(),
+			mock:         func(auth *mock_service.MockAuth) {},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			auth := mock_service.NewMockAuth(ctrl)
+			tc.mock(auth)
+
+			info := &grpc.UnaryServerInfo{FullMethod: tc.method}
+			resp, err := AdminInterceptor(auth, admin)(tc.ctx, "req", info, handler)
+			require.Equal(t, tc.expectedCode, status.Code(err))
+			if tc.expectedCode == codes.OK {
+				require.Equal(t, "resp", resp)
+			}
+		})
+	}
+}
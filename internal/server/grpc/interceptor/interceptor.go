@@ -3,13 +3,15 @@ package interceptor
 import (
 	"context"
 	"errors"
+	"github.com/romandnk/shortener/internal/metrics"
 	"github.com/romandnk/shortener/pkg/logger"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 	"time"
 )
 
-func LoggingInterceptor(logger logger.Logger) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+func LoggingInterceptor(logger logger.Logger, metrics *metrics.Metrics) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 
@@ -17,6 +19,8 @@ func LoggingInterceptor(logger logger.Logger) func(ctx context.Context, req inte
 
 		duration := time.Since(start)
 
+		metrics.ObserveGRPC(info.FullMethod, status.Code(err).String(), duration)
+
 		logErr := err
 		if logErr == nil {
 			logErr = errors.New("empty")
@@ -0,0 +1,77 @@
+package interceptor
+
+import (
+	"context"
+	authservice "github.com/romandnk/shortener/internal/service/auth"
+	"github.com/romandnk/shortener/pkg/logger"
+	"github.com/romandnk/shortener/pkg/ratelimit"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"math"
+	"net"
+	"strconv"
+)
+
+// IPRateLimitInterceptor limits calls per client ip. It must precede AuthInterceptor,
+// so that calls with wrong or missing api keys are limited before the key lookup.
+func IPRateLimitInterceptor(limiter ratelimit.Limiter, logger logger.Logger) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return rateLimit(ctx, limiter, logger, "ip:"+clientIP(ctx), req, handler)
+	}
+}
+
+// KeyRateLimitInterceptor limits calls per api key on top of the ip limit, anonymous calls pass.
+// It must follow AuthInterceptor to see the authenticated key.
+func KeyRateLimitInterceptor(limiter ratelimit.Limiter, logger logger.Logger) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		apiKey, ok := authservice.APIKeyFromContext(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+		return rateLimit(ctx, limiter, logger, "key:"+apiKey.Name, req, handler)
+	}
+}
+
+func rateLimit(ctx context.Context, limiter ratelimit.Limiter, logger logger.Logger, key string, req interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+	res, err := limiter.Allow(ctx, key)
+	if err != nil {
+		logger.Error("RateLimitInterceptor - limiter.Allow", zap.String("error", err.Error()))
+		return handler(ctx, req)
+	}
+
+	if !res.Allowed {
+		retryAfter := max(int(math.Ceil(res.RetryAfter.Seconds())), 1)
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+
+		st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+		withDetails, detailsErr := st.WithDetails(&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(res.RetryAfter),
+		})
+		if detailsErr != nil {
+			return nil, st.Err()
+		}
+		return nil, withDetails.Err()
+	}
+
+	return handler(ctx, req)
+}
+
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
@@ -0,0 +1,117 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	authservice "github.com/romandnk/shortener/internal/service/auth"
+	"github.com/romandnk/shortener/pkg/logger"
+	mock_logger "github.com/romandnk/shortener/pkg/logger/mock"
+	"github.com/romandnk/shortener/pkg/ratelimit"
+	mock_ratelimit "github.com/romandnk/shortener/pkg/ratelimit/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestRateLimitInterceptors(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 50000},
+	})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "resp", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/url.EventService/CreateURLAlias"}
+
+	type mockBehaviour func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger)
+
+	type interceptor func(limiter ratelimit.Limiter, logger logger.Logger) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error)
+
+	testCases := []struct {
+		name          string
+		interceptor   interceptor
+		ctx           context.Context
+		mock          mockBehaviour
+		expectedCode  codes.Code
+		expectedDelay time.Duration
+	}{
+		{
+			name:        "allowed by ip",
+			interceptor: IPRateLimitInterceptor,
+			ctx:         ctx,
+			mock: func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {
+				limiter.EXPECT().Allow(gomock.Any(), "ip:127.0.0.1").Return(ratelimit.Result{Allowed: true}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:        "limited by ip before authentication",
+			interceptor: IPRateLimitInterceptor,
+			ctx:         ctx,
+			mock: func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {
+				limiter.EXPECT().Allow(gomock.Any(), "ip:127.0.0.1").Return(ratelimit.Result{RetryAfter: time.Second}, nil)
+			},
+			expectedCode:  codes.ResourceExhausted,
+			expectedDelay: time.Second,
+		},
+		{
+			name:         "anonymous call is not limited by api key",
+			interceptor:  KeyRateLimitInterceptor,
+			ctx:          ctx,
+			mock:         func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {},
+			expectedCode: codes.OK,
+		},
+		{
+			name:        "limited by api key",
+			interceptor: KeyRateLimitInterceptor,
+			ctx:         authservice.WithAPIKey(ctx, entity.APIKey{Name: "ci"}),
+			mock: func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {
+				limiter.EXPECT().Allow(gomock.Any(), "key:ci").Return(ratelimit.Result{RetryAfter: 300 * time.Millisecond}, nil)
+			},
+			expectedCode:  codes.ResourceExhausted,
+			expectedDelay: 300 * time.Millisecond,
+		},
+		{
+			name:        "limiter error",
+			interceptor: IPRateLimitInterceptor,
+			ctx:         ctx,
+			mock: func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {
+				limiter.EXPECT().Allow(gomock.Any(), "ip:127.0.0.1").Return(ratelimit.Result{}, errors.New("connection refused"))
+				log.EXPECT().Error("RateLimitInterceptor - limiter.Allow", []any{zap.String("error", "connection refused")})
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			limiter := mock_ratelimit.NewMockLimiter(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			tc.mock(limiter, log)
+
+			resp, err := tc.interceptor(limiter, log)(tc.ctx, "req", info, handler)
+			st := status.Convert(err)
+			require.Equal(t, tc.expectedCode, st.Code())
+			if tc.expectedCode != codes.OK {
+				require.Nil(t, resp)
+				require.Len(t, st.Details(), 1)
+				require.Equal(t, tc.expectedDelay, st.Details()[0].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+			} else {
+				require.Equal(t, "resp", resp)
+			}
+		})
+	}
+}
@@ -4,26 +4,110 @@ import (
 	"context"
 	"errors"
 	urlpb "github.com/romandnk/shortener/api/url/pb"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
 	"github.com/romandnk/shortener/internal/service"
+	clickservice "github.com/romandnk/shortener/internal/service/click"
 	urlservice "github.com/romandnk/shortener/internal/service/url"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"net"
+	"time"
 )
 
+// reasonURLExpired and reasonURLBlocked are sent in error details to tell expired
+// and disabled aliases apart from unknown ones.
+const (
+	reasonURLExpired = "URL_EXPIRED"
+	reasonURLBlocked = "URL_BLOCKED"
+)
+
+// AnonymousMethods can be called without an api key.
+var AnonymousMethods = map[string]bool{
+	urlpb.EventService_GetOriginalByAlias_FullMethodName: true,
+	urlpb.EventService_GetURLStats_FullMethodName:        true,
+}
+
 type urlHandler struct {
-	url service.URL
+	url   service.URL
+	click service.Click
 	urlpb.UnimplementedEventServiceServer
 }
 
-func Register(gRPCSServer *grpc.Server, url service.URL) {
+func Register(gRPCSServer *grpc.Server, url service.URL, click service.Click) {
 	urlpb.RegisterEventServiceServer(gRPCSServer, &urlHandler{
-		url: url,
+		url:   url,
+		click: click,
 	})
 }
 
 func (h urlHandler) CreateURLAlias(ctx context.Context, req *urlpb.CreateURLAliasRequest) (*urlpb.CreateURLAliasResponse, error) {
-	alias, err := h.url.CreateURLAlias(ctx, req.GetOriginal())
+	expiresAt, err := expirationTime(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	alias, existing, err := h.url.CreateURLAlias(ctx, entity.URL{
+		Original:  req.GetOriginal(),
+		Alias:     req.GetAlias(),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		code := codes.InvalidArgument
+		if errors.Is(err, urlservice.ErrCustomAliasDisabled) {
+			code = codes.PermissionDenied
+		}
+		if errors.Is(err, urlservice.ErrInternalError) {
+			code = codes.Internal
+		}
+		return nil, urlError(code, err)
+	}
+
+	resp := &urlpb.CreateURLAliasResponse{
+		Alias:    alias,
+		Existing: existing,
+	}
+	if !existing && !expiresAt.IsZero() {
+		resp.ExpiresAt = timestamppb.New(expiresAt)
+	}
+
+	return resp, nil
+}
+
+func (h urlHandler) CreateURLAliasBatch(ctx context.Context, req *urlpb.CreateURLAliasBatchRequest) (*urlpb.CreateURLAliasBatchResponse, error) {
+	resp := &urlpb.CreateURLAliasBatchResponse{
+		Results: make([]*urlpb.CreateURLAliasBatchResult, len(req.GetUrls())),
+	}
+
+	// urls with invalid expiration are not sent to the service
+	urls := make([]entity.URL, 0, len(req.GetUrls()))
+	positions := make([]int, 0, len(req.GetUrls()))
+	for i, urlReq := range req.GetUrls() {
+		resp.Results[i] = &urlpb.CreateURLAliasBatchResult{}
+
+		expiresAt, err := expirationTime(urlReq)
+		if err != nil {
+			resp.Results[i].Error = err.Error()
+			continue
+		}
+		urls = append(urls, entity.URL{
+			Original:  urlReq.GetOriginal(),
+			Alias:     urlReq.GetAlias(),
+			ExpiresAt: expiresAt,
+		})
+		positions = append(positions, i)
+	}
+
+	if len(urls) == 0 && len(req.GetUrls()) > 0 {
+		return resp, nil
+	}
+
+	results, err := h.url.CreateURLAliasBatch(ctx, urls)
 	if err != nil {
 		code := codes.InvalidArgument
 		if errors.Is(err, urlservice.ErrInternalError) {
@@ -31,21 +115,180 @@ func (h urlHandler) CreateURLAlias(ctx context.Context, req *urlpb.CreateURLAlia
 		}
 		return nil, status.Error(code, err.Error())
 	}
-	return &urlpb.CreateURLAliasResponse{
-		Alias: alias,
-	}, nil
+
+	for i, res := range results {
+		result := resp.Results[positions[i]]
+		if res.Err != nil {
+			result.Error = res.Err.Error()
+			var rejected *urlservice.RejectedURLError
+			if errors.As(res.Err, &rejected) {
+				result.Reason = rejected.Reason
+			}
+			continue
+		}
+		result.Alias = res.Alias
+		result.Existing = res.Existing
+		if !res.Existing && !urls[i].ExpiresAt.IsZero() {
+			result.ExpiresAt = timestamppb.New(urls[i].ExpiresAt)
+		}
+	}
+
+	return resp, nil
+}
+
+func expirationTime(req *urlpb.CreateURLAliasRequest) (time.Time, error) {
+	var (
+		expiresAt time.Time
+		ttl       time.Duration
+	)
+
+	if req.GetExpiresAt() != nil {
+		if err := req.GetExpiresAt().CheckValid(); err != nil {
+			return time.Time{}, err
+		}
+		expiresAt = req.GetExpiresAt().AsTime()
+	}
+	if req.GetTtl() != nil {
+		if err := req.GetTtl().CheckValid(); err != nil {
+			return time.Time{}, err
+		}
+		ttl = req.GetTtl().AsDuration()
+		if ttl == 0 {
+			return time.Time{}, urlservice.ErrInvalidTTL
+		}
+	}
+
+	return urlservice.ExpirationTime(expiresAt, ttl)
 }
 
 func (h urlHandler) GetOriginalByAlias(ctx context.Context, req *urlpb.GetOriginalByAliasRequest) (*urlpb.GetOriginalByAliasResponse, error) {
 	original, err := h.url.GetOriginalByAlias(ctx, req.GetAlias())
 	if err != nil {
+		if errors.Is(err, urlservice.ErrOriginalURLExpired) {
+			return nil, reasonError(codes.NotFound, reasonURLExpired, err)
+		}
+		if errors.Is(err, urlservice.ErrOriginalURLBlocked) {
+			return nil, reasonError(codes.PermissionDenied, reasonURLBlocked, err)
+		}
 		code := codes.InvalidArgument
 		if errors.Is(err, urlservice.ErrInternalError) {
 			code = codes.Internal
 		}
 		return nil, status.Error(code, err.Error())
 	}
+
+	h.click.RecordClick(newClick(ctx, req.GetAlias()), clientIP(ctx))
+
 	return &urlpb.GetOriginalByAliasResponse{
 		Original: original,
 	}, nil
 }
+
+func (h urlHandler) UpdateOriginal(ctx context.Context, req *urlpb.UpdateOriginalRequest) (*urlpb.UpdateOriginalResponse, error) {
+	err := h.url.UpdateOriginal(ctx, req.GetAlias(), req.GetOriginal())
+	if err != nil {
+		return nil, urlError(errorCode(err), err)
+	}
+	return &urlpb.UpdateOriginalResponse{}, nil
+}
+
+func (h urlHandler) DeleteByAlias(ctx context.Context, req *urlpb.DeleteByAliasRequest) (*urlpb.DeleteByAliasResponse, error) {
+	err := h.url.DeleteByAlias(ctx, req.GetAlias())
+	if err != nil {
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &urlpb.DeleteByAliasResponse{}, nil
+}
+
+// errorCode maps errors of changing existing urls to gRPC codes.
+func errorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, urlservice.ErrInternalError):
+		return codes.Internal
+	case errors.Is(err, urlservice.ErrOriginalURLNotFound):
+		return codes.NotFound
+	default:
+		return codes.InvalidArgument
+	}
+}
+
+func (h urlHandler) GetURLStats(ctx context.Context, req *urlpb.GetURLStatsRequest) (*urlpb.GetURLStatsResponse, error) {
+	stats, err := h.click.GetClickStats(ctx, req.GetAlias())
+	if err != nil {
+		code := codes.InvalidArgument
+		if errors.Is(err, clickservice.ErrURLNotFound) {
+			code = codes.NotFound
+		}
+		if errors.Is(err, clickservice.ErrInternalError) {
+			code = codes.Internal
+		}
+		return nil, status.Error(code, err.Error())
+	}
+
+	resp := &urlpb.GetURLStatsResponse{
+		Alias: req.GetAlias(),
+		Total: stats.Total,
+		Daily: make([]*urlpb.DailyClicks, 0, len(stats.Daily)),
+	}
+	for _, daily := range stats.Daily {
+		resp.Daily = append(resp.Daily, &urlpb.DailyClicks{
+			Date:   daily.Day.Format(constant.DayLayout),
+			Clicks: daily.Clicks,
+		})
+	}
+
+	return resp, nil
+}
+
+// newClick takes referrer and user agent from request metadata.
+func newClick(ctx context.Context, alias string) entity.Click {
+	click := entity.Click{Alias: alias}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return click
+	}
+	if values := md.Get("referer"); len(values) > 0 {
+		click.Referrer = values[0]
+	}
+	if values := md.Get("user-agent"); len(values) > 0 {
+		click.UserAgent = values[0]
+	}
+
+	return click
+}
+
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+func reasonError(code codes.Code, reason string, err error) error {
+	st := status.New(code, err.Error())
+	withDetails, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// urlError sends the reason of urls rejected by the policy in error details.
+func urlError(code codes.Code, err error) error {
+	var rejected *urlservice.RejectedURLError
+	if !errors.As(err, &rejected) {
+		return status.Error(code, err.Error())
+	}
+
+	return reasonError(code, rejected.Reason, err)
+}
@@ -3,18 +3,25 @@ package urlgrpc
 import (
 	"context"
 	"errors"
-	urlpb "github.com/romandnk/shortener/internal/server/grpc/url/pb"
+	urlpb "github.com/romandnk/shortener/api/url/pb"
+	"github.com/romandnk/shortener/internal/entity"
+	clickservice "github.com/romandnk/shortener/internal/service/click"
 	mock_service "github.com/romandnk/shortener/internal/service/mock"
 	urlservice "github.com/romandnk/shortener/internal/service/url"
 	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"log"
 	"net"
 	"testing"
+	"time"
 )
 
 func startGRPCServer() (*grpc.Server, *bufconn.Listener) {
@@ -39,23 +46,26 @@ func getDialer(lis *bufconn.Listener) func(context.Context, string) (net.Conn, e
 func TestHandlerGRPCCreateEvent(t *testing.T) {
 	type args struct {
 		input         string
+		alias         string
 		output        string
+		existing      bool
 		expectedError error
 	}
 
 	type mockBehaviour func(m *mock_service.MockURL, args args)
 
 	testCases := []struct {
-		name          string
-		input         urlpb.CreateURLAliasRequest
-		args          args
-		mock          mockBehaviour
-		expectedAlias string
-		expectedError error
+		name             string
+		input            *urlpb.CreateURLAliasRequest
+		args             args
+		mock             mockBehaviour
+		expectedAlias    string
+		expectedExisting bool
+		expectedError    error
 	}{
 		{
 			name: "OK",
-			input: urlpb.CreateURLAliasRequest{
+			input: &urlpb.CreateURLAliasRequest{
 				Original: "http://google.com",
 			},
 			args: args{
@@ -63,40 +73,141 @@ func TestHandlerGRPCCreateEvent(t *testing.T) {
 				output: "testtest11",
 			},
 			mock: func(m *mock_service.MockURL, args args) {
-				m.EXPECT().CreateURLAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
 			},
 			expectedAlias: "testtest11",
 		},
 		{
-			name: "original url is empty",
+			name: "OK existing alias",
+			input: &urlpb.CreateURLAliasRequest{
+				Original: "http://google.com",
+			},
+			args: args{
+				input:    "http://google.com",
+				output:   "testtest11",
+				existing: true,
+			},
+			mock: func(m *mock_service.MockURL, args args) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
+			},
+			expectedAlias:    "testtest11",
+			expectedExisting: true,
+		},
+		{
+			name: "OK custom alias",
+			input: &urlpb.CreateURLAliasRequest{
+				Original: "http://google.com",
+				Alias:    "google",
+			},
+			args: args{
+				input:  "http://google.com",
+				alias:  "google",
+				output: "google",
+			},
+			mock: func(m *mock_service.MockURL, args args) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
+			},
+			expectedAlias: "google",
+		},
+		{
+			name: "custom alias contains invalid symbols",
+			input: &urlpb.CreateURLAliasRequest{
+				Original: "http://google.com",
+				Alias:    "goo gle",
+			},
+			args: args{
+				input:         "http://google.com",
+				alias:         "goo gle",
+				expectedError: urlservice.ErrInvalidAliasSymbols,
+			},
+			mock: func(m *mock_service.MockURL, args args) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
+			},
+			expectedError: errors.New("rpc error: code = InvalidArgument desc = unique id contains invalid symbols"),
+		},
+		{
+			name: "custom aliases are disabled",
+			input: &urlpb.CreateURLAliasRequest{
+				Original: "http://google.com",
+				Alias:    "google",
+			},
+			args: args{
+				input:         "http://google.com",
+				alias:         "google",
+				expectedError: urlservice.ErrCustomAliasDisabled,
+			},
+			mock: func(m *mock_service.MockURL, args args) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
+			},
+			expectedError: errors.New("rpc error: code = PermissionDenied desc = custom unique ids are disabled"),
+		},
+		{
+			name:  "original url is empty",
+			input: &urlpb.CreateURLAliasRequest{},
 			args: args{
 				expectedError: urlservice.ErrEmptyOriginalURL,
 			},
 			mock: func(m *mock_service.MockURL, args args) {
-				m.EXPECT().CreateURLAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
 			},
 			expectedError: errors.New("rpc error: code = InvalidArgument desc = url cannot be empty"),
 		},
 		{
 			name: "invalid original url format",
+			input: &urlpb.CreateURLAliasRequest{
+				Original: "http//google.com",
+			},
 			args: args{
+				input:         "http//google.com",
 				expectedError: urlservice.ErrInvalidOriginalURL,
 			},
 			mock: func(m *mock_service.MockURL, args args) {
-				m.EXPECT().CreateURLAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
 			},
 			expectedError: errors.New("rpc error: code = InvalidArgument desc = invalid url format"),
 		},
 		{
 			name: "original url exists",
+			input: &urlpb.CreateURLAliasRequest{
+				Original: "http://google.com",
+			},
 			args: args{
+				input:         "http://google.com",
 				expectedError: storageerrors.ErrOriginalURLExists,
 			},
 			mock: func(m *mock_service.MockURL, args args) {
-				m.EXPECT().CreateURLAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
 			},
 			expectedError: errors.New("rpc error: code = InvalidArgument desc = original url already exists"),
 		},
+		{
+			name: "OK with expiration time",
+			input: &urlpb.CreateURLAliasRequest{
+				Original:  "http://google.com",
+				ExpiresAt: timestamppb.New(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			args: args{
+				input:  "http://google.com",
+				output: "testtest11",
+			},
+			mock: func(m *mock_service.MockURL, args args) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{
+					Original:  args.input,
+					ExpiresAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+				}).Return(args.output, args.existing, args.expectedError)
+			},
+			expectedAlias: "testtest11",
+		},
+		{
+			name: "both expiration time and ttl",
+			input: &urlpb.CreateURLAliasRequest{
+				Original:  "http://google.com",
+				ExpiresAt: timestamppb.New(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)),
+				Ttl:       durationpb.New(time.Hour),
+			},
+			mock:          func(m *mock_service.MockURL, args args) {},
+			expectedError: errors.New("rpc error: code = InvalidArgument desc = only one of expiration time and ttl can be set"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -128,13 +239,14 @@ func TestHandlerGRPCCreateEvent(t *testing.T) {
 
 			tc.mock(urlService, tc.args)
 
-			res, err := client.CreateURLAlias(ctx, &tc.input)
+			res, err := client.CreateURLAlias(ctx, tc.input)
 			if err != nil {
 				require.EqualError(t, err, tc.expectedError.Error())
 			} else {
 				require.NoError(t, err)
 			}
 			require.Equal(t, tc.expectedAlias, res.GetAlias())
+			require.Equal(t, tc.expectedExisting, res.GetExisting())
 		})
 	}
 }
@@ -150,15 +262,17 @@ func TestURLHandler_GetOriginalByAlias(t *testing.T) {
 
 	testCases := []struct {
 		name             string
-		input            urlpb.GetOriginalByAliasRequest
+		input            *urlpb.GetOriginalByAliasRequest
 		args             args
 		mock             mockBehaviour
 		expectedOriginal string
 		expectedError    error
+		expectedReason   string
+		expectedClick    bool
 	}{
 		{
 			name: "OK",
-			input: urlpb.GetOriginalByAliasRequest{
+			input: &urlpb.GetOriginalByAliasRequest{
 				Alias: "testtest11",
 			},
 			args: args{
@@ -169,10 +283,11 @@ func TestURLHandler_GetOriginalByAlias(t *testing.T) {
 				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
 			},
 			expectedOriginal: "http://google.com",
+			expectedClick:    true,
 		},
 		{
 			name: "too short alias",
-			input: urlpb.GetOriginalByAliasRequest{
+			input: &urlpb.GetOriginalByAliasRequest{
 				Alias: "testtest",
 			},
 			args: args{
@@ -182,11 +297,11 @@ func TestURLHandler_GetOriginalByAlias(t *testing.T) {
 			mock: func(m *mock_service.MockURL, args args) {
 				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
 			},
-			expectedError: errors.New("rpc error: code = InvalidArgument desc = unique id must be 10 symbols length"),
+			expectedError: errors.New("rpc error: code = InvalidArgument desc = unique id has invalid length"),
 		},
 		{
 			name: "original url is not found",
-			input: urlpb.GetOriginalByAliasRequest{
+			input: &urlpb.GetOriginalByAliasRequest{
 				Alias: "testtest12",
 			},
 			args: args{
@@ -198,6 +313,36 @@ func TestURLHandler_GetOriginalByAlias(t *testing.T) {
 			},
 			expectedError: errors.New("rpc error: code = InvalidArgument desc = original url is not found"),
 		},
+		{
+			name: "original url is expired",
+			input: &urlpb.GetOriginalByAliasRequest{
+				Alias: "testtest12",
+			},
+			args: args{
+				input:         "testtest12",
+				expectedError: urlservice.ErrOriginalURLExpired,
+			},
+			mock: func(m *mock_service.MockURL, args args) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			expectedError:  errors.New("rpc error: code = NotFound desc = url alias is expired"),
+			expectedReason: reasonURLExpired,
+		},
+		{
+			name: "domain of original url is blocked",
+			input: &urlpb.GetOriginalByAliasRequest{
+				Alias: "testtest12",
+			},
+			args: args{
+				input:         "testtest12",
+				expectedError: urlservice.ErrOriginalURLBlocked,
+			},
+			mock: func(m *mock_service.MockURL, args args) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			expectedError:  errors.New("rpc error: code = PermissionDenied desc = url alias is disabled, its domain is blocked"),
+			expectedReason: reasonURLBlocked,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -211,8 +356,17 @@ func TestURLHandler_GetOriginalByAlias(t *testing.T) {
 			defer lis.Close()
 
 			urlService := mock_service.NewMockURL(ctrl)
+			clickService := mock_service.NewMockClick(ctrl)
 			handler := urlHandler{
-				url: urlService,
+				url:   urlService,
+				click: clickService,
+			}
+
+			if tc.expectedClick {
+				clickService.EXPECT().RecordClick(gomock.Cond(func(x any) bool {
+					click := x.(entity.Click)
+					return click.Alias == tc.args.input && click.UserAgent != ""
+				}), gomock.Any())
 			}
 
 			urlpb.RegisterEventServiceServer(srv, handler)
@@ -229,9 +383,14 @@ func TestURLHandler_GetOriginalByAlias(t *testing.T) {
 
 			tc.mock(urlService, tc.args)
 
-			res, err := client.GetOriginalByAlias(ctx, &tc.input)
+			res, err := client.GetOriginalByAlias(ctx, tc.input)
 			if err != nil {
 				require.EqualError(t, err, tc.expectedError.Error())
+				if tc.expectedReason != "" {
+					details := status.Convert(err).Details()
+					require.Len(t, details, 1)
+					require.Equal(t, tc.expectedReason, details[0].(*errdetails.ErrorInfo).GetReason())
+				}
 			} else {
 				require.NoError(t, err)
 			}
@@ -239,3 +398,207 @@ func TestURLHandler_GetOriginalByAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestURLHandler_GetURLStats(t *testing.T) {
+	type mockBehaviour func(m *mock_service.MockClick)
+
+	testCases := []struct {
+		name          string
+		input         *urlpb.GetURLStatsRequest
+		mock          mockBehaviour
+		expectedTotal int64
+		expectedDaily []*urlpb.DailyClicks
+		expectedError error
+	}{
+		{
+			name:  "OK",
+			input: &urlpb.GetURLStatsRequest{Alias: "testtest11"},
+			mock: func(m *mock_service.MockClick) {
+				m.EXPECT().GetClickStats(gomock.Any(), "testtest11").Return(entity.ClickStats{
+					Total: 3,
+					Daily: []entity.DailyClicks{
+						{Day: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Clicks: 3},
+					},
+				}, nil)
+			},
+			expectedTotal: 3,
+			expectedDaily: []*urlpb.DailyClicks{{Date: "2030-01-01", Clicks: 3}},
+		},
+		{
+			name:  "url is not found",
+			input: &urlpb.GetURLStatsRequest{Alias: "testtest11"},
+			mock: func(m *mock_service.MockClick) {
+				m.EXPECT().GetClickStats(gomock.Any(), "testtest11").Return(entity.ClickStats{}, clickservice.ErrURLNotFound)
+			},
+			expectedError: errors.New("rpc error: code = NotFound desc = url alias is not found"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv, lis := startGRPCServer()
+			defer srv.Stop()
+			defer lis.Close()
+
+			clickService := mock_service.NewMockClick(ctrl)
+			handler := urlHandler{
+				click: clickService,
+			}
+
+			urlpb.RegisterEventServiceServer(srv, handler)
+
+			ctx := context.Background()
+
+			conn, err := grpc.DialContext(ctx, "",
+				grpc.WithContextDialer(getDialer(lis)),
+				grpc.WithTransportCredentials(insecure.NewCredentials()))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			client := urlpb.NewEventServiceClient(conn)
+
+			tc.mock(clickService)
+
+			res, err := client.GetURLStats(ctx, tc.input)
+			if err != nil {
+				require.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+			require.NoError(t, tc.expectedError)
+			require.Equal(t, tc.expectedTotal, res.GetTotal())
+			require.Len(t, res.GetDaily(), len(tc.expectedDaily))
+			for i, daily := range tc.expectedDaily {
+				require.Equal(t, daily.GetDate(), res.GetDaily()[i].GetDate())
+				require.Equal(t, daily.GetClicks(), res.GetDaily()[i].GetClicks())
+			}
+		})
+	}
+}
+
+func TestURLHandler_UpdateOriginalAndDeleteByAlias(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv, lis := startGRPCServer()
+	defer srv.Stop()
+	defer lis.Close()
+
+	urlService := mock_service.NewMockURL(ctrl)
+	urlpb.RegisterEventServiceServer(srv, urlHandler{
+		url: urlService,
+	})
+
+	ctx := context.Background()
+
+	conn, err := grpc.DialContext(ctx, "",
+		grpc.WithContextDialer(getDialer(lis)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := urlpb.NewEventServiceClient(conn)
+
+	gomock.InOrder(
+		urlService.EXPECT().UpdateOriginal(gomock.Any(), "testtest11", "http://google.com").Return(nil),
+		urlService.EXPECT().UpdateOriginal(gomock.Any(), "testtest11", "http://google.com").
+			Return(storageerrors.ErrOriginalURLExists),
+		urlService.EXPECT().UpdateOriginal(gomock.Any(), "testtest11", "http://google.com").
+			Return(urlservice.ErrPrivateHost),
+		urlService.EXPECT().DeleteByAlias(gomock.Any(), "testtest11").Return(nil),
+		urlService.EXPECT().DeleteByAlias(gomock.Any(), "testtest11").Return(urlservice.ErrOriginalURLNotFound),
+	)
+
+	req := &urlpb.UpdateOriginalRequest{Alias: "testtest11", Original: "http://google.com"}
+
+	_, err = client.UpdateOriginal(ctx, req)
+	require.NoError(t, err)
+
+	_, err = client.UpdateOriginal(ctx, req)
+	require.EqualError(t, err, "rpc error: code = InvalidArgument desc = original url already exists")
+	require.Empty(t, status.Convert(err).Details())
+
+	_, err = client.UpdateOriginal(ctx, req)
+	require.EqualError(t, err, "rpc error: code = InvalidArgument desc = url host is private")
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	require.Equal(t, urlservice.ReasonPrivateHost, details[0].(*errdetails.ErrorInfo).GetReason())
+
+	_, err = client.DeleteByAlias(ctx, &urlpb.DeleteByAliasRequest{Alias: "testtest11"})
+	require.NoError(t, err)
+
+	_, err = client.DeleteByAlias(ctx, &urlpb.DeleteByAliasRequest{Alias: "testtest11"})
+	require.EqualError(t, err, "rpc error: code = NotFound desc = original url is not found")
+}
+
+func TestURLHandler_CreateURLAliasBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv, lis := startGRPCServer()
+	defer srv.Stop()
+	defer lis.Close()
+
+	urlService := mock_service.NewMockURL(ctrl)
+	urlpb.RegisterEventServiceServer(srv, urlHandler{
+		url: urlService,
+	})
+
+	ctx := context.Background()
+
+	conn, err := grpc.DialContext(ctx, "",
+		grpc.WithContextDialer(getDialer(lis)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := urlpb.NewEventServiceClient(conn)
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	var urls []entity.URL
+	gomock.InOrder(
+		urlService.EXPECT().CreateURLAliasBatch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, batch []entity.URL) ([]urlservice.CreateResult, error) {
+				urls = batch
+				return []urlservice.CreateResult{
+					{Alias: "testtest11"},
+					{Err: storageerrors.ErrURLAliasExists},
+					{Err: urlservice.ErrSchemeNotAllowed},
+				}, nil
+			}),
+		urlService.EXPECT().CreateURLAliasBatch(gomock.Any(), []entity.URL{{Original: "http://google.com"}}).
+			Return(nil, urlservice.ErrInternalError),
+	)
+
+	resp, err := client.CreateURLAliasBatch(ctx, &urlpb.CreateURLAliasBatchRequest{
+		Urls: []*urlpb.CreateURLAliasRequest{
+			{Original: "http://google.com", ExpiresAt: timestamppb.New(expiresAt)},
+			{Original: "http://ya.ru", Ttl: durationpb.New(-time.Hour)},
+			{Original: "http://go.dev", Alias: "golang"},
+			{Original: "javascript:alert(1)"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, urls, 3)
+	require.Equal(t, "http://google.com", urls[0].Original)
+	require.True(t, expiresAt.Equal(urls[0].ExpiresAt))
+	require.Equal(t, entity.URL{Original: "http://go.dev", Alias: "golang"}, urls[1])
+	require.Len(t, resp.GetResults(), 4)
+	require.Equal(t, "testtest11", resp.GetResults()[0].GetAlias())
+	require.Equal(t, expiresAt, resp.GetResults()[0].GetExpiresAt().AsTime().Local())
+	require.Equal(t, urlservice.ErrInvalidTTL.Error(), resp.GetResults()[1].GetError())
+	require.Equal(t, storageerrors.ErrURLAliasExists.Error(), resp.GetResults()[2].GetError())
+	require.Empty(t, resp.GetResults()[2].GetAlias())
+	require.Empty(t, resp.GetResults()[2].GetReason())
+	require.Equal(t, urlservice.ErrSchemeNotAllowed.Error(), resp.GetResults()[3].GetError())
+	require.Equal(t, urlservice.ReasonSchemeNotAllowed, resp.GetResults()[3].GetReason())
+
+	_, err = client.CreateURLAliasBatch(ctx, &urlpb.CreateURLAliasBatchRequest{
+		Urls: []*urlpb.CreateURLAliasRequest{{Original: "http://google.com"}},
+	})
+	require.EqualError(t, err, "rpc error: code = Internal desc = internal error")
+}
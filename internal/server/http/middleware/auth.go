@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	httpresponse "github.com/romandnk/shortener/internal/server/http/v1/response"
+	authservice "github.com/romandnk/shortener/internal/service/auth"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+)
+
+// Auth requires an api key for requests changing data,
+// reading requests stay anonymous.
+func (m *MW) Auth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !m.auth.Enabled() || isSafeMethod(ctx.Request.Method) {
+			ctx.Next()
+			return
+		}
+
+		key, err := m.auth.Authenticate(ctx, apiKey(ctx.Request))
+		if err != nil {
+			code := http.StatusUnauthorized
+			if errors.Is(err, authservice.ErrInternalError) {
+				code = http.StatusInternalServerError
+			}
+			ctx.Header("WWW-Authenticate", "Bearer")
+			httpresponse.SentErrorResponse(ctx, code, "error authenticating request", err)
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(authservice.WithAPIKey(ctx.Request.Context(), key))
+
+		ctx.Next()
+	}
+}
+
+// Admin requires an admin api key for all requests, it reuses the key authenticated by Auth.
+// Requests are forbidden if auth is disabled.
+func (m *MW) Admin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !m.auth.Enabled() {
+			httpresponse.SentErrorResponse(ctx, http.StatusForbidden, "error authorizing request", authservice.ErrAuthDisabled)
+			return
+		}
+
+		key, ok := authservice.APIKeyFromContext(ctx.Request.Context())
+		if !ok {
+			var err error
+			key, err = m.auth.Authenticate(ctx, apiKey(ctx.Request))
+			if err != nil {
+				code := http.StatusUnauthorized
+				if errors.Is(err, authservice.ErrInternalError) {
+					code = http.StatusInternalServerError
+				}
+				ctx.Header("WWW-Authenticate", "Bearer")
+				httpresponse.SentErrorResponse(ctx, code, "error authenticating request", err)
+				return
+			}
+			ctx.Request = ctx.Request.WithContext(authservice.WithAPIKey(ctx.Request.Context(), key))
+		}
+
+		if !m.auth.IsAdmin(key) {
+			httpresponse.SentErrorResponse(ctx, http.StatusForbidden, "error authorizing request", authservice.ErrNotAdmin)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// apiKey takes the key from X-API-Key header or from bearer authorization.
+func apiKey(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) > len(bearerPrefix) && strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		return authorization[len(bearerPrefix):]
+	}
+
+	return ""
+}
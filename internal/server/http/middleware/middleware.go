@@ -2,7 +2,11 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/romandnk/shortener/internal/metrics"
+	"github.com/romandnk/shortener/internal/service"
 	"github.com/romandnk/shortener/pkg/logger"
+	"github.com/romandnk/shortener/pkg/ratelimit"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"time"
@@ -11,12 +15,18 @@ import (
 var Module = fx.Module("middleware", fx.Provide(New))
 
 type MW struct {
-	logger logger.Logger
+	logger  logger.Logger
+	auth    service.Auth
+	limiter ratelimit.Limiter
+	metrics *metrics.Metrics
 }
 
-func New(logger logger.Logger) *MW {
+func New(logger logger.Logger, services *service.Services, limiter ratelimit.Limiter, metrics *metrics.Metrics) *MW {
 	return &MW{
-		logger: logger,
+		logger:  logger,
+		auth:    services.Auth,
+		limiter: limiter,
+		metrics: metrics,
 	}
 }
 
@@ -32,6 +42,8 @@ func (m *MW) Logging() gin.HandlerFunc {
 
 		code := ctx.Writer.Status()
 
+		m.metrics.ObserveHTTP(ctx.Request.Method, ctx.FullPath(), code, duration)
+
 		msg := "HTTP requests"
 
 		m.logger.Info(msg,
@@ -43,6 +55,7 @@ func (m *MW) Logging() gin.HandlerFunc {
 			zap.Int("status code", code),
 			zap.String("processing time", info.Latency),
 			zap.String("user agent", info.UserAgent),
+			zap.String("trace id", trace.SpanContextFromContext(ctx.Request.Context()).TraceID().String()),
 		)
 	}
 }
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	httpresponse "github.com/romandnk/shortener/internal/server/http/v1/response"
+	authservice "github.com/romandnk/shortener/internal/service/auth"
+	"go.uber.org/zap"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+var errTooManyRequests = errors.New("too many requests")
+
+// RateLimitIP limits requests per client ip. It must precede Auth,
+// so that requests with wrong or missing api keys are limited before the key lookup.
+func (m *MW) RateLimitIP() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		m.rateLimit(ctx, "ip:"+ctx.ClientIP())
+	}
+}
+
+// RateLimitKey limits requests per api key on top of the ip limit, anonymous requests pass.
+// It must follow Auth to see the authenticated key.
+func (m *MW) RateLimitKey() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		apiKey, ok := authservice.APIKeyFromContext(ctx.Request.Context())
+		if !ok {
+			ctx.Next()
+			return
+		}
+		m.rateLimit(ctx, "key:"+apiKey.Name)
+	}
+}
+
+func (m *MW) rateLimit(ctx *gin.Context, key string) {
+	res, err := m.limiter.Allow(ctx, key)
+	if err != nil {
+		// the limiter is not a reason to stop serving
+		m.logger.Error("MW.RateLimit - m.limiter.Allow", zap.String("error", err.Error()))
+		ctx.Next()
+		return
+	}
+
+	if !res.Allowed {
+		retryAfter := int(math.Ceil(res.RetryAfter.Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		httpresponse.SentErrorResponse(ctx, http.StatusTooManyRequests, "rate limit exceeded", errTooManyRequests)
+		return
+	}
+
+	ctx.Next()
+}
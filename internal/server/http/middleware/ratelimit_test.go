@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/romandnk/shortener/internal/entity"
+	"github.com/romandnk/shortener/internal/service"
+	authservice "github.com/romandnk/shortener/internal/service/auth"
+	mock_logger "github.com/romandnk/shortener/pkg/logger/mock"
+	"github.com/romandnk/shortener/pkg/ratelimit"
+	mock_ratelimit "github.com/romandnk/shortener/pkg/ratelimit/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMW_RateLimit(t *testing.T) {
+	type mockBehaviour func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger)
+
+	testCases := []struct {
+		name               string
+		trustedProxies     []string
+		forwardedFor       []string
+		apiKey             string
+		mock               mockBehaviour
+		expectedCode       int
+		expectedRetryAfter string
+	}{
+		{
+			name: "allowed by ip",
+			mock: func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {
+				limiter.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1").Return(ratelimit.Result{Allowed: true}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "spoofed forwarded header is ignored",
+			forwardedFor: []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"},
+			mock: func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {
+				gomock.InOrder(
+					limiter.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1").Return(ratelimit.Result{Allowed: true}, nil).Times(2),
+					limiter.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1").Return(ratelimit.Result{RetryAfter: 1500 * time.Millisecond}, nil),
+				)
+			},
+			expectedCode:       http.StatusTooManyRequests,
+			expectedRetryAfter: "2",
+		},
+		{
+			name:           "forwarded header of trusted proxy",
+			trustedProxies: []string{"192.0.2.0/24"},
+			forwardedFor:   []string{"203.0.113.1"},
+			mock: func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {
+				limiter.EXPECT().Allow(gomock.Any(), "ip:203.0.113.1").Return(ratelimit.Result{RetryAfter: time.Millisecond}, nil)
+			},
+			expectedCode:       http.StatusTooManyRequests,
+			expectedRetryAfter: "1",
+		},
+		{
+			name:   "limited by api key",
+			apiKey: "ci",
+			mock: func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {
+				limiter.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1").Return(ratelimit.Result{Allowed: true}, nil)
+				limiter.EXPECT().Allow(gomock.Any(), "key:ci").Return(ratelimit.Result{RetryAfter: 3 * time.Second}, nil)
+			},
+			expectedCode:       http.StatusTooManyRequests,
+			expectedRetryAfter: "3",
+		},
+		{
+			name: "limiter error",
+			mock: func(limiter *mock_ratelimit.MockLimiter, log *mock_logger.MockLogger) {
+				limiter.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1").Return(ratelimit.Result{}, errors.New("connection refused"))
+				log.EXPECT().Error("MW.RateLimit - m.limiter.Allow", []any{zap.String("error", "connection refused")})
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			limiter := mock_ratelimit.NewMockLimiter(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			tc.mock(limiter, log)
+
+			mw := New(log, &service.Services{}, limiter, nil)
+
+			router := gin.New()
+			require.NoError(t, router.SetTrustedProxies(tc.trustedProxies))
+			router.GET("/test", mw.RateLimitIP(), func(ctx *gin.Context) {
+				if tc.apiKey != "" {
+					ctx.Request = ctx.Request.WithContext(authservice.WithAPIKey(ctx.Request.Context(), entity.APIKey{Name: tc.apiKey}))
+				}
+			}, mw.RateLimitKey(), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			forwardedFor := tc.forwardedFor
+			if len(forwardedFor) == 0 {
+				forwardedFor = []string{""}
+			}
+
+			var w *httptest.ResponseRecorder
+			for _, ip := range forwardedFor {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.RemoteAddr = "192.0.2.1:50000"
+				if ip != "" {
+					req.Header.Set("X-Forwarded-For", ip)
+					req.Header.Set("X-Real-IP", ip)
+				}
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+			}
+
+			require.Equal(t, tc.expectedCode, w.Code)
+			require.Equal(t, tc.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
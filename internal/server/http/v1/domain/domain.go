@@ -0,0 +1,128 @@
+package domainroute
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/romandnk/shortener/internal/entity"
+	httpresponse "github.com/romandnk/shortener/internal/server/http/v1/response"
+	"github.com/romandnk/shortener/internal/service"
+	domainservice "github.com/romandnk/shortener/internal/service/domain"
+	"net/http"
+)
+
+type DomainRoutes struct {
+	domain service.Domain
+}
+
+func NewDomainRoutes(g *gin.RouterGroup, domain service.Domain) {
+	r := &DomainRoutes{
+		domain: domain,
+	}
+
+	g.GET("/", r.ListDomainRules)
+	g.POST("/", r.AddDomainRule)
+	g.DELETE("/:pattern", r.RemoveDomainRule)
+}
+
+// AddDomainRule
+//
+//	@Summary		Add domain rule
+//	@Description	Block or allow links to hosts matching the pattern. An existing pattern is moved to the list. Links to blocked hosts stop redirecting immediately.
+//	@UUID			300
+//	@Param			params	body		AddDomainRuleRequest	true	"Required JSON body with host pattern and list"
+//	@Success		201		{object}	DomainRule				"Domain rule was saved successfully"
+//	@Failure		400		{object}	httpresponse.Response	"Invalid input data"
+//	@Failure		401		{object}	httpresponse.Response	"API key is missing or invalid"
+//	@Failure		403		{object}	httpresponse.Response	"API key is not an admin one"
+//	@Failure		500		{object}	httpresponse.Response	"Internal error"
+//	@Security		ApiKeyAuth
+//	@Router			/domains [post]
+//	@Tags			Domain
+func (r *DomainRoutes) AddDomainRule(ctx *gin.Context) {
+	var params AddDomainRuleRequest
+
+	if err := ctx.BindJSON(&params); err != nil {
+		httpresponse.SentErrorResponse(ctx, http.StatusBadRequest, "error binding json body", err)
+		return
+	}
+
+	rule, err := r.domain.AddRule(ctx, params.Pattern, entity.DomainList(params.List))
+	if err != nil {
+		httpresponse.SentErrorResponse(ctx, errorCode(err), "error adding domain rule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, DomainRule{
+		Pattern: rule.Pattern,
+		List:    string(rule.List),
+	})
+}
+
+// ListDomainRules
+//
+//	@Summary		List domain rules
+//	@Description	Get all domain rules sorted by pattern.
+//	@UUID			301
+//	@Success		200	{object}	ListDomainRulesResponse	"Domain rules were received successfully"
+//	@Failure		401	{object}	httpresponse.Response	"API key is missing or invalid"
+//	@Failure		403	{object}	httpresponse.Response	"API key is not an admin one"
+//	@Failure		500	{object}	httpresponse.Response	"Internal error"
+//	@Security		ApiKeyAuth
+//	@Router			/domains [get]
+//	@Tags			Domain
+func (r *DomainRoutes) ListDomainRules(ctx *gin.Context) {
+	rules, err := r.domain.ListRules(ctx)
+	if err != nil {
+		httpresponse.SentErrorResponse(ctx, errorCode(err), "error listing domain rules", err)
+		return
+	}
+
+	resp := ListDomainRulesResponse{
+		Rules: make([]DomainRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, DomainRule{
+			Pattern: rule.Pattern,
+			List:    string(rule.List),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// RemoveDomainRule
+//
+//	@Summary		Remove domain rule
+//	@Description	Remove the rule of the pattern, links to matching hosts are resolved again unless another rule matches.
+//	@UUID			302
+//	@Param			pattern	path	string	true	"Required path param with host pattern"
+//	@Success		204		"Domain rule was removed successfully"
+//	@Failure		400		{object}	httpresponse.Response	"Invalid input data"
+//	@Failure		401		{object}	httpresponse.Response	"API key is missing or invalid"
+//	@Failure		403		{object}	httpresponse.Response	"API key is not an admin one"
+//	@Failure		404		{object}	httpresponse.Response	"Domain rule is not found"
+//	@Failure		500		{object}	httpresponse.Response	"Internal error"
+//	@Security		ApiKeyAuth
+//	@Router			/domains/:pattern [delete]
+//	@Tags			Domain
+func (r *DomainRoutes) RemoveDomainRule(ctx *gin.Context) {
+	err := r.domain.RemoveRule(ctx, ctx.Param("pattern"))
+	if err != nil {
+		httpresponse.SentErrorResponse(ctx, errorCode(err), "error removing domain rule", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// errorCode maps errors of the domain service to HTTP codes.
+func errorCode(err error) int {
+	switch {
+	case errors.Is(err, domainservice.ErrInternalError):
+		return http.StatusInternalServerError
+	case errors.Is(err, domainservice.ErrRuleNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
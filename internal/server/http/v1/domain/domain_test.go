@@ -0,0 +1,242 @@
+package domainroute
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/romandnk/shortener/internal/entity"
+	domainservice "github.com/romandnk/shortener/internal/service/domain"
+	mock_service "github.com/romandnk/shortener/internal/service/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDomainRoutes_AddDomainRule(t *testing.T) {
+	url := "/api/v1/domains"
+
+	testCases := []struct {
+		name                 string
+		mock                 func(m *mock_service.MockDomain)
+		requestBody          map[string]interface{}
+		expectedResponseBody string
+		expectedHTTPCode     int
+	}{
+		{
+			name: "OK",
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().AddRule(gomock.Any(), "*.Evil.com", entity.DomainBlock).
+					Return(entity.DomainRule{Pattern: "*.evil.com", List: entity.DomainBlock}, nil)
+			},
+			requestBody: map[string]interface{}{
+				"pattern": "*.Evil.com",
+				"list":    "block",
+			},
+			expectedResponseBody: `{"pattern":"*.evil.com","list":"block"}`,
+			expectedHTTPCode:     http.StatusCreated,
+		},
+		{
+			name: "invalid list",
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().AddRule(gomock.Any(), "evil.com", entity.DomainList("deny")).
+					Return(entity.DomainRule{}, domainservice.ErrInvalidList)
+			},
+			requestBody: map[string]interface{}{
+				"pattern": "evil.com",
+				"list":    "deny",
+			},
+			expectedResponseBody: `{"message":"error adding domain rule","error":"domain list must be block or allow"}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
+		{
+			name: "invalid json",
+			requestBody: map[string]interface{}{
+				"pattern": 1,
+			},
+			expectedResponseBody: `{"message":"error binding json body","error":"json: cannot unmarshal number into Go struct field AddDomainRuleRequest.pattern of type string"}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
+		{
+			name: "internal error",
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().AddRule(gomock.Any(), "evil.com", entity.DomainBlock).
+					Return(entity.DomainRule{}, domainservice.ErrInternalError)
+			},
+			requestBody: map[string]interface{}{
+				"pattern": "evil.com",
+				"list":    "block",
+			},
+			expectedResponseBody: `{"message":"error adding domain rule","error":"internal error"}`,
+			expectedHTTPCode:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			domainService := mock_service.NewMockDomain(ctrl)
+			if tc.mock != nil {
+				tc.mock(domainService)
+			}
+
+			domainR := DomainRoutes{
+				domain: domainService,
+			}
+
+			r := gin.Default()
+			r.POST(url, domainR.AddDomainRule)
+
+			jsonBody, err := json.Marshal(tc.requestBody)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedHTTPCode, w.Code)
+			require.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestDomainRoutes_ListDomainRules(t *testing.T) {
+	url := "/api/v1/domains"
+
+	testCases := []struct {
+		name                 string
+		mock                 func(m *mock_service.MockDomain)
+		expectedResponseBody string
+		expectedHTTPCode     int
+	}{
+		{
+			name: "OK",
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().ListRules(gomock.Any()).Return([]entity.DomainRule{
+					{Pattern: "*.evil.com", List: entity.DomainBlock},
+					{Pattern: "go.dev", List: entity.DomainAllow},
+				}, nil)
+			},
+			expectedResponseBody: `{"rules":[{"pattern":"*.evil.com","list":"block"},{"pattern":"go.dev","list":"allow"}]}`,
+			expectedHTTPCode:     http.StatusOK,
+		},
+		{
+			name: "OK no rules",
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().ListRules(gomock.Any()).Return(nil, nil)
+			},
+			expectedResponseBody: `{"rules":[]}`,
+			expectedHTTPCode:     http.StatusOK,
+		},
+		{
+			name: "internal error",
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().ListRules(gomock.Any()).Return(nil, domainservice.ErrInternalError)
+			},
+			expectedResponseBody: `{"message":"error listing domain rules","error":"internal error"}`,
+			expectedHTTPCode:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			domainService := mock_service.NewMockDomain(ctrl)
+			tc.mock(domainService)
+
+			domainR := DomainRoutes{
+				domain: domainService,
+			}
+
+			r := gin.Default()
+			r.GET(url, domainR.ListDomainRules)
+
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedHTTPCode, w.Code)
+			require.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestDomainRoutes_RemoveDomainRule(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		mock                 func(m *mock_service.MockDomain)
+		pathParam            string
+		expectedResponseBody string
+		expectedHTTPCode     int
+	}{
+		{
+			name: "OK",
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().RemoveRule(gomock.Any(), "*.evil.com").Return(nil)
+			},
+			pathParam:        "*.evil.com",
+			expectedHTTPCode: http.StatusNoContent,
+		},
+		{
+			name: "rule is not found",
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().RemoveRule(gomock.Any(), "evil.com").Return(domainservice.ErrRuleNotFound)
+			},
+			pathParam:            "evil.com",
+			expectedResponseBody: `{"message":"error removing domain rule","error":"domain rule is not found"}`,
+			expectedHTTPCode:     http.StatusNotFound,
+		},
+		{
+			name: "invalid pattern",
+			mock: func(m *mock_service.MockDomain) {
+				m.EXPECT().RemoveRule(gomock.Any(), "evil.*.com").Return(domainservice.ErrInvalidPattern)
+			},
+			pathParam:            "evil.*.com",
+			expectedResponseBody: `{"message":"error removing domain rule","error":"domain pattern must be a host or *. followed by a host"}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			domainService := mock_service.NewMockDomain(ctrl)
+			tc.mock(domainService)
+
+			domainR := DomainRoutes{
+				domain: domainService,
+			}
+
+			r := gin.Default()
+			r.DELETE("/api/v1/domains/:pattern", domainR.RemoveDomainRule)
+
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodDelete, "/api/v1/domains/"+tc.pathParam, nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedHTTPCode, w.Code)
+			require.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
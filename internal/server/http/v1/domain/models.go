@@ -0,0 +1,17 @@
+package domainroute
+
+type AddDomainRuleRequest struct {
+	// host like example.com or *. followed by a host to match it and its subdomains
+	Pattern string `json:"pattern"`
+	// block or allow
+	List string `json:"list"`
+}
+
+type DomainRule struct {
+	Pattern string `json:"pattern"`
+	List    string `json:"list"`
+}
+
+type ListDomainRulesResponse struct {
+	Rules []DomainRule `json:"rules"`
+}
@@ -3,23 +3,30 @@ package v1
 import (
 	"github.com/gin-gonic/gin"
 	docs "github.com/romandnk/shortener/docs"
+	"github.com/romandnk/shortener/internal/metrics"
 	"github.com/romandnk/shortener/internal/server/http/middleware"
+	domainroute "github.com/romandnk/shortener/internal/server/http/v1/domain"
+	redirectroute "github.com/romandnk/shortener/internal/server/http/v1/redirect"
 	servicesroute "github.com/romandnk/shortener/internal/server/http/v1/services"
 	urlroute "github.com/romandnk/shortener/internal/server/http/v1/url"
 	"github.com/romandnk/shortener/internal/service"
+	"github.com/romandnk/shortener/pkg/health"
+	"github.com/romandnk/shortener/pkg/httpserver"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/fx"
 	"net/http"
-	"sync/atomic"
 )
 
 var Module = fx.Module("HTTPHandler",
 	fx.Provide(
 		fx.Annotate(
-			func(ok *atomic.Bool, services *service.Services, mw *middleware.MW) *gin.Engine {
-				h := NewHandler(services, mw)
-				return h.InitRoutes(ok)
+			func(health *health.Health, services *service.Services, mw *middleware.MW, redirect redirectroute.Config, metrics *metrics.Metrics, server httpserver.Config) (*gin.Engine, error) {
+				if err := redirect.Validate(); err != nil {
+					return nil, err
+				}
+				h := NewHandler(services, mw, redirect, metrics)
+				return h.InitRoutes(health, server.TrustedProxies)
 			},
 			fx.As(new(http.Handler)),
 		),
@@ -30,18 +37,29 @@ type Handler struct {
 	engine   *gin.Engine
 	services *service.Services
 	mw       *middleware.MW
+	redirect redirectroute.Config
+	metrics  *metrics.Metrics
 }
 
-func NewHandler(services *service.Services, mw *middleware.MW) *Handler {
+func NewHandler(services *service.Services, mw *middleware.MW, redirect redirectroute.Config, metrics *metrics.Metrics) *Handler {
 	return &Handler{
 		services: services,
 		mw:       mw,
+		redirect: redirect,
+		metrics:  metrics,
 	}
 }
 
-func (h *Handler) InitRoutes(ok *atomic.Bool) *gin.Engine {
+// InitRoutes takes client ips from forwarding headers only of trusted proxies,
+// otherwise clients could escape the ip rate limit by sending a new header each request.
+func (h *Handler) InitRoutes(health *health.Health, trustedProxies []string) (*gin.Engine, error) {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	// handlers pass gin context to services, values such as spans come from the request context
+	router.ContextWithFallback = true
 	h.engine = router
 
 	docs.SwaggerInfo.BasePath = "/api/v1"
@@ -50,17 +68,27 @@ func (h *Handler) InitRoutes(ok *atomic.Bool) *gin.Engine {
 	services := router.Group("/services")
 	{
 		// group for service information
-		servicesroute.NewHealthCheckRoutes(services, ok)
+		servicesroute.NewHealthCheckRoutes(services, health)
+		servicesroute.NewMetricsRoutes(services, h.metrics.Handler())
 	}
 
-	api := router.Group("/api/v1", h.mw.Logging())
+	api := router.Group("/api/v1", h.mw.Tracing(), h.mw.Logging(), h.mw.RateLimitIP(), h.mw.Auth(), h.mw.RateLimitKey())
 	{
 		// urls management group
 		urls := api.Group("/urls")
 		{
-			urlroute.NewUrlRoutes(urls, h.services.URL)
+			urlroute.NewUrlRoutes(urls, h.services.URL, h.services.Click)
+		}
+
+		// domain rules management group, admin keys only
+		domains := api.Group("/domains", h.mw.Admin())
+		{
+			domainroute.NewDomainRoutes(domains, h.services.Domain)
 		}
 	}
 
-	return h.engine
+	// public short links
+	redirectroute.NewRedirectRoutes(router.Group("/", h.mw.Tracing(), h.mw.Logging(), h.mw.RateLimitIP()), h.services.URL, h.services.Click, h.redirect)
+
+	return h.engine, nil
 }
@@ -0,0 +1,21 @@
+package redirectroute
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type Config struct {
+	StatusCode int `yaml:"status_code" env:"REDIRECT_STATUS_CODE" env-default:"302"`
+}
+
+// Validate checks that status code is one of the HTTP redirect codes
+// which keep the browser on the original URL.
+func (c Config) Validate() error {
+	switch c.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return nil
+	default:
+		return fmt.Errorf("invalid redirect status code %d: allowed 301, 302, 307, 308", c.StatusCode)
+	}
+}
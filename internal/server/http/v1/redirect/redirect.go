@@ -0,0 +1,61 @@
+package redirectroute
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/romandnk/shortener/internal/entity"
+	httpresponse "github.com/romandnk/shortener/internal/server/http/v1/response"
+	"github.com/romandnk/shortener/internal/service"
+	urlservice "github.com/romandnk/shortener/internal/service/url"
+	"net/http"
+)
+
+type RedirectRoutes struct {
+	url   service.URL
+	click service.Click
+	code  int
+}
+
+func NewRedirectRoutes(g gin.IRoutes, url service.URL, click service.Click, cfg Config) {
+	r := &RedirectRoutes{
+		url:   url,
+		click: click,
+		code:  cfg.StatusCode,
+	}
+
+	g.GET("/:alias", r.Redirect)
+	g.HEAD("/:alias", r.Redirect)
+}
+
+// Redirect sends the client to the original URL of the alias.
+// The route is mounted at the root of the server, outside the API base path.
+// Only GET requests are counted as clicks, HEAD ones are usually sent by link previews.
+func (r *RedirectRoutes) Redirect(ctx *gin.Context) {
+	alias := ctx.Param("alias")
+
+	original, err := r.url.GetOriginalByAlias(ctx, alias)
+	if err != nil {
+		code := http.StatusNotFound
+		if errors.Is(err, urlservice.ErrInternalError) {
+			code = http.StatusInternalServerError
+		}
+		if errors.Is(err, urlservice.ErrOriginalURLExpired) {
+			code = http.StatusGone
+		}
+		if errors.Is(err, urlservice.ErrOriginalURLBlocked) {
+			code = http.StatusForbidden
+		}
+		httpresponse.SentErrorResponse(ctx, code, "error redirecting by alias", err)
+		return
+	}
+
+	if ctx.Request.Method == http.MethodGet {
+		r.click.RecordClick(entity.Click{
+			Alias:     alias,
+			Referrer:  ctx.Request.Referer(),
+			UserAgent: ctx.Request.UserAgent(),
+		}, ctx.ClientIP())
+	}
+
+	ctx.Redirect(r.code, original)
+}
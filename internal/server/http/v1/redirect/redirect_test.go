@@ -0,0 +1,197 @@
+package redirectroute
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/romandnk/shortener/internal/entity"
+	mock_service "github.com/romandnk/shortener/internal/service/mock"
+	urlservice "github.com/romandnk/shortener/internal/service/url"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectRoutes_Redirect(t *testing.T) {
+	type argsAlias struct {
+		input         string
+		output        string
+		expectedError error
+	}
+
+	type mockUrlBehaviour func(m *mock_service.MockURL, args argsAlias)
+
+	testCases := []struct {
+		name                 string
+		argsUrl              argsAlias
+		urlM                 mockUrlBehaviour
+		method               string
+		statusCode           int
+		pathParam            string
+		expectedLocation     string
+		expectedResponseBody string
+		expectedHTTPCode     int
+		expectedClick        bool
+	}{
+		{
+			name: "OK",
+			argsUrl: argsAlias{
+				input:  "testtest12",
+				output: "https://google.com",
+			},
+			urlM: func(m *mock_service.MockURL, args argsAlias) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			method:           http.MethodGet,
+			statusCode:       http.StatusFound,
+			pathParam:        "testtest12",
+			expectedLocation: "https://google.com",
+			expectedHTTPCode: http.StatusFound,
+			expectedClick:    true,
+		},
+		{
+			name: "OK permanent redirect",
+			argsUrl: argsAlias{
+				input:  "testtest12",
+				output: "https://google.com",
+			},
+			urlM: func(m *mock_service.MockURL, args argsAlias) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			method:           http.MethodGet,
+			statusCode:       http.StatusMovedPermanently,
+			pathParam:        "testtest12",
+			expectedLocation: "https://google.com",
+			expectedHTTPCode: http.StatusMovedPermanently,
+			expectedClick:    true,
+		},
+		{
+			name: "OK head",
+			argsUrl: argsAlias{
+				input:  "testtest12",
+				output: "https://google.com",
+			},
+			urlM: func(m *mock_service.MockURL, args argsAlias) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			method:           http.MethodHead,
+			statusCode:       http.StatusTemporaryRedirect,
+			pathParam:        "testtest12",
+			expectedLocation: "https://google.com",
+			expectedHTTPCode: http.StatusTemporaryRedirect,
+		},
+		{
+			name: "original url is not found",
+			argsUrl: argsAlias{
+				input:         "testtest12",
+				expectedError: urlservice.ErrOriginalURLNotFound,
+			},
+			urlM: func(m *mock_service.MockURL, args argsAlias) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			method:               http.MethodGet,
+			statusCode:           http.StatusFound,
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"message":"error redirecting by alias","error":"original url is not found"}`,
+			expectedHTTPCode:     http.StatusNotFound,
+		},
+		{
+			name: "original url is expired",
+			argsUrl: argsAlias{
+				input:         "testtest12",
+				expectedError: urlservice.ErrOriginalURLExpired,
+			},
+			urlM: func(m *mock_service.MockURL, args argsAlias) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			method:               http.MethodGet,
+			statusCode:           http.StatusFound,
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"message":"error redirecting by alias","error":"url alias is expired"}`,
+			expectedHTTPCode:     http.StatusGone,
+		},
+		{
+			name: "domain of original url is blocked",
+			argsUrl: argsAlias{
+				input:         "testtest12",
+				expectedError: urlservice.ErrOriginalURLBlocked,
+			},
+			urlM: func(m *mock_service.MockURL, args argsAlias) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			method:               http.MethodGet,
+			statusCode:           http.StatusFound,
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"message":"error redirecting by alias","error":"url alias is disabled, its domain is blocked"}`,
+			expectedHTTPCode:     http.StatusForbidden,
+		},
+		{
+			name: "internal error",
+			argsUrl: argsAlias{
+				input:         "testtest12",
+				expectedError: urlservice.ErrInternalError,
+			},
+			urlM: func(m *mock_service.MockURL, args argsAlias) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			method:               http.MethodGet,
+			statusCode:           http.StatusFound,
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"message":"error redirecting by alias","error":"internal error"}`,
+			expectedHTTPCode:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			urlService := mock_service.NewMockURL(ctrl)
+			clickService := mock_service.NewMockClick(ctrl)
+
+			if tc.urlM != nil {
+				tc.urlM(urlService, tc.argsUrl)
+			}
+			if tc.expectedClick {
+				clickService.EXPECT().RecordClick(entity.Click{
+					Alias:     tc.argsUrl.input,
+					Referrer:  "https://ref.com",
+					UserAgent: "test-agent",
+				}, "192.0.2.1")
+			}
+
+			r := gin.New()
+			NewRedirectRoutes(r, urlService, clickService, Config{StatusCode: tc.statusCode})
+
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(context.Background(), tc.method, "/"+tc.pathParam, nil)
+			require.NoError(t, err)
+			req.RemoteAddr = "192.0.2.1:1234"
+			req.Header.Set("Referer", "https://ref.com")
+			req.Header.Set("User-Agent", "test-agent")
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedHTTPCode, w.Code)
+			require.Equal(t, tc.expectedLocation, w.Header().Get("Location"))
+
+			if tc.expectedResponseBody != "" {
+				require.Equal(t, []byte(tc.expectedResponseBody), w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	for _, code := range []int{http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect} {
+		require.NoError(t, Config{StatusCode: code}.Validate())
+	}
+
+	for _, code := range []int{0, http.StatusOK, http.StatusSeeOther, http.StatusNotFound} {
+		require.Error(t, Config{StatusCode: code}.Validate())
+	}
+}
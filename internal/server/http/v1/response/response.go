@@ -5,6 +5,8 @@ import "github.com/gin-gonic/gin"
 type Response struct {
 	Message string `json:"message"`
 	Error   string `json:"error,omitempty"`
+	// machine readable reason of rejected input, e.g. SCHEME_NOT_ALLOWED or PRIVATE_HOST
+	Reason string `json:"reason,omitempty"`
 }
 
 func SentErrorResponse(c *gin.Context, status int, message string, err error) {
@@ -0,0 +1,30 @@
+package servicesroute
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type MetricsRoutes struct {
+	handler http.Handler
+}
+
+func NewMetricsRoutes(g *gin.RouterGroup, handler http.Handler) {
+	r := &MetricsRoutes{
+		handler: handler,
+	}
+
+	g.GET("/metrics", r.Metrics)
+}
+
+// Metrics
+//
+//	@Summary		Prometheus metrics
+//	@Description	Return metrics in Prometheus text format
+//	@UUID			201
+//	@Success		200	nil	"Metrics were successfully received"
+//	@Router			services/metrics [get]
+//	@Tags			services
+func (r *MetricsRoutes) Metrics(ctx *gin.Context) {
+	r.handler.ServeHTTP(ctx.Writer, ctx.Request)
+}
@@ -2,8 +2,8 @@ package servicesroute
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/romandnk/shortener/pkg/health"
 	"net/http"
-	"sync/atomic"
 )
 
 var (
@@ -13,12 +13,12 @@ var (
 )
 
 type HealthCheckRoutes struct {
-	ok *atomic.Bool
+	health *health.Health
 }
 
-func NewHealthCheckRoutes(g *gin.RouterGroup, ok *atomic.Bool) {
+func NewHealthCheckRoutes(g *gin.RouterGroup, health *health.Health) {
 	r := &HealthCheckRoutes{
-		ok: ok,
+		health: health,
 	}
 
 	g.GET("/liveness", r.LivenessProbe)
@@ -58,15 +58,19 @@ func (r *HealthCheckRoutes) LivenessProbe(ctx *gin.Context) {
 // ReadinessProbe
 //
 //	@Summary		K8s checks readiness probe
-//	@Description	Return a status okay if everything is initialized
+//	@Description	Ping dependencies of the application and return status of every component. Results are cached for a short time.
 //	@UUID			200
-//	@Success		200	nil	"Readiness probe is got over"
+//	@Success		200	{object}	health.Report	"All components are available"
+//	@Failure		503	{object}	health.Report	"Some component is unavailable or the application is shutting down"
 //	@Router			services/readiness [get]
 //	@Tags			services
 func (r *HealthCheckRoutes) ReadinessProbe(ctx *gin.Context) {
-	if r.ok == nil || !r.ok.Load() {
-		ctx.AbortWithStatus(http.StatusServiceUnavailable)
+	report := r.health.Check(ctx)
+
+	code := http.StatusOK
+	if !report.OK() {
+		code = http.StatusServiceUnavailable
 	}
 
-	ctx.AbortWithStatus(http.StatusOK)
+	ctx.JSON(code, report)
 }
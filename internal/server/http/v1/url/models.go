@@ -1,13 +1,59 @@
 package urlroute
 
+import "time"
+
 type CreateURLAliasRequest struct {
 	OriginalURL string `json:"original_url"`
+	// optional custom alias, generated if empty
+	Alias string `json:"alias,omitempty"`
+	// optional expiration time in RFC 3339 format, cannot be set together with ttl
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// optional time to live, e.g. "24h" or "90m"
+	TTL string `json:"ttl,omitempty"`
 }
 
 type CreateURLAliasResponse struct {
-	Alias string `json:"alias"`
+	Alias     string     `json:"alias"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type CreateURLAliasBatchRequest struct {
+	URLs []CreateURLAliasRequest `json:"urls"`
+}
+
+type CreateURLAliasBatchResponse struct {
+	// results in order of requested urls
+	Results []CreateURLAliasBatchResult `json:"results"`
+}
+
+type CreateURLAliasBatchResult struct {
+	Alias string `json:"alias,omitempty"`
+	// true if the original url was already shortened and its alias is returned
+	Existing  bool       `json:"existing,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// reason why the url was not shortened
+	Error string `json:"error,omitempty"`
+	// machine readable reason if the url is rejected by the policy
+	Reason string `json:"reason,omitempty"`
 }
 
 type GetOriginalByAliasResponse struct {
 	OriginalURL string `json:"original_url"`
 }
+
+type UpdateOriginalRequest struct {
+	OriginalURL string `json:"original_url"`
+}
+
+type GetURLStatsResponse struct {
+	Alias string `json:"alias"`
+	Total int64  `json:"total"`
+	// days without clicks are omitted
+	Daily []DailyClicks `json:"daily"`
+}
+
+type DailyClicks struct {
+	// UTC day in 2006-01-02 format
+	Date   string `json:"date"`
+	Clicks int64  `json:"clicks"`
+}
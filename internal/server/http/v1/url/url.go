@@ -3,34 +3,48 @@ package urlroute
 import (
 	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
 	httpresponse "github.com/romandnk/shortener/internal/server/http/v1/response"
 	"github.com/romandnk/shortener/internal/service"
+	clickservice "github.com/romandnk/shortener/internal/service/click"
 	urlservice "github.com/romandnk/shortener/internal/service/url"
 	"net/http"
+	"time"
 )
 
 type UrlRoutes struct {
-	url service.URL
+	url   service.URL
+	click service.Click
 }
 
-func NewUrlRoutes(g *gin.RouterGroup, url service.URL) {
+func NewUrlRoutes(g *gin.RouterGroup, url service.URL, click service.Click) {
 	r := &UrlRoutes{
-		url: url,
+		url:   url,
+		click: click,
 	}
 
 	g.POST("/", r.CreateURLAlias)
+	g.POST("/batch", r.CreateURLAliasBatch)
 	g.GET("/:alias", r.GetOriginalByAlias)
+	g.GET("/:alias/stats", r.GetURLStats)
+	g.PUT("/:alias", r.UpdateOriginal)
+	g.DELETE("/:alias", r.DeleteByAlias)
 }
 
 // CreateURLAlias
 //
 //	@Summary		Create short URL alias
-//	@Description	Create short new URL alias if not exists.
+//	@Description	Create short new URL alias if not exists. In idempotent mode the existing alias is returned with 200.
 //	@UUID			100
-//	@Param			params	body		CreateURLAliasRequest	true	"Required JSON body with original url"
+//	@Param			params	body		CreateURLAliasRequest	true	"Required JSON body with original url, optional custom alias and expiration"
+//	@Success		200		{object}	CreateURLAliasResponse	"URL alias already exists"
 //	@Success		201		{object}	CreateURLAliasResponse	"URL alias was created successfully"
-//	@Failure		400		{object}	httpresponse.Response	"Invalid input data"
+//	@Failure		400		{object}	httpresponse.Response	"Invalid input data, reason is set if the URL is rejected by the policy"
+//	@Failure		401		{object}	httpresponse.Response	"API key is missing or invalid"
+//	@Failure		403		{object}	httpresponse.Response	"Custom aliases are disabled"
 //	@Failure		500		{object}	httpresponse.Response	"Internal error"
+//	@Security		ApiKeyAuth
 //	@Router			/urls [post]
 //	@Tags			URL
 func (r *UrlRoutes) CreateURLAlias(ctx *gin.Context) {
@@ -41,19 +55,139 @@ func (r *UrlRoutes) CreateURLAlias(ctx *gin.Context) {
 		return
 	}
 
-	alias, err := r.url.CreateURLAlias(ctx, params.OriginalURL)
+	expiresAt, err := expirationTime(params)
+	if err != nil {
+		httpresponse.SentErrorResponse(ctx, http.StatusBadRequest, "error parsing expiration", err)
+		return
+	}
+
+	alias, existing, err := r.url.CreateURLAlias(ctx, entity.URL{
+		Original:  params.OriginalURL,
+		Alias:     params.Alias,
+		ExpiresAt: expiresAt,
+	})
 	if err != nil {
 		code := http.StatusBadRequest
+		if errors.Is(err, urlservice.ErrCustomAliasDisabled) {
+			code = http.StatusForbidden
+		}
 		if errors.Is(err, urlservice.ErrInternalError) {
 			code = http.StatusInternalServerError
 		}
-		httpresponse.SentErrorResponse(ctx, code, "error creating short url", err)
+		sentURLError(ctx, code, "error creating short url", err)
 		return
 	}
 
 	resp := CreateURLAliasResponse{Alias: alias}
 
-	ctx.JSON(http.StatusCreated, resp)
+	code := http.StatusCreated
+	if existing {
+		code = http.StatusOK
+	} else if !expiresAt.IsZero() {
+		resp.ExpiresAt = &expiresAt
+	}
+
+	ctx.JSON(code, resp)
+}
+
+// CreateURLAliasBatch
+//
+//	@Summary		Create short URL aliases in batch
+//	@Description	Create aliases of many URLs with a fixed number of storage calls. Results are returned in order of URLs, invalid or conflicting URLs get an error and do not fail the batch.
+//	@UUID			105
+//	@Param			params	body		CreateURLAliasBatchRequest	true	"Required JSON body with urls in the same format as for a single url"
+//	@Success		200		{object}	CreateURLAliasBatchResponse	"Batch was processed"
+//	@Failure		400		{object}	httpresponse.Response		"Invalid input data"
+//	@Failure		401		{object}	httpresponse.Response		"API key is missing or invalid"
+//	@Failure		500		{object}	httpresponse.Response		"Internal error"
+//	@Security		ApiKeyAuth
+//	@Router			/urls/batch [post]
+//	@Tags			URL
+func (r *UrlRoutes) CreateURLAliasBatch(ctx *gin.Context) {
+	var params CreateURLAliasBatchRequest
+
+	if err := ctx.BindJSON(&params); err != nil {
+		httpresponse.SentErrorResponse(ctx, http.StatusBadRequest, "error binding json body", err)
+		return
+	}
+
+	resp := CreateURLAliasBatchResponse{
+		Results: make([]CreateURLAliasBatchResult, len(params.URLs)),
+	}
+
+	// urls with invalid expiration are not sent to the service
+	urls := make([]entity.URL, 0, len(params.URLs))
+	positions := make([]int, 0, len(params.URLs))
+	for i, param := range params.URLs {
+		expiresAt, err := expirationTime(param)
+		if err != nil {
+			resp.Results[i].Error = err.Error()
+			continue
+		}
+		urls = append(urls, entity.URL{
+			Original:  param.OriginalURL,
+			Alias:     param.Alias,
+			ExpiresAt: expiresAt,
+		})
+		positions = append(positions, i)
+	}
+
+	if len(urls) == 0 && len(params.URLs) > 0 {
+		ctx.JSON(http.StatusOK, resp)
+		return
+	}
+
+	results, err := r.url.CreateURLAliasBatch(ctx, urls)
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, urlservice.ErrInternalError) {
+			code = http.StatusInternalServerError
+		}
+		httpresponse.SentErrorResponse(ctx, code, "error creating short urls", err)
+		return
+	}
+
+	for i, res := range results {
+		result := &resp.Results[positions[i]]
+		if res.Err != nil {
+			result.Error = res.Err.Error()
+			var rejected *urlservice.RejectedURLError
+			if errors.As(res.Err, &rejected) {
+				result.Reason = rejected.Reason
+			}
+			continue
+		}
+		result.Alias = res.Alias
+		result.Existing = res.Existing
+		if !res.Existing && !urls[i].ExpiresAt.IsZero() {
+			result.ExpiresAt = &urls[i].ExpiresAt
+		}
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func expirationTime(params CreateURLAliasRequest) (time.Time, error) {
+	var (
+		expiresAt time.Time
+		ttl       time.Duration
+		err       error
+	)
+
+	if params.ExpiresAt != nil {
+		expiresAt = *params.ExpiresAt
+	}
+	if params.TTL != "" {
+		ttl, err = time.ParseDuration(params.TTL)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ttl == 0 {
+			return time.Time{}, urlservice.ErrInvalidTTL
+		}
+	}
+
+	return urlservice.ExpirationTime(expiresAt, ttl)
 }
 
 // GetOriginalByAlias
@@ -64,6 +198,7 @@ func (r *UrlRoutes) CreateURLAlias(ctx *gin.Context) {
 //	@Param			alias	path		string						true	"Required path param with url alias"
 //	@Success		200		{object}	GetOriginalByAliasResponse	"Original URL was received successfully"
 //	@Failure		400		{object}	httpresponse.Response		"Invalid input data"
+//	@Failure		410		{object}	httpresponse.Response		"URL alias is expired"
 //	@Failure		500		{object}	httpresponse.Response		"Internal error"
 //	@Router			/urls/:alias [get]
 //	@Tags			URL
@@ -76,11 +211,153 @@ func (r *UrlRoutes) GetOriginalByAlias(ctx *gin.Context) {
 		if errors.Is(err, urlservice.ErrInternalError) {
 			code = http.StatusInternalServerError
 		}
+		if errors.Is(err, urlservice.ErrOriginalURLExpired) {
+			code = http.StatusGone
+		}
+		if errors.Is(err, urlservice.ErrOriginalURLBlocked) {
+			code = http.StatusForbidden
+		}
 		httpresponse.SentErrorResponse(ctx, code, "error getting original url by alias", err)
 		return
 	}
 
+	r.click.RecordClick(entity.Click{
+		Alias:     alias,
+		Referrer:  ctx.Request.Referer(),
+		UserAgent: ctx.Request.UserAgent(),
+	}, ctx.ClientIP())
+
 	resp := GetOriginalByAliasResponse{OriginalURL: original}
 
 	ctx.JSON(http.StatusOK, resp)
 }
+
+// UpdateOriginal
+//
+//	@Summary		Update original URL
+//	@Description	Point existing URL alias to another original URL. Expiration of the alias is kept.
+//	@UUID			103
+//	@Param			alias	path	string					true	"Required path param with url alias"
+//	@Param			params	body	UpdateOriginalRequest	true	"Required JSON body with new original url"
+//	@Success		204		"Original URL was updated successfully"
+//	@Failure		400		{object}	httpresponse.Response	"Invalid input data, reason is set if the URL is rejected by the policy"
+//	@Failure		401		{object}	httpresponse.Response	"API key is missing or invalid"
+//	@Failure		404		{object}	httpresponse.Response	"URL alias is not found"
+//	@Failure		500		{object}	httpresponse.Response	"Internal error"
+//	@Security		ApiKeyAuth
+//	@Router			/urls/:alias [put]
+//	@Tags			URL
+func (r *UrlRoutes) UpdateOriginal(ctx *gin.Context) {
+	alias := ctx.Param("alias")
+
+	var params UpdateOriginalRequest
+
+	if err := ctx.BindJSON(&params); err != nil {
+		httpresponse.SentErrorResponse(ctx, http.StatusBadRequest, "error binding json body", err)
+		return
+	}
+
+	err := r.url.UpdateOriginal(ctx, alias, params.OriginalURL)
+	if err != nil {
+		sentURLError(ctx, errorCode(err), "error updating original url", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteByAlias
+//
+//	@Summary		Delete URL alias
+//	@Description	Delete URL alias, the alias stops redirecting immediately.
+//	@UUID			104
+//	@Param			alias	path	string	true	"Required path param with url alias"
+//	@Success		204		"URL alias was deleted successfully"
+//	@Failure		400		{object}	httpresponse.Response	"Invalid input data"
+//	@Failure		401		{object}	httpresponse.Response	"API key is missing or invalid"
+//	@Failure		404		{object}	httpresponse.Response	"URL alias is not found"
+//	@Failure		500		{object}	httpresponse.Response	"Internal error"
+//	@Security		ApiKeyAuth
+//	@Router			/urls/:alias [delete]
+//	@Tags			URL
+func (r *UrlRoutes) DeleteByAlias(ctx *gin.Context) {
+	alias := ctx.Param("alias")
+
+	err := r.url.DeleteByAlias(ctx, alias)
+	if err != nil {
+		httpresponse.SentErrorResponse(ctx, errorCode(err), "error deleting url alias", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// sentURLError adds the reason to responses about urls rejected by the policy.
+func sentURLError(ctx *gin.Context, code int, message string, err error) {
+	var rejected *urlservice.RejectedURLError
+	if !errors.As(err, &rejected) {
+		httpresponse.SentErrorResponse(ctx, code, message, err)
+		return
+	}
+
+	ctx.AbortWithStatusJSON(code, httpresponse.Response{
+		Message: message,
+		Error:   err.Error(),
+		Reason:  rejected.Reason,
+	})
+}
+
+// errorCode maps errors of changing existing urls to HTTP codes.
+func errorCode(err error) int {
+	switch {
+	case errors.Is(err, urlservice.ErrInternalError):
+		return http.StatusInternalServerError
+	case errors.Is(err, urlservice.ErrOriginalURLNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// GetURLStats
+//
+//	@Summary		Get URL click stats
+//	@Description	Get total clicks of the URL alias and clicks per UTC day. Clicks are written asynchronously and appear with a small delay.
+//	@UUID			102
+//	@Param			alias	path		string					true	"Required path param with url alias"
+//	@Success		200		{object}	GetURLStatsResponse		"Stats were received successfully"
+//	@Failure		400		{object}	httpresponse.Response	"Invalid input data"
+//	@Failure		404		{object}	httpresponse.Response	"URL alias is not found"
+//	@Failure		500		{object}	httpresponse.Response	"Internal error"
+//	@Router			/urls/:alias/stats [get]
+//	@Tags			URL
+func (r *UrlRoutes) GetURLStats(ctx *gin.Context) {
+	alias := ctx.Param("alias")
+
+	stats, err := r.click.GetClickStats(ctx, alias)
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, clickservice.ErrURLNotFound) {
+			code = http.StatusNotFound
+		}
+		if errors.Is(err, clickservice.ErrInternalError) {
+			code = http.StatusInternalServerError
+		}
+		httpresponse.SentErrorResponse(ctx, code, "error getting url stats", err)
+		return
+	}
+
+	resp := GetURLStatsResponse{
+		Alias: alias,
+		Total: stats.Total,
+		Daily: make([]DailyClicks, 0, len(stats.Daily)),
+	}
+	for _, daily := range stats.Daily {
+		resp.Daily = append(resp.Daily, DailyClicks{
+			Date:   daily.Day.Format(constant.DayLayout),
+			Clicks: daily.Clicks,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
+	"github.com/romandnk/shortener/internal/entity"
+	clickservice "github.com/romandnk/shortener/internal/service/click"
 	mock_service "github.com/romandnk/shortener/internal/service/mock"
 	urlservice "github.com/romandnk/shortener/internal/service/url"
 	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
@@ -13,6 +15,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestUrlRoutes_CreateURLAlias(t *testing.T) {
@@ -20,7 +23,9 @@ func TestUrlRoutes_CreateURLAlias(t *testing.T) {
 
 	type argsUrl struct {
 		input         string
+		alias         string
 		output        string
+		existing      bool
 		expectedError error
 	}
 
@@ -41,7 +46,7 @@ func TestUrlRoutes_CreateURLAlias(t *testing.T) {
 				output: "testtest12",
 			},
 			urlM: func(m *mock_service.MockURL, args argsUrl) {
-				m.EXPECT().CreateURLAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
 			},
 			requestBody: map[string]interface{}{
 				"original_url": "https://google.com",
@@ -49,13 +54,80 @@ func TestUrlRoutes_CreateURLAlias(t *testing.T) {
 			expectedResponseBody: `{"alias":"testtest12"}`,
 			expectedHTTPCode:     http.StatusCreated,
 		},
+		{
+			name: "OK existing alias",
+			argsUrl: argsUrl{
+				input:    "https://google.com",
+				output:   "testtest12",
+				existing: true,
+			},
+			urlM: func(m *mock_service.MockURL, args argsUrl) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
+			},
+			requestBody: map[string]interface{}{
+				"original_url": "https://google.com",
+			},
+			expectedResponseBody: `{"alias":"testtest12"}`,
+			expectedHTTPCode:     http.StatusOK,
+		},
+		{
+			name: "OK custom alias",
+			argsUrl: argsUrl{
+				input:  "https://google.com",
+				alias:  "google",
+				output: "google",
+			},
+			urlM: func(m *mock_service.MockURL, args argsUrl) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
+			},
+			requestBody: map[string]interface{}{
+				"original_url": "https://google.com",
+				"alias":        "google",
+			},
+			expectedResponseBody: `{"alias":"google"}`,
+			expectedHTTPCode:     http.StatusCreated,
+		},
+		{
+			name: "reserved custom alias",
+			argsUrl: argsUrl{
+				input:         "https://google.com",
+				alias:         "swagger",
+				expectedError: urlservice.ErrReservedAlias,
+			},
+			urlM: func(m *mock_service.MockURL, args argsUrl) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
+			},
+			requestBody: map[string]interface{}{
+				"original_url": "https://google.com",
+				"alias":        "swagger",
+			},
+			expectedResponseBody: `{"message":"error creating short url","error":"unique id is reserved"}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
+		{
+			name: "custom aliases are disabled",
+			argsUrl: argsUrl{
+				input:         "https://google.com",
+				alias:         "google",
+				expectedError: urlservice.ErrCustomAliasDisabled,
+			},
+			urlM: func(m *mock_service.MockURL, args argsUrl) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
+			},
+			requestBody: map[string]interface{}{
+				"original_url": "https://google.com",
+				"alias":        "google",
+			},
+			expectedResponseBody: `{"message":"error creating short url","error":"custom unique ids are disabled"}`,
+			expectedHTTPCode:     http.StatusForbidden,
+		},
 		{
 			name: "original url is empty",
 			argsUrl: argsUrl{
 				expectedError: urlservice.ErrEmptyOriginalURL,
 			},
 			urlM: func(m *mock_service.MockURL, args argsUrl) {
-				m.EXPECT().CreateURLAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
 			},
 			requestBody: map[string]interface{}{
 				"original_url": "",
@@ -70,7 +142,7 @@ func TestUrlRoutes_CreateURLAlias(t *testing.T) {
 				expectedError: urlservice.ErrInvalidOriginalURL,
 			},
 			urlM: func(m *mock_service.MockURL, args argsUrl) {
-				m.EXPECT().CreateURLAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
 			},
 			requestBody: map[string]interface{}{
 				"original_url": "http//google.com",
@@ -78,6 +150,21 @@ func TestUrlRoutes_CreateURLAlias(t *testing.T) {
 			expectedResponseBody: `{"message":"error creating short url","error":"invalid url format"}`,
 			expectedHTTPCode:     http.StatusBadRequest,
 		},
+		{
+			name: "original url is rejected",
+			argsUrl: argsUrl{
+				input:         "http://127.0.0.1/admin",
+				expectedError: urlservice.ErrPrivateHost,
+			},
+			urlM: func(m *mock_service.MockURL, args argsUrl) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
+			},
+			requestBody: map[string]interface{}{
+				"original_url": "http://127.0.0.1/admin",
+			},
+			expectedResponseBody: `{"message":"error creating short url","error":"url host is private","reason":"PRIVATE_HOST"}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
 		{
 			name: "original url exists",
 			argsUrl: argsUrl{
@@ -85,7 +172,7 @@ func TestUrlRoutes_CreateURLAlias(t *testing.T) {
 				expectedError: storageerrors.ErrOriginalURLExists,
 			},
 			urlM: func(m *mock_service.MockURL, args argsUrl) {
-				m.EXPECT().CreateURLAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+				m.EXPECT().CreateURLAlias(gomock.Any(), entity.URL{Original: args.input, Alias: args.alias}).Return(args.output, args.existing, args.expectedError)
 			},
 			requestBody: map[string]interface{}{
 				"original_url": "http://google.com",
@@ -93,6 +180,43 @@ func TestUrlRoutes_CreateURLAlias(t *testing.T) {
 			expectedResponseBody: `{"message":"error creating short url","error":"original url already exists"}`,
 			expectedHTTPCode:     http.StatusBadRequest,
 		},
+		{
+			name: "OK with expiration time",
+			argsUrl: argsUrl{
+				input:  "https://google.com",
+				output: "testtest12",
+			},
+			urlM: func(m *mock_service.MockURL, args argsUrl) {
+				m.EXPECT().CreateURLAlias(gomock.Any(), gomock.Cond(func(x any) bool {
+					return x.(entity.URL).ExpiresAt.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+				})).Return(args.output, args.existing, args.expectedError)
+			},
+			requestBody: map[string]interface{}{
+				"original_url": "https://google.com",
+				"expires_at":   "2030-01-01T00:00:00Z",
+			},
+			expectedResponseBody: `{"alias":"testtest12","expires_at":"2030-01-01T00:00:00Z"}`,
+			expectedHTTPCode:     http.StatusCreated,
+		},
+		{
+			name: "invalid ttl",
+			requestBody: map[string]interface{}{
+				"original_url": "https://google.com",
+				"ttl":          "one day",
+			},
+			expectedResponseBody: `{"message":"error parsing expiration","error":"time: invalid duration \"one day\""}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
+		{
+			name: "both expiration time and ttl",
+			requestBody: map[string]interface{}{
+				"original_url": "https://google.com",
+				"expires_at":   "2030-01-01T00:00:00Z",
+				"ttl":          "24h",
+			},
+			expectedResponseBody: `{"message":"error parsing expiration","error":"only one of expiration time and ttl can be set"}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -133,6 +257,95 @@ func TestUrlRoutes_CreateURLAlias(t *testing.T) {
 	}
 }
 
+func TestUrlRoutes_CreateURLAliasBatch(t *testing.T) {
+	url := "/api/v1/urls/batch"
+
+	testCases := []struct {
+		name                 string
+		urlM                 func(m *mock_service.MockURL)
+		requestBody          string
+		expectedResponseBody string
+		expectedHTTPCode     int
+	}{
+		{
+			name: "OK",
+			urlM: func(m *mock_service.MockURL) {
+				m.EXPECT().CreateURLAliasBatch(gomock.Any(), []entity.URL{
+					{Original: "https://google.com"},
+					{Original: "https://go.dev", Alias: "golang"},
+				}).Return([]urlservice.CreateResult{
+					{Alias: "testtest12"},
+					{Err: storageerrors.ErrURLAliasExists},
+				}, nil)
+			},
+			requestBody: `{"urls":[{"original_url":"https://google.com"},{"original_url":"https://ya.ru","ttl":"-1h"},` +
+				`{"original_url":"https://go.dev","alias":"golang"}]}`,
+			expectedResponseBody: `{"results":[{"alias":"testtest12"},{"error":"ttl must be positive"},{"error":"url alias already exists"}]}`,
+			expectedHTTPCode:     http.StatusOK,
+		},
+		{
+			name: "OK rejected url",
+			urlM: func(m *mock_service.MockURL) {
+				m.EXPECT().CreateURLAliasBatch(gomock.Any(), []entity.URL{
+					{Original: "file:///etc/passwd"},
+				}).Return([]urlservice.CreateResult{
+					{Err: urlservice.ErrSchemeNotAllowed},
+				}, nil)
+			},
+			requestBody:          `{"urls":[{"original_url":"file:///etc/passwd"}]}`,
+			expectedResponseBody: `{"results":[{"error":"url scheme is not allowed","reason":"SCHEME_NOT_ALLOWED"}]}`,
+			expectedHTTPCode:     http.StatusOK,
+		},
+		{
+			name: "batch is too large",
+			urlM: func(m *mock_service.MockURL) {
+				m.EXPECT().CreateURLAliasBatch(gomock.Any(), gomock.Any()).Return(nil, urlservice.ErrBatchTooLarge)
+			},
+			requestBody:          `{"urls":[{"original_url":"https://google.com"}]}`,
+			expectedResponseBody: `{"message":"error creating short urls","error":"batch is too large"}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
+		{
+			name: "internal error",
+			urlM: func(m *mock_service.MockURL) {
+				m.EXPECT().CreateURLAliasBatch(gomock.Any(), gomock.Any()).Return(nil, urlservice.ErrInternalError)
+			},
+			requestBody:          `{"urls":[{"original_url":"https://google.com"}]}`,
+			expectedResponseBody: `{"message":"error creating short urls","error":"internal error"}`,
+			expectedHTTPCode:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			urlService := mock_service.NewMockURL(ctrl)
+			tc.urlM(urlService)
+
+			urlR := UrlRoutes{
+				url: urlService,
+			}
+
+			r := gin.Default()
+			r.POST(url, urlR.CreateURLAliasBatch)
+
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewBufferString(tc.requestBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedHTTPCode, w.Code)
+			require.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
 func TestUrlRoutes_GetOriginalByAlias(t *testing.T) {
 	url := "/api/v1/urls/"
 
@@ -151,6 +364,7 @@ func TestUrlRoutes_GetOriginalByAlias(t *testing.T) {
 		pathParam            string
 		expectedResponseBody string
 		expectedHTTPCode     int
+		expectedClick        bool
 	}{
 		{
 			name: "OK",
@@ -164,6 +378,7 @@ func TestUrlRoutes_GetOriginalByAlias(t *testing.T) {
 			pathParam:            "testtest12",
 			expectedResponseBody: `{"original_url":"https://google.com"}`,
 			expectedHTTPCode:     http.StatusOK,
+			expectedClick:        true,
 		},
 		{
 			name: "too short alias",
@@ -175,7 +390,7 @@ func TestUrlRoutes_GetOriginalByAlias(t *testing.T) {
 				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
 			},
 			pathParam:            "testtest",
-			expectedResponseBody: `{"message":"error getting original url by alias","error":"unique id must be 10 symbols length"}`,
+			expectedResponseBody: `{"message":"error getting original url by alias","error":"unique id has invalid length"}`,
 			expectedHTTPCode:     http.StatusBadRequest,
 		},
 		{
@@ -191,6 +406,32 @@ func TestUrlRoutes_GetOriginalByAlias(t *testing.T) {
 			expectedResponseBody: `{"message":"error getting original url by alias","error":"original url is not found"}`,
 			expectedHTTPCode:     http.StatusBadRequest,
 		},
+		{
+			name: "original url is expired",
+			argsUrl: argsAlias{
+				input:         "testtest12",
+				expectedError: urlservice.ErrOriginalURLExpired,
+			},
+			urlM: func(m *mock_service.MockURL, args argsAlias) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"message":"error getting original url by alias","error":"url alias is expired"}`,
+			expectedHTTPCode:     http.StatusGone,
+		},
+		{
+			name: "domain of original url is blocked",
+			argsUrl: argsAlias{
+				input:         "testtest12",
+				expectedError: urlservice.ErrOriginalURLBlocked,
+			},
+			urlM: func(m *mock_service.MockURL, args argsAlias) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.input).Return(args.output, args.expectedError)
+			},
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"message":"error getting original url by alias","error":"url alias is disabled, its domain is blocked"}`,
+			expectedHTTPCode:     http.StatusForbidden,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -200,13 +441,18 @@ func TestUrlRoutes_GetOriginalByAlias(t *testing.T) {
 			defer ctrl.Finish()
 
 			urlService := mock_service.NewMockURL(ctrl)
+			clickService := mock_service.NewMockClick(ctrl)
 
 			if tc.urlM != nil {
 				tc.urlM(urlService, tc.argsUrl)
 			}
+			if tc.expectedClick {
+				clickService.EXPECT().RecordClick(entity.Click{Alias: tc.pathParam}, gomock.Any())
+			}
 
 			urlR := UrlRoutes{
-				url: urlService,
+				url:   urlService,
+				click: clickService,
 			}
 
 			r := gin.Default()
@@ -228,3 +474,231 @@ func TestUrlRoutes_GetOriginalByAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestUrlRoutes_GetURLStats(t *testing.T) {
+	url := "/api/v1/urls/"
+
+	type mockClickBehaviour func(m *mock_service.MockClick)
+
+	testCases := []struct {
+		name                 string
+		clickM               mockClickBehaviour
+		pathParam            string
+		expectedResponseBody string
+		expectedHTTPCode     int
+	}{
+		{
+			name: "OK",
+			clickM: func(m *mock_service.MockClick) {
+				m.EXPECT().GetClickStats(gomock.Any(), "testtest12").Return(entity.ClickStats{
+					Total: 5,
+					Daily: []entity.DailyClicks{
+						{Day: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Clicks: 3},
+						{Day: time.Date(2030, 1, 3, 0, 0, 0, 0, time.UTC), Clicks: 2},
+					},
+				}, nil)
+			},
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"alias":"testtest12","total":5,"daily":[{"date":"2030-01-01","clicks":3},{"date":"2030-01-03","clicks":2}]}`,
+			expectedHTTPCode:     http.StatusOK,
+		},
+		{
+			name: "OK no clicks",
+			clickM: func(m *mock_service.MockClick) {
+				m.EXPECT().GetClickStats(gomock.Any(), "testtest12").Return(entity.ClickStats{}, nil)
+			},
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"alias":"testtest12","total":0,"daily":[]}`,
+			expectedHTTPCode:     http.StatusOK,
+		},
+		{
+			name: "url is not found",
+			clickM: func(m *mock_service.MockClick) {
+				m.EXPECT().GetClickStats(gomock.Any(), "testtest12").Return(entity.ClickStats{}, clickservice.ErrURLNotFound)
+			},
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"message":"error getting url stats","error":"url alias is not found"}`,
+			expectedHTTPCode:     http.StatusNotFound,
+		},
+		{
+			name: "internal error",
+			clickM: func(m *mock_service.MockClick) {
+				m.EXPECT().GetClickStats(gomock.Any(), "testtest12").Return(entity.ClickStats{}, clickservice.ErrInternalError)
+			},
+			pathParam:            "testtest12",
+			expectedResponseBody: `{"message":"error getting url stats","error":"internal error"}`,
+			expectedHTTPCode:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			clickService := mock_service.NewMockClick(ctrl)
+			tc.clickM(clickService)
+
+			urlR := UrlRoutes{
+				click: clickService,
+			}
+
+			r := gin.Default()
+			r.GET(url+":alias/stats", urlR.GetURLStats)
+
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url+tc.pathParam+"/stats", nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedHTTPCode, w.Code)
+
+			require.Equal(t, []byte(tc.expectedResponseBody), w.Body.Bytes())
+		})
+	}
+}
+
+func TestUrlRoutes_UpdateOriginal(t *testing.T) {
+	url := "/api/v1/urls/"
+
+	type mockUrlBehaviour func(m *mock_service.MockURL)
+
+	testCases := []struct {
+		name                 string
+		urlM                 mockUrlBehaviour
+		pathParam            string
+		requestBody          string
+		expectedResponseBody string
+		expectedHTTPCode     int
+	}{
+		{
+			name: "OK",
+			urlM: func(m *mock_service.MockURL) {
+				m.EXPECT().UpdateOriginal(gomock.Any(), "testtest12", "https://google.com").Return(nil)
+			},
+			pathParam:        "testtest12",
+			requestBody:      `{"original_url":"https://google.com"}`,
+			expectedHTTPCode: http.StatusNoContent,
+		},
+		{
+			name:                 "invalid json body",
+			pathParam:            "testtest12",
+			requestBody:          `{"original_url":`,
+			expectedResponseBody: `{"message":"error binding json body","error":"unexpected EOF"}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
+		{
+			name: "original url exists",
+			urlM: func(m *mock_service.MockURL) {
+				m.EXPECT().UpdateOriginal(gomock.Any(), "testtest12", "https://google.com").Return(storageerrors.ErrOriginalURLExists)
+			},
+			pathParam:            "testtest12",
+			requestBody:          `{"original_url":"https://google.com"}`,
+			expectedResponseBody: `{"message":"error updating original url","error":"original url already exists"}`,
+			expectedHTTPCode:     http.StatusBadRequest,
+		},
+		{
+			name: "alias is not found",
+			urlM: func(m *mock_service.MockURL) {
+				m.EXPECT().UpdateOriginal(gomock.Any(), "testtest12", "https://google.com").Return(urlservice.ErrOriginalURLNotFound)
+			},
+			pathParam:            "testtest12",
+			requestBody:          `{"original_url":"https://google.com"}`,
+			expectedResponseBody: `{"message":"error updating original url","error":"original url is not found"}`,
+			expectedHTTPCode:     http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			urlService := mock_service.NewMockURL(ctrl)
+			if tc.urlM != nil {
+				tc.urlM(urlService)
+			}
+
+			urlR := UrlRoutes{
+				url: urlService,
+			}
+
+			r := gin.Default()
+			r.PUT(url+":alias", urlR.UpdateOriginal)
+
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, url+tc.pathParam,
+				bytes.NewBufferString(tc.requestBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedHTTPCode, w.Code)
+
+			require.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestUrlRoutes_DeleteByAlias(t *testing.T) {
+	url := "/api/v1/urls/"
+
+	testCases := []struct {
+		name                 string
+		serviceError         error
+		expectedResponseBody string
+		expectedHTTPCode     int
+	}{
+		{
+			name:             "OK",
+			expectedHTTPCode: http.StatusNoContent,
+		},
+		{
+			name:                 "alias is not found",
+			serviceError:         urlservice.ErrOriginalURLNotFound,
+			expectedResponseBody: `{"message":"error deleting url alias","error":"original url is not found"}`,
+			expectedHTTPCode:     http.StatusNotFound,
+		},
+		{
+			name:                 "internal error",
+			serviceError:         urlservice.ErrInternalError,
+			expectedResponseBody: `{"message":"error deleting url alias","error":"internal error"}`,
+			expectedHTTPCode:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			urlService := mock_service.NewMockURL(ctrl)
+			urlService.EXPECT().DeleteByAlias(gomock.Any(), "testtest12").Return(tc.serviceError)
+
+			urlR := UrlRoutes{
+				url: urlService,
+			}
+
+			r := gin.Default()
+			r.DELETE(url+":alias", urlR.DeleteByAlias)
+
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodDelete, url+"testtest12", nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedHTTPCode, w.Code)
+
+			require.Equal(t, tc.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package authservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	"github.com/romandnk/shortener/internal/storage"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/logger"
+	"go.uber.org/zap"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	keyPrefix = "sk_"
+	keyBytes  = 32
+)
+
+type AuthService struct {
+	cfg    Config
+	apiKey storage.APIKey
+	logger logger.Logger
+}
+
+func NewAuthService(cfg Config, apiKey storage.APIKey, logger logger.Logger) *AuthService {
+	return &AuthService{
+		cfg:    cfg,
+		apiKey: apiKey,
+		logger: logger,
+	}
+}
+
+// Enabled reports whether requests changing urls require an api key.
+func (s *AuthService) Enabled() bool {
+	return s.cfg.Enabled
+}
+
+// CreateKey generates a new api key, only its hash is saved,
+// so the returned key cannot be received again.
+func (s *AuthService) CreateKey(ctx context.Context, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		s.logger.Error("AuthService.CreateKey", zap.String("error", ErrEmptyKeyName.Error()))
+		return "", ErrEmptyKeyName
+	}
+
+	random := make([]byte, keyBytes)
+	if _, err := rand.Read(random); err != nil {
+		s.logger.Error("AuthService.CreateKey - rand.Read", zap.String("error", err.Error()))
+		return "", ErrInternalError
+	}
+	key := keyPrefix + hex.EncodeToString(random)
+
+	err := s.apiKey.CreateAPIKey(ctx, entity.APIKey{
+		Name:      name,
+		Hash:      HashKey(key),
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrAPIKeyExists) {
+			s.logger.Error("AuthService.CreateKey", zap.String("name", name), zap.String("error", err.Error()))
+			return "", ErrKeyExists
+		}
+		s.logger.Error("AuthService.CreateKey - s.apiKey.CreateAPIKey", zap.String("error", err.Error()))
+		return "", ErrInternalError
+	}
+
+	s.logger.Info("AuthService.CreateKey - api key was created successfully", zap.String("name", name))
+
+	return key, nil
+}
+
+func (s *AuthService) ListKeys(ctx context.Context) ([]entity.APIKey, error) {
+	keys, err := s.apiKey.GetAPIKeys(ctx)
+	if err != nil {
+		s.logger.Error("AuthService.ListKeys - s.apiKey.GetAPIKeys", zap.String("error", err.Error()))
+		return nil, ErrInternalError
+	}
+	return keys, nil
+}
+
+func (s *AuthService) RevokeKey(ctx context.Context, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		s.logger.Error("AuthService.RevokeKey", zap.String("error", ErrEmptyKeyName.Error()))
+		return ErrEmptyKeyName
+	}
+
+	err := s.apiKey.DeleteAPIKey(ctx, name)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrAPIKeyNotFound) {
+			s.logger.Error("AuthService.RevokeKey", zap.String("name", name), zap.String("error", err.Error()))
+			return ErrKeyNotFound
+		}
+		s.logger.Error("AuthService.RevokeKey - s.apiKey.DeleteAPIKey", zap.String("error", err.Error()))
+		return ErrInternalError
+	}
+
+	s.logger.Info("AuthService.RevokeKey - api key was revoked successfully", zap.String("name", name))
+
+	return nil
+}
+
+// Authenticate returns the api key the raw key belongs to.
+func (s *AuthService) Authenticate(ctx context.Context, key string) (entity.APIKey, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return entity.APIKey{}, ErrMissingAPIKey
+	}
+
+	apiKey, err := s.apiKey.GetAPIKeyByHash(ctx, HashKey(key))
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrAPIKeyNotFound) {
+			s.logger.Error("AuthService.Authenticate", zap.String("error", ErrInvalidAPIKey.Error()))
+			return entity.APIKey{}, ErrInvalidAPIKey
+		}
+		s.logger.Error("AuthService.Authenticate - s.apiKey.GetAPIKeyByHash", zap.String("error", err.Error()))
+		return entity.APIKey{}, ErrInternalError
+	}
+
+	return apiKey, nil
+}
+
+// IsAdmin reports whether the api key can manage domain rules,
+// nobody can unless auth is enabled and the key is listed in admins.
+func (s *AuthService) IsAdmin(key entity.APIKey) bool {
+	return s.cfg.Enabled && slices.Contains(s.cfg.Admins, key.Name)
+}
+
+// HashKey returns the hex encoded sha256 of the key as it is kept in storage.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
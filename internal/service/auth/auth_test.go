@@ -0,0 +1,191 @@
+package authservice
+
+import (
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	mock_storage "github.com/romandnk/shortener/internal/storage/mock"
+	mock_logger "github.com/romandnk/shortener/pkg/logger/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+)
+
+func TestAuthService_CreateKey(t *testing.T) {
+	type mockBehaviour func(apiKey *mock_storage.MockAPIKey, log *mock_logger.MockLogger)
+
+	testCases := []struct {
+		name          string
+		inputName     string
+		mock          mockBehaviour
+		expectedError error
+	}{
+		{
+			name:      "OK",
+			inputName: " ci ",
+			mock: func(apiKey *mock_storage.MockAPIKey, log *mock_logger.MockLogger) {
+				apiKey.EXPECT().CreateAPIKey(gomock.Any(), gomock.Cond(func(x any) bool {
+					key := x.(entity.APIKey)
+					return key.Name == "ci" && len(key.Hash) == 64 && !key.CreatedAt.IsZero()
+				})).Return(nil)
+				log.EXPECT().Info("AuthService.CreateKey - api key was created successfully",
+					[]any{zap.String("name", "ci")})
+			},
+		},
+		{
+			name: "empty name",
+			mock: func(apiKey *mock_storage.MockAPIKey, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("AuthService.CreateKey", []any{zap.String("error", ErrEmptyKeyName.Error())})
+			},
+			expectedError: ErrEmptyKeyName,
+		},
+		{
+			name:      "key exists",
+			inputName: "ci",
+			mock: func(apiKey *mock_storage.MockAPIKey, log *mock_logger.MockLogger) {
+				apiKey.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Return(storageerrors.ErrAPIKeyExists)
+				log.EXPECT().Error("AuthService.CreateKey", []any{
+					zap.String("name", "ci"),
+					zap.String("error", storageerrors.ErrAPIKeyExists.Error()),
+				})
+			},
+			expectedError: ErrKeyExists,
+		},
+		{
+			name:      "storage error",
+			inputName: "ci",
+			mock: func(apiKey *mock_storage.MockAPIKey, log *mock_logger.MockLogger) {
+				apiKey.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+				log.EXPECT().Error("AuthService.CreateKey - s.apiKey.CreateAPIKey",
+					[]any{zap.String("error", "connection refused")})
+			},
+			expectedError: ErrInternalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			apiKeyStorage := mock_storage.NewMockAPIKey(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			tc.mock(apiKeyStorage, log)
+
+			authService := NewAuthService(Config{Enabled: true}, apiKeyStorage, log)
+
+			key, err := authService.CreateKey(context.Background(), tc.inputName)
+			require.ErrorIs(t, err, tc.expectedError)
+			if tc.expectedError == nil {
+				require.True(t, strings.HasPrefix(key, keyPrefix))
+				require.Len(t, key, len(keyPrefix)+keyBytes*2)
+			}
+		})
+	}
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	const key = "sk_test"
+	apiKey := entity.APIKey{Name: "ci", Hash: HashKey(key)}
+
+	type mockBehaviour func(apiKey *mock_storage.MockAPIKey, log *mock_logger.MockLogger)
+
+	testCases := []struct {
+		name           string
+		inputKey       string
+		mock           mockBehaviour
+		expectedAPIKey entity.APIKey
+		expectedError  error
+	}{
+		{
+			name:     "OK",
+			inputKey: key,
+			mock: func(storage *mock_storage.MockAPIKey, log *mock_logger.MockLogger) {
+				storage.EXPECT().GetAPIKeyByHash(gomock.Any(), HashKey(key)).Return(apiKey, nil)
+			},
+			expectedAPIKey: apiKey,
+		},
+		{
+			name:          "missing key",
+			inputKey:      " ",
+			mock:          func(storage *mock_storage.MockAPIKey, log *mock_logger.MockLogger) {},
+			expectedError: ErrMissingAPIKey,
+		},
+		{
+			name:     "invalid key",
+			inputKey: "sk_invalid",
+			mock: func(storage *mock_storage.MockAPIKey, log *mock_logger.MockLogger) {
+				storage.EXPECT().GetAPIKeyByHash(gomock.Any(), HashKey("sk_invalid")).
+					Return(entity.APIKey{}, storageerrors.ErrAPIKeyNotFound)
+				log.EXPECT().Error("AuthService.Authenticate", []any{zap.String("error", ErrInvalidAPIKey.Error())})
+			},
+			expectedError: ErrInvalidAPIKey,
+		},
+		{
+			name:     "storage error",
+			inputKey: key,
+			mock: func(storage *mock_storage.MockAPIKey, log *mock_logger.MockLogger) {
+				storage.EXPECT().GetAPIKeyByHash(gomock.Any(), HashKey(key)).
+					Return(entity.APIKey{}, errors.New("connection refused"))
+				log.EXPECT().Error("AuthService.Authenticate - s.apiKey.GetAPIKeyByHash",
+					[]any{zap.String("error", "connection refused")})
+			},
+			expectedError: ErrInternalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			apiKeyStorage := mock_storage.NewMockAPIKey(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			tc.mock(apiKeyStorage, log)
+
+			authService := NewAuthService(Config{Enabled: true}, apiKeyStorage, log)
+
+			actual, err := authService.Authenticate(context.Background(), tc.inputKey)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedAPIKey, actual)
+		})
+	}
+}
+
+func TestAuthService_RevokeKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	apiKeyStorage := mock_storage.NewMockAPIKey(ctrl)
+	log := mock_logger.NewMockLogger(ctrl)
+
+	authService := NewAuthService(Config{Enabled: true}, apiKeyStorage, log)
+
+	apiKeyStorage.EXPECT().DeleteAPIKey(gomock.Any(), "ci").Return(storageerrors.ErrAPIKeyNotFound)
+	log.EXPECT().Error("AuthService.RevokeKey", []any{
+		zap.String("name", "ci"),
+		zap.String("error", storageerrors.ErrAPIKeyNotFound.Error()),
+	})
+
+	err := authService.RevokeKey(context.Background(), "ci")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestAuthService_IsAdmin(t *testing.T) {
+	noAdmins := NewAuthService(Config{Enabled: true}, nil, nil)
+	require.False(t, noAdmins.IsAdmin(entity.APIKey{Name: "ci"}))
+
+	admins := NewAuthService(Config{Enabled: true, Admins: []string{"ops"}}, nil, nil)
+	require.True(t, admins.IsAdmin(entity.APIKey{Name: "ops"}))
+	require.False(t, admins.IsAdmin(entity.APIKey{Name: "ci"}))
+
+	disabled := NewAuthService(Config{Admins: []string{"ops"}}, nil, nil)
+	require.False(t, disabled.IsAdmin(entity.APIKey{Name: "ops"}))
+}
@@ -0,0 +1,9 @@
+package authservice
+
+type Config struct {
+	// Enabled requires a valid api key for requests changing urls,
+	// resolving aliases is always allowed.
+	Enabled bool `yaml:"enabled" env:"AUTH_ENABLED" env-default:"true"`
+	// Admins are names of api keys allowed to manage domain rules, nobody is allowed if empty.
+	Admins []string `yaml:"admins" env:"AUTH_ADMINS"`
+}
@@ -0,0 +1,19 @@
+package authservice
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+)
+
+type apiKeyCtxKey struct{}
+
+// WithAPIKey returns a copy of ctx carrying the authenticated api key.
+func WithAPIKey(ctx context.Context, key entity.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyCtxKey{}, key)
+}
+
+// APIKeyFromContext returns the api key the request was authenticated with.
+func APIKeyFromContext(ctx context.Context) (entity.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyCtxKey{}).(entity.APIKey)
+	return key, ok
+}
@@ -0,0 +1,17 @@
+package authservice
+
+import "errors"
+
+var (
+	ErrInternalError = errors.New("internal error")
+)
+
+var (
+	ErrMissingAPIKey = errors.New("api key is required")
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	ErrEmptyKeyName  = errors.New("empty api key name")
+	ErrKeyExists     = errors.New("api key with this name already exists")
+	ErrKeyNotFound   = errors.New("api key is not found")
+	ErrNotAdmin      = errors.New("api key is not allowed to manage domain rules")
+	ErrAuthDisabled  = errors.New("domain rules cannot be managed with auth disabled")
+)
@@ -0,0 +1,151 @@
+package clickservice
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	"github.com/romandnk/shortener/internal/storage"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/logger"
+	"go.uber.org/zap"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type ClickService struct {
+	cfg    Config
+	click  storage.Click
+	url    storage.URL
+	logger logger.Logger
+
+	queue   chan entity.Click
+	dropped atomic.Uint64
+}
+
+func NewClickService(cfg Config, click storage.Click, url storage.URL, logger logger.Logger) *ClickService {
+	return &ClickService{
+		cfg:    cfg,
+		click:  click,
+		url:    url,
+		logger: logger,
+		queue:  make(chan entity.Click, max(cfg.BufferSize, 1)),
+	}
+}
+
+// RecordClick queues the click for writing without waiting for the storage,
+// so resolving aliases does not slow down. The click is dropped if the buffer is full.
+func (s *ClickService) RecordClick(click entity.Click, ip string) {
+	if click.ClickedAt.IsZero() {
+		click.ClickedAt = time.Now()
+	}
+	if ip != "" {
+		click.IPHash = s.hashIP(ip)
+	}
+
+	select {
+	case s.queue <- click:
+	default:
+		dropped := s.dropped.Add(1)
+		s.logger.Error("ClickService.RecordClick - buffer is full, click was dropped",
+			zap.String("alias", click.Alias), zap.Uint64("dropped", dropped))
+	}
+}
+
+func (s *ClickService) hashIP(ip string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.IPSecret))
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run writes queued clicks in batches until ctx is done,
+// then writes the clicks left in the buffer.
+func (s *ClickService) Run(ctx context.Context) {
+	batchSize := max(s.cfg.BatchSize, 1)
+	batch := make([]entity.Click, 0, batchSize)
+
+	interval := s.cfg.FlushInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case click := <-s.queue:
+			batch = append(batch, click)
+			if len(batch) >= batchSize {
+				batch = s.flush(batch)
+			}
+		case <-ticker.C:
+			batch = s.flush(batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case click := <-s.queue:
+					batch = append(batch, click)
+					if len(batch) >= batchSize {
+						batch = s.flush(batch)
+					}
+				default:
+					s.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes the batch and returns it emptied for reuse.
+func (s *ClickService) flush(batch []entity.Click) []entity.Click {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx := context.Background()
+	if s.cfg.WriteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.WriteTimeout)
+		defer cancel()
+	}
+
+	if err := s.click.CreateClicks(ctx, batch); err != nil {
+		s.logger.Error("ClickService.flush - s.click.CreateClicks",
+			zap.Int("clicks", len(batch)), zap.String("error", err.Error()))
+	}
+
+	return batch[:0]
+}
+
+// GetClickStats returns clicks of the alias, stats of expired aliases are still available.
+func (s *ClickService) GetClickStats(ctx context.Context, alias string) (entity.ClickStats, error) {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		s.logger.Error("ClickService.GetClickStats", zap.String("error", ErrEmptyURLAlias.Error()))
+		return entity.ClickStats{}, ErrEmptyURLAlias
+	}
+
+	_, err := s.url.GetOriginalByAlias(ctx, alias)
+	if err != nil && !errors.Is(err, storageerrors.ErrURLExpired) {
+		if errors.Is(err, storageerrors.ErrURLAliasNotFound) {
+			s.logger.Error("ClickService.GetClickStats", zap.String("alias", alias), zap.String("error", err.Error()))
+			return entity.ClickStats{}, ErrURLNotFound
+		}
+		s.logger.Error("ClickService.GetClickStats - s.url.GetOriginalByAlias", zap.String("error", err.Error()))
+		return entity.ClickStats{}, ErrInternalError
+	}
+
+	stats, err := s.click.GetClickStats(ctx, alias)
+	if err != nil {
+		s.logger.Error("ClickService.GetClickStats - s.click.GetClickStats", zap.String("error", err.Error()))
+		return entity.ClickStats{}, ErrInternalError
+	}
+
+	s.logger.Info("ClickService.GetClickStats - stats were received successfully", zap.String("alias", alias))
+
+	return stats, nil
+}
@@ -0,0 +1,170 @@
+package clickservice
+
+import (
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	mock_storage "github.com/romandnk/shortener/internal/storage/mock"
+	mock_logger "github.com/romandnk/shortener/pkg/logger/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestClickService_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clickStorage := mock_storage.NewMockClick(ctrl)
+	log := mock_logger.NewMockLogger(ctrl)
+
+	clickService := NewClickService(Config{
+		BufferSize:    10,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		IPSecret:      "secret",
+	}, clickStorage, nil, log)
+
+	clickedAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var written []entity.Click
+	clickStorage.EXPECT().CreateClicks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, clicks []entity.Click) error {
+			written = append(written, clicks...)
+			return nil
+		}).Times(2)
+
+	for _, alias := range []string{"testtest11", "testtest12", "testtest13"} {
+		clickService.RecordClick(entity.Click{Alias: alias, ClickedAt: clickedAt}, "127.0.0.1")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the buffer is drained after ctx is done
+	clickService.Run(ctx)
+
+	require.Len(t, written, 3)
+	for _, click := range written {
+		require.Equal(t, clickService.hashIP("127.0.0.1"), click.IPHash)
+		require.Equal(t, clickedAt, click.ClickedAt)
+	}
+	require.NotEqual(t, "127.0.0.1", written[0].IPHash)
+}
+
+func TestClickService_RecordClickBufferIsFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := mock_logger.NewMockLogger(ctrl)
+
+	clickService := NewClickService(Config{BufferSize: 1}, mock_storage.NewMockClick(ctrl), nil, log)
+
+	log.EXPECT().Error("ClickService.RecordClick - buffer is full, click was dropped",
+		[]any{zap.String("alias", "testtest12"), zap.Uint64("dropped", 1)})
+
+	clickService.RecordClick(entity.Click{Alias: "testtest11"}, "")
+	clickService.RecordClick(entity.Click{Alias: "testtest12"}, "")
+
+	require.Len(t, clickService.queue, 1)
+}
+
+func TestClickService_GetClickStats(t *testing.T) {
+	stats := entity.ClickStats{
+		Total: 2,
+		Daily: []entity.DailyClicks{
+			{Day: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Clicks: 2},
+		},
+	}
+
+	type mockBehaviour func(url *mock_storage.MockURL, click *mock_storage.MockClick, log *mock_logger.MockLogger)
+
+	testCases := []struct {
+		name          string
+		inputAlias    string
+		mock          mockBehaviour
+		expectedStats entity.ClickStats
+		expectedError error
+	}{
+		{
+			name:       "OK",
+			inputAlias: "testtest11",
+			mock: func(url *mock_storage.MockURL, click *mock_storage.MockClick, log *mock_logger.MockLogger) {
+				url.EXPECT().GetOriginalByAlias(gomock.Any(), "testtest11").Return("http://google.com", nil)
+				click.EXPECT().GetClickStats(gomock.Any(), "testtest11").Return(stats, nil)
+				log.EXPECT().Info("ClickService.GetClickStats - stats were received successfully",
+					[]any{zap.String("alias", "testtest11")})
+			},
+			expectedStats: stats,
+		},
+		{
+			name:       "OK expired url",
+			inputAlias: "testtest11",
+			mock: func(url *mock_storage.MockURL, click *mock_storage.MockClick, log *mock_logger.MockLogger) {
+				url.EXPECT().GetOriginalByAlias(gomock.Any(), "testtest11").Return("", storageerrors.ErrURLExpired)
+				click.EXPECT().GetClickStats(gomock.Any(), "testtest11").Return(stats, nil)
+				log.EXPECT().Info("ClickService.GetClickStats - stats were received successfully",
+					[]any{zap.String("alias", "testtest11")})
+			},
+			expectedStats: stats,
+		},
+		{
+			name: "empty alias",
+			mock: func(url *mock_storage.MockURL, click *mock_storage.MockClick, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("ClickService.GetClickStats", []any{zap.String("error", ErrEmptyURLAlias.Error())})
+			},
+			expectedError: ErrEmptyURLAlias,
+		},
+		{
+			name:       "url is not found",
+			inputAlias: "testtest11",
+			mock: func(url *mock_storage.MockURL, click *mock_storage.MockClick, log *mock_logger.MockLogger) {
+				url.EXPECT().GetOriginalByAlias(gomock.Any(), "testtest11").Return("", storageerrors.ErrURLAliasNotFound)
+				log.EXPECT().Error("ClickService.GetClickStats", []any{
+					zap.String("alias", "testtest11"),
+					zap.String("error", storageerrors.ErrURLAliasNotFound.Error()),
+				})
+			},
+			expectedError: ErrURLNotFound,
+		},
+		{
+			name:       "storage error",
+			inputAlias: "testtest11",
+			mock: func(url *mock_storage.MockURL, click *mock_storage.MockClick, log *mock_logger.MockLogger) {
+				url.EXPECT().GetOriginalByAlias(gomock.Any(), "testtest11").Return("http://google.com", nil)
+				click.EXPECT().GetClickStats(gomock.Any(), "testtest11").Return(entity.ClickStats{}, errors.New("connection refused"))
+				log.EXPECT().Error("ClickService.GetClickStats - s.click.GetClickStats",
+					[]any{zap.String("error", "connection refused")})
+			},
+			expectedError: ErrInternalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			urlStorage := mock_storage.NewMockURL(ctrl)
+			clickStorage := mock_storage.NewMockClick(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			tc.mock(urlStorage, clickStorage, log)
+
+			clickService := NewClickService(Config{}, clickStorage, urlStorage, log)
+
+			actual, err := clickService.GetClickStats(context.Background(), tc.inputAlias)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedStats, actual)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.ErrorIs(t, Config{}.Validate(), ErrEmptyIPSecret)
+	require.NoError(t, Config{IPSecret: "secret"}.Validate())
+}
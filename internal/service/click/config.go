@@ -0,0 +1,24 @@
+package clickservice
+
+import "time"
+
+type Config struct {
+	// BufferSize is how many clicks wait for writing,
+	// clicks recorded while the buffer is full are dropped.
+	BufferSize int `yaml:"buffer_size" env-default:"10000"`
+	// BatchSize is the max number of clicks written at once.
+	BatchSize int `yaml:"batch_size" env-default:"100"`
+	// FlushInterval is the max time a click waits in the buffer.
+	FlushInterval time.Duration `yaml:"flush_interval" env-default:"1s"`
+	WriteTimeout  time.Duration `yaml:"write_timeout" env-default:"5s"`
+	// IPSecret keys HMAC-SHA256 of client IPs, without it hashes of the whole IPv4 space
+	// are computed in minutes. It is required.
+	IPSecret string `yaml:"ip_secret" env:"CLICK_SERVICE_IP_SECRET"`
+}
+
+func (c Config) Validate() error {
+	if c.IPSecret == "" {
+		return ErrEmptyIPSecret
+	}
+	return nil
+}
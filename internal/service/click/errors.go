@@ -0,0 +1,13 @@
+package clickservice
+
+import "errors"
+
+var (
+	ErrInternalError = errors.New("internal error")
+)
+
+var (
+	ErrEmptyURLAlias = errors.New("empty url unique id")
+	ErrURLNotFound   = errors.New("url alias is not found")
+	ErrEmptyIPSecret = errors.New("click_service.ip_secret (CLICK_SERVICE_IP_SECRET) is required")
+)
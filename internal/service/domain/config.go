@@ -0,0 +1,12 @@
+package domainservice
+
+import "time"
+
+type Config struct {
+	// AllowlistOnly rejects domains not matching any allow rule,
+	// otherwise only domains matching block rules are rejected.
+	AllowlistOnly bool `yaml:"allowlist_only" env:"DOMAIN_ALLOWLIST_ONLY"`
+	// RefreshInterval is how often rules changed by other instances are reloaded,
+	// zero reloads them only after changes made through this instance.
+	RefreshInterval time.Duration `yaml:"refresh_interval" env-default:"30s"`
+}
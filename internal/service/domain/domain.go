@@ -0,0 +1,236 @@
+package domainservice
+
+import (
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	"github.com/romandnk/shortener/internal/storage"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/net/idna"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const wildcardPrefix = "*."
+
+// DomainService manages domain rules and checks hosts against a snapshot of them,
+// so checks made for every link do not query the storage.
+type DomainService struct {
+	cfg    Config
+	domain storage.Domain
+	logger logger.Logger
+
+	rules atomic.Pointer[ruleSet]
+}
+
+func NewDomainService(cfg Config, domain storage.Domain, logger logger.Logger) *DomainService {
+	return &DomainService{
+		cfg:    cfg,
+		domain: domain,
+		logger: logger,
+	}
+}
+
+// AddRule blocks or allows links to hosts matching the pattern, an existing pattern is moved to the list.
+func (s *DomainService) AddRule(ctx context.Context, pattern string, list entity.DomainList) (entity.DomainRule, error) {
+	pattern, err := normalizePattern(pattern)
+	if err != nil {
+		s.logger.Error("DomainService.AddRule", zap.String("error", err.Error()))
+		return entity.DomainRule{}, err
+	}
+	if list != entity.DomainBlock && list != entity.DomainAllow {
+		s.logger.Error("DomainService.AddRule", zap.String("list", string(list)), zap.String("error", ErrInvalidList.Error()))
+		return entity.DomainRule{}, ErrInvalidList
+	}
+
+	rule := entity.DomainRule{Pattern: pattern, List: list}
+	if err = s.domain.SaveDomainRule(ctx, rule); err != nil {
+		s.logger.Error("DomainService.AddRule - s.domain.SaveDomainRule", zap.String("error", err.Error()))
+		return entity.DomainRule{}, ErrInternalError
+	}
+
+	s.logger.Info("DomainService.AddRule - domain rule was saved successfully",
+		zap.String("pattern", pattern),
+		zap.String("list", string(list)),
+	)
+	s.reload(ctx, "DomainService.AddRule")
+
+	return rule, nil
+}
+
+func (s *DomainService) ListRules(ctx context.Context) ([]entity.DomainRule, error) {
+	rules, err := s.domain.GetDomainRules(ctx)
+	if err != nil {
+		s.logger.Error("DomainService.ListRules - s.domain.GetDomainRules", zap.String("error", err.Error()))
+		return nil, ErrInternalError
+	}
+	return rules, nil
+}
+
+func (s *DomainService) RemoveRule(ctx context.Context, pattern string) error {
+	pattern, err := normalizePattern(pattern)
+	if err != nil {
+		s.logger.Error("DomainService.RemoveRule", zap.String("error", err.Error()))
+		return err
+	}
+
+	err = s.domain.DeleteDomainRule(ctx, pattern)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrDomainRuleNotFound) {
+			s.logger.Error("DomainService.RemoveRule", zap.String("pattern", pattern), zap.String("error", err.Error()))
+			return ErrRuleNotFound
+		}
+		s.logger.Error("DomainService.RemoveRule - s.domain.DeleteDomainRule", zap.String("error", err.Error()))
+		return ErrInternalError
+	}
+
+	s.logger.Info("DomainService.RemoveRule - domain rule was removed successfully", zap.String("pattern", pattern))
+	s.reload(ctx, "DomainService.RemoveRule")
+
+	return nil
+}
+
+// CheckHost returns ErrDomainBlocked if the most specific rule matching the host blocks it
+// and ErrDomainNotAllowed if no rule matches in allowlist only mode.
+// Rules are loaded on the first check.
+func (s *DomainService) CheckHost(ctx context.Context, host string) error {
+	rules := s.rules.Load()
+	if rules == nil {
+		if err := s.Refresh(ctx); err != nil {
+			s.logger.Error("DomainService.CheckHost - s.Refresh", zap.String("error", err.Error()))
+			return ErrInternalError
+		}
+		rules = s.rules.Load()
+	}
+
+	list, ok := rules.match(normalizeHost(host))
+	switch {
+	case ok && list == entity.DomainBlock:
+		return ErrDomainBlocked
+	case !ok && s.cfg.AllowlistOnly:
+		return ErrDomainNotAllowed
+	default:
+		return nil
+	}
+}
+
+// Refresh replaces the snapshot of rules with rules from the storage.
+func (s *DomainService) Refresh(ctx context.Context) error {
+	rules, err := s.domain.GetDomainRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.rules.Store(newRuleSet(rules))
+
+	return nil
+}
+
+// Run reloads rules every refresh interval until ctx is done.
+func (s *DomainService) Run(ctx context.Context) {
+	if s.cfg.RefreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reload(ctx, "DomainService.Run")
+		}
+	}
+}
+
+// reload keeps the previous snapshot if rules cannot be loaded.
+func (s *DomainService) reload(ctx context.Context, method string) {
+	if err := s.Refresh(ctx); err != nil {
+		s.logger.Error(method+" - s.Refresh", zap.String("error", err.Error()))
+	}
+}
+
+// ruleSet matches hosts against exact patterns and wildcard suffixes.
+type ruleSet struct {
+	exact map[string]entity.DomainList
+	// wildcard maps suffixes of wildcard patterns without the leading "*."
+	wildcard map[string]entity.DomainList
+}
+
+func newRuleSet(rules []entity.DomainRule) *ruleSet {
+	set := ruleSet{
+		exact:    make(map[string]entity.DomainList),
+		wildcard: make(map[string]entity.DomainList),
+	}
+	for _, rule := range rules {
+		if suffix, ok := strings.CutPrefix(rule.Pattern, wildcardPrefix); ok {
+			set.wildcard[suffix] = rule.List
+			continue
+		}
+		set.exact[rule.Pattern] = rule.List
+	}
+
+	return &set
+}
+
+// match returns the list of the most specific rule, an exact pattern wins over wildcards
+// and a longer wildcard wins over shorter ones.
+func (r *ruleSet) match(host string) (entity.DomainList, bool) {
+	if list, ok := r.exact[host]; ok {
+		return list, true
+	}
+
+	// a wildcard matches the domain itself too, so blocking *.evil.com leaves no way through evil.com
+	parent := host
+	for {
+		if list, ok := r.wildcard[parent]; ok {
+			return list, true
+		}
+		i := strings.IndexByte(parent, '.')
+		if i < 0 {
+			return "", false
+		}
+		parent = parent[i+1:]
+	}
+}
+
+// normalizeHost brings hosts to the form of patterns.
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return addr.WithZone("").String()
+	}
+	return host
+}
+
+// normalizePattern lowercases the pattern and converts international names to punycode,
+// as hosts of original urls are normalized.
+func normalizePattern(pattern string) (string, error) {
+	pattern = normalizeHost(strings.TrimSpace(pattern))
+
+	host, wildcard := strings.CutPrefix(pattern, wildcardPrefix)
+	if _, err := netip.ParseAddr(host); err == nil {
+		if wildcard {
+			return "", ErrInvalidPattern
+		}
+		return host, nil
+	}
+	if host == "" || strings.ContainsAny(host, "*/:@?#[] ") {
+		return "", ErrInvalidPattern
+	}
+
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", ErrInvalidPattern
+	}
+	if wildcard {
+		return wildcardPrefix + ascii, nil
+	}
+	return ascii, nil
+}
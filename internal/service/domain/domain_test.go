@@ -0,0 +1,229 @@
+package domainservice
+
+import (
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	memorystorage "github.com/romandnk/shortener/internal/storage/memory"
+	mock_storage "github.com/romandnk/shortener/internal/storage/mock"
+	mock_logger "github.com/romandnk/shortener/pkg/logger/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestDomainService_AddRule(t *testing.T) {
+	type mockBehaviour func(domain *mock_storage.MockDomain, log *mock_logger.MockLogger)
+
+	testCases := []struct {
+		name          string
+		inputPattern  string
+		inputList     entity.DomainList
+		mock          mockBehaviour
+		expectedRule  entity.DomainRule
+		expectedError error
+	}{
+		{
+			name:         "OK",
+			inputPattern: " *.Пример.РФ. ",
+			inputList:    entity.DomainBlock,
+			mock: func(domain *mock_storage.MockDomain, log *mock_logger.MockLogger) {
+				rule := entity.DomainRule{Pattern: "*.xn--e1afmkfd.xn--p1ai", List: entity.DomainBlock}
+				domain.EXPECT().SaveDomainRule(gomock.Any(), rule).Return(nil)
+				log.EXPECT().Info("DomainService.AddRule - domain rule was saved successfully", []any{
+					zap.String("pattern", rule.Pattern),
+					zap.String("list", "block"),
+				})
+				domain.EXPECT().GetDomainRules(gomock.Any()).Return([]entity.DomainRule{rule}, nil)
+			},
+			expectedRule: entity.DomainRule{Pattern: "*.xn--e1afmkfd.xn--p1ai", List: entity.DomainBlock},
+		},
+		{
+			name:         "OK ip address",
+			inputPattern: "[2001:DB8:0::1]",
+			inputList:    entity.DomainAllow,
+			mock: func(domain *mock_storage.MockDomain, log *mock_logger.MockLogger) {
+				domain.EXPECT().SaveDomainRule(gomock.Any(), entity.DomainRule{Pattern: "2001:db8::1", List: entity.DomainAllow}).Return(nil)
+				log.EXPECT().Info(gomock.Any(), gomock.Any())
+				domain.EXPECT().GetDomainRules(gomock.Any()).Return(nil, errors.New("connection refused"))
+				log.EXPECT().Error("DomainService.AddRule - s.Refresh", []any{zap.String("error", "connection refused")})
+			},
+			expectedRule: entity.DomainRule{Pattern: "2001:db8::1", List: entity.DomainAllow},
+		},
+		{
+			name:         "wildcard in the middle",
+			inputPattern: "evil.*.com",
+			inputList:    entity.DomainBlock,
+			mock: func(domain *mock_storage.MockDomain, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("DomainService.AddRule", []any{zap.String("error", ErrInvalidPattern.Error())})
+			},
+			expectedError: ErrInvalidPattern,
+		},
+		{
+			name:         "url instead of host",
+			inputPattern: "https://evil.com/",
+			inputList:    entity.DomainBlock,
+			mock: func(domain *mock_storage.MockDomain, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("DomainService.AddRule", []any{zap.String("error", ErrInvalidPattern.Error())})
+			},
+			expectedError: ErrInvalidPattern,
+		},
+		{
+			name:         "wildcard ip address",
+			inputPattern: "*.10.0.0.1",
+			inputList:    entity.DomainBlock,
+			mock: func(domain *mock_storage.MockDomain, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("DomainService.AddRule", []any{zap.String("error", ErrInvalidPattern.Error())})
+			},
+			expectedError: ErrInvalidPattern,
+		},
+		{
+			name:         "invalid list",
+			inputPattern: "evil.com",
+			inputList:    "deny",
+			mock: func(domain *mock_storage.MockDomain, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("DomainService.AddRule", []any{
+					zap.String("list", "deny"),
+					zap.String("error", ErrInvalidList.Error()),
+				})
+			},
+			expectedError: ErrInvalidList,
+		},
+		{
+			name:         "storage error",
+			inputPattern: "evil.com",
+			inputList:    entity.DomainBlock,
+			mock: func(domain *mock_storage.MockDomain, log *mock_logger.MockLogger) {
+				domain.EXPECT().SaveDomainRule(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+				log.EXPECT().Error("DomainService.AddRule - s.domain.SaveDomainRule", []any{zap.String("error", "connection refused")})
+			},
+			expectedError: ErrInternalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			domain := mock_storage.NewMockDomain(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+			tc.mock(domain, log)
+
+			rule, err := NewDomainService(Config{}, domain, log).AddRule(context.Background(), tc.inputPattern, tc.inputList)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedRule, rule)
+		})
+	}
+}
+
+func TestDomainService_RemoveRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	domain := mock_storage.NewMockDomain(ctrl)
+	log := mock_logger.NewMockLogger(ctrl)
+
+	gomock.InOrder(
+		domain.EXPECT().DeleteDomainRule(gomock.Any(), "evil.com").Return(nil),
+		log.EXPECT().Info("DomainService.RemoveRule - domain rule was removed successfully", []any{zap.String("pattern", "evil.com")}),
+		domain.EXPECT().GetDomainRules(gomock.Any()).Return(nil, nil),
+		domain.EXPECT().DeleteDomainRule(gomock.Any(), "evil.com").Return(storageerrors.ErrDomainRuleNotFound),
+		log.EXPECT().Error("DomainService.RemoveRule", []any{
+			zap.String("pattern", "evil.com"),
+			zap.String("error", storageerrors.ErrDomainRuleNotFound.Error()),
+		}),
+	)
+
+	service := NewDomainService(Config{}, domain, log)
+	require.NoError(t, service.RemoveRule(context.Background(), "Evil.com"))
+	require.ErrorIs(t, service.RemoveRule(context.Background(), "evil.com"), ErrRuleNotFound)
+}
+
+func TestDomainService_CheckHost(t *testing.T) {
+	rules := []entity.DomainRule{
+		{Pattern: "*.evil.com", List: entity.DomainBlock},
+		{Pattern: "*.safe.evil.com", List: entity.DomainAllow},
+		{Pattern: "bad.safe.evil.com", List: entity.DomainBlock},
+		{Pattern: "phishing.org", List: entity.DomainBlock},
+		{Pattern: "10.0.0.1", List: entity.DomainBlock},
+		{Pattern: "go.dev", List: entity.DomainAllow},
+	}
+
+	testCases := []struct {
+		name          string
+		cfg           Config
+		host          string
+		expectedError error
+	}{
+		{name: "no rule", host: "google.com"},
+		{name: "exact", host: "phishing.org", expectedError: ErrDomainBlocked},
+		{name: "exact does not match subdomains", host: "www.phishing.org"},
+		{name: "wildcard", host: "a.b.evil.com", expectedError: ErrDomainBlocked},
+		{name: "wildcard matches the domain itself", host: "evil.com", expectedError: ErrDomainBlocked},
+		{name: "longer wildcard wins", host: "www.safe.evil.com"},
+		{name: "longer wildcard wins for its domain", host: "safe.evil.com"},
+		{name: "exact wins", host: "bad.safe.evil.com", expectedError: ErrDomainBlocked},
+		{name: "case and trailing dot", host: "WWW.Evil.COM.", expectedError: ErrDomainBlocked},
+		{name: "ip address", host: "10.0.0.1", expectedError: ErrDomainBlocked},
+		{name: "allowlist only allowed", cfg: Config{AllowlistOnly: true}, host: "go.dev"},
+		{name: "allowlist only not allowed", cfg: Config{AllowlistOnly: true}, host: "google.com", expectedError: ErrDomainNotAllowed},
+		{name: "allowlist only blocked", cfg: Config{AllowlistOnly: true}, host: "x.evil.com", expectedError: ErrDomainBlocked},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			domain := mock_storage.NewMockDomain(ctrl)
+			domain.EXPECT().GetDomainRules(gomock.Any()).Return(rules, nil)
+
+			service := NewDomainService(tc.cfg, domain, mock_logger.NewMockLogger(ctrl))
+			require.ErrorIs(t, service.CheckHost(context.Background(), tc.host), tc.expectedError)
+			// rules are loaded once
+			require.ErrorIs(t, service.CheckHost(context.Background(), tc.host), tc.expectedError)
+		})
+	}
+}
+
+func TestDomainService_CheckHostStorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	domain := mock_storage.NewMockDomain(ctrl)
+	log := mock_logger.NewMockLogger(ctrl)
+	domain.EXPECT().GetDomainRules(gomock.Any()).Return(nil, errors.New("connection refused"))
+	log.EXPECT().Error("DomainService.CheckHost - s.Refresh", []any{zap.String("error", "connection refused")})
+
+	err := NewDomainService(Config{}, domain, log).CheckHost(context.Background(), "google.com")
+	require.ErrorIs(t, err, ErrInternalError)
+}
+
+func TestDomainService_Rules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := mock_logger.NewMockLogger(ctrl)
+	log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	ctx := context.Background()
+	service := NewDomainService(Config{}, memorystorage.NewDomainRepo(), log)
+
+	require.NoError(t, service.CheckHost(ctx, "www.evil.com"))
+
+	_, err := service.AddRule(ctx, "*.evil.com", entity.DomainBlock)
+	require.NoError(t, err)
+	require.ErrorIs(t, service.CheckHost(ctx, "www.evil.com"), ErrDomainBlocked)
+
+	rules, err := service.ListRules(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []entity.DomainRule{{Pattern: "*.evil.com", List: entity.DomainBlock}}, rules)
+
+	require.NoError(t, service.RemoveRule(ctx, "*.evil.com"))
+	require.NoError(t, service.CheckHost(ctx, "www.evil.com"))
+}
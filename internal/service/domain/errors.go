@@ -0,0 +1,16 @@
+package domainservice
+
+import "errors"
+
+var (
+	ErrInternalError = errors.New("internal error")
+)
+
+var (
+	ErrInvalidPattern = errors.New("domain pattern must be a host or *. followed by a host")
+	ErrInvalidList    = errors.New("domain list must be block or allow")
+	ErrRuleNotFound   = errors.New("domain rule is not found")
+
+	ErrDomainBlocked    = errors.New("domain is blocked")
+	ErrDomainNotAllowed = errors.New("domain is not in the allowlist")
+)
@@ -12,6 +12,8 @@ import (
 	context "context"
 	reflect "reflect"
 
+	entity "github.com/romandnk/shortener/internal/entity"
+	urlservice "github.com/romandnk/shortener/internal/service/url"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,18 +41,48 @@ func (m *MockURL) EXPECT() *MockURLMockRecorder {
 }
 
 // CreateURLAlias mocks base method.
-func (m *MockURL) CreateURLAlias(ctx context.Context, original string) (string, error) {
+func (m *MockURL) CreateURLAlias(ctx context.Context, url entity.URL) (string, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateURLAlias", ctx, original)
+	ret := m.ctrl.Call(m, "CreateURLAlias", ctx, url)
 	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateURLAlias indicates an expected call of CreateURLAlias.
+func (mr *MockURLMockRecorder) CreateURLAlias(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateURLAlias", reflect.TypeOf((*MockURL)(nil).CreateURLAlias), ctx, url)
+}
+
+// CreateURLAliasBatch mocks base method.
+func (m *MockURL) CreateURLAliasBatch(ctx context.Context, urls []entity.URL) ([]urlservice.CreateResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateURLAliasBatch", ctx, urls)
+	ret0, _ := ret[0].([]urlservice.CreateResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateURLAlias indicates an expected call of CreateURLAlias.
-func (mr *MockURLMockRecorder) CreateURLAlias(ctx, original any) *gomock.Call {
+// CreateURLAliasBatch indicates an expected call of CreateURLAliasBatch.
+func (mr *MockURLMockRecorder) CreateURLAliasBatch(ctx, urls any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateURLAliasBatch", reflect.TypeOf((*MockURL)(nil).CreateURLAliasBatch), ctx, urls)
+}
+
+// DeleteByAlias mocks base method.
+func (m *MockURL) DeleteByAlias(ctx context.Context, alias string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByAlias", ctx, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByAlias indicates an expected call of DeleteByAlias.
+func (mr *MockURLMockRecorder) DeleteByAlias(ctx, alias any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateURLAlias", reflect.TypeOf((*MockURL)(nil).CreateURLAlias), ctx, original)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAlias", reflect.TypeOf((*MockURL)(nil).DeleteByAlias), ctx, alias)
 }
 
 // GetOriginalByAlias mocks base method.
@@ -67,3 +99,298 @@ func (mr *MockURLMockRecorder) GetOriginalByAlias(ctx, alias any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOriginalByAlias", reflect.TypeOf((*MockURL)(nil).GetOriginalByAlias), ctx, alias)
 }
+
+// ListURLs mocks base method.
+func (m *MockURL) ListURLs(ctx context.Context, cursor string, limit int) ([]entity.URL, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListURLs", ctx, cursor, limit)
+	ret0, _ := ret[0].([]entity.URL)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListURLs indicates an expected call of ListURLs.
+func (mr *MockURLMockRecorder) ListURLs(ctx, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListURLs", reflect.TypeOf((*MockURL)(nil).ListURLs), ctx, cursor, limit)
+}
+
+// Stats mocks base method.
+func (m *MockURL) Stats() urlservice.Stats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(urlservice.Stats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockURLMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockURL)(nil).Stats))
+}
+
+// UpdateOriginal mocks base method.
+func (m *MockURL) UpdateOriginal(ctx context.Context, alias, original string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOriginal", ctx, alias, original)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOriginal indicates an expected call of UpdateOriginal.
+func (mr *MockURLMockRecorder) UpdateOriginal(ctx, alias, original any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOriginal", reflect.TypeOf((*MockURL)(nil).UpdateOriginal), ctx, alias, original)
+}
+
+// MockClick is a mock of Click interface.
+type MockClick struct {
+	ctrl     *gomock.Controller
+	recorder *MockClickMockRecorder
+}
+
+// MockClickMockRecorder is the mock recorder for MockClick.
+type MockClickMockRecorder struct {
+	mock *MockClick
+}
+
+// NewMockClick creates a new mock instance.
+func NewMockClick(ctrl *gomock.Controller) *MockClick {
+	mock := &MockClick{ctrl: ctrl}
+	mock.recorder = &MockClickMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClick) EXPECT() *MockClickMockRecorder {
+	return m.recorder
+}
+
+// GetClickStats mocks base method.
+func (m *MockClick) GetClickStats(ctx context.Context, alias string) (entity.ClickStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStats", ctx, alias)
+	ret0, _ := ret[0].(entity.ClickStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStats indicates an expected call of GetClickStats.
+func (mr *MockClickMockRecorder) GetClickStats(ctx, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStats", reflect.TypeOf((*MockClick)(nil).GetClickStats), ctx, alias)
+}
+
+// RecordClick mocks base method.
+func (m *MockClick) RecordClick(click entity.Click, ip string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordClick", click, ip)
+}
+
+// RecordClick indicates an expected call of RecordClick.
+func (mr *MockClickMockRecorder) RecordClick(click, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordClick", reflect.TypeOf((*MockClick)(nil).RecordClick), click, ip)
+}
+
+// Run mocks base method.
+func (m *MockClick) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockClickMockRecorder) Run(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockClick)(nil).Run), ctx)
+}
+
+// MockAuth is a mock of Auth interface.
+type MockAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthMockRecorder
+}
+
+// MockAuthMockRecorder is the mock recorder for MockAuth.
+type MockAuthMockRecorder struct {
+	mock *MockAuth
+}
+
+// NewMockAuth creates a new mock instance.
+func NewMockAuth(ctrl *gomock.Controller) *MockAuth {
+	mock := &MockAuth{ctrl: ctrl}
+	mock.recorder = &MockAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuth) Authenticate(ctx context.Context, key string) (entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthMockRecorder) Authenticate(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuth)(nil).Authenticate), ctx, key)
+}
+
+// CreateKey mocks base method.
+func (m *MockAuth) CreateKey(ctx context.Context, name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKey", ctx, name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKey indicates an expected call of CreateKey.
+func (mr *MockAuthMockRecorder) CreateKey(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKey", reflect.TypeOf((*MockAuth)(nil).CreateKey), ctx, name)
+}
+
+// Enabled mocks base method.
+func (m *MockAuth) Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled.
+func (mr *MockAuthMockRecorder) Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockAuth)(nil).Enabled))
+}
+
+// IsAdmin mocks base method.
+func (m *MockAuth) IsAdmin(key entity.APIKey) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAdmin", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAdmin indicates an expected call of IsAdmin.
+func (mr *MockAuthMockRecorder) IsAdmin(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAdmin", reflect.TypeOf((*MockAuth)(nil).IsAdmin), key)
+}
+
+// ListKeys mocks base method.
+func (m *MockAuth) ListKeys(ctx context.Context) ([]entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKeys", ctx)
+	ret0, _ := ret[0].([]entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKeys indicates an expected call of ListKeys.
+func (mr *MockAuthMockRecorder) ListKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKeys", reflect.TypeOf((*MockAuth)(nil).ListKeys), ctx)
+}
+
+// RevokeKey mocks base method.
+func (m *MockAuth) RevokeKey(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeKey", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeKey indicates an expected call of RevokeKey.
+func (mr *MockAuthMockRecorder) RevokeKey(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeKey", reflect.TypeOf((*MockAuth)(nil).RevokeKey), ctx, name)
+}
+
+// MockDomain is a mock of Domain interface.
+type MockDomain struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainMockRecorder
+}
+
+// MockDomainMockRecorder is the mock recorder for MockDomain.
+type MockDomainMockRecorder struct {
+	mock *MockDomain
+}
+
+// NewMockDomain creates a new mock instance.
+func NewMockDomain(ctrl *gomock.Controller) *MockDomain {
+	mock := &MockDomain{ctrl: ctrl}
+	mock.recorder = &MockDomainMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomain) EXPECT() *MockDomainMockRecorder {
+	return m.recorder
+}
+
+// AddRule mocks base method.
+func (m *MockDomain) AddRule(ctx context.Context, pattern string, list entity.DomainList) (entity.DomainRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRule", ctx, pattern, list)
+	ret0, _ := ret[0].(entity.DomainRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRule indicates an expected call of AddRule.
+func (mr *MockDomainMockRecorder) AddRule(ctx, pattern, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRule", reflect.TypeOf((*MockDomain)(nil).AddRule), ctx, pattern, list)
+}
+
+// ListRules mocks base method.
+func (m *MockDomain) ListRules(ctx context.Context) ([]entity.DomainRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRules", ctx)
+	ret0, _ := ret[0].([]entity.DomainRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRules indicates an expected call of ListRules.
+func (mr *MockDomainMockRecorder) ListRules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockDomain)(nil).ListRules), ctx)
+}
+
+// RemoveRule mocks base method.
+func (m *MockDomain) RemoveRule(ctx context.Context, pattern string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRule", ctx, pattern)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRule indicates an expected call of RemoveRule.
+func (mr *MockDomainMockRecorder) RemoveRule(ctx, pattern any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRule", reflect.TypeOf((*MockDomain)(nil).RemoveRule), ctx, pattern)
+}
+
+// Run mocks base method.
+func (m *MockDomain) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockDomainMockRecorder) Run(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockDomain)(nil).Run), ctx)
+}
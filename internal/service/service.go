@@ -4,26 +4,80 @@ package service
 
 import (
 	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	authservice "github.com/romandnk/shortener/internal/service/auth"
+	clickservice "github.com/romandnk/shortener/internal/service/click"
+	domainservice "github.com/romandnk/shortener/internal/service/domain"
 	urlservice "github.com/romandnk/shortener/internal/service/url"
 	"github.com/romandnk/shortener/internal/storage"
 	"github.com/romandnk/shortener/pkg/generator"
 	"github.com/romandnk/shortener/pkg/logger"
-	"go.uber.org/fx"
 )
 
-var Module = fx.Module("services", fx.Provide(NewServices))
-
 type URL interface {
-	CreateURLAlias(ctx context.Context, original string) (string, error)
+	CreateURLAlias(ctx context.Context, url entity.URL) (string, bool, error)
+	// CreateURLAliasBatch returns results in order of urls, errors of single urls do not fail the batch.
+	CreateURLAliasBatch(ctx context.Context, urls []entity.URL) ([]urlservice.CreateResult, error)
 	GetOriginalByAlias(ctx context.Context, alias string) (string, error)
+	DeleteByAlias(ctx context.Context, alias string) error
+	UpdateOriginal(ctx context.Context, alias, original string) error
+	// ListURLs pages through not expired urls, the empty cursor starts from the first page
+	// and the returned cursor is empty after the last one.
+	ListURLs(ctx context.Context, cursor string, limit int) ([]entity.URL, string, error)
+	// Stats returns alias generation counters since the service start.
+	Stats() urlservice.Stats
+}
+
+type Click interface {
+	// RecordClick saves the click asynchronously, ip is hashed before saving.
+	RecordClick(click entity.Click, ip string)
+	GetClickStats(ctx context.Context, alias string) (entity.ClickStats, error)
+	// Run writes recorded clicks until ctx is done.
+	Run(ctx context.Context)
+}
+
+type Auth interface {
+	// Enabled reports whether requests changing urls require an api key.
+	Enabled() bool
+	// CreateKey returns a new api key, it cannot be received again.
+	CreateKey(ctx context.Context, name string) (string, error)
+	ListKeys(ctx context.Context) ([]entity.APIKey, error)
+	RevokeKey(ctx context.Context, name string) error
+	Authenticate(ctx context.Context, key string) (entity.APIKey, error)
+	// IsAdmin reports whether the api key can manage domain rules.
+	IsAdmin(key entity.APIKey) bool
+}
+
+type Domain interface {
+	// AddRule blocks or allows links to hosts matching the pattern, an existing pattern is moved to the list.
+	AddRule(ctx context.Context, pattern string, list entity.DomainList) (entity.DomainRule, error)
+	ListRules(ctx context.Context) ([]entity.DomainRule, error)
+	RemoveRule(ctx context.Context, pattern string) error
+	// Run reloads rules changed by other instances until ctx is done.
+	Run(ctx context.Context)
 }
 
 type Services struct {
-	URL URL
+	URL    URL
+	Click  Click
+	Auth   Auth
+	Domain Domain
 }
 
-func NewServices(generator generator.Generator, repo *storage.Storage, logger logger.Logger) *Services {
+type Config struct {
+	URL    urlservice.Config
+	Click  clickservice.Config
+	Auth   authservice.Config
+	Domain domainservice.Config
+}
+
+func NewServices(cfg Config, generator generator.Generator, repo *storage.Storage, logger logger.Logger) *Services {
+	domain := domainservice.NewDomainService(cfg.Domain, repo.Domain, logger)
+
 	return &Services{
-		URL: urlservice.NewURLService(generator, repo.URL, logger),
+		URL:    urlservice.NewURLService(cfg.URL, generator, repo.URL, domain, logger),
+		Domain: domain,
+		Click:  clickservice.NewClickService(cfg.Click, repo.Click, repo.URL, logger),
+		Auth:   authservice.NewAuthService(cfg.Auth, repo.APIKey, logger),
 	}
 }
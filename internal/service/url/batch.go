@@ -0,0 +1,150 @@
+package urlservice
+
+import (
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// CreateResult is a result of one url of a batch, Err is set if the url was not shortened.
+type CreateResult struct {
+	Alias string
+	// Existing is true if the url was already shortened, which is possible only in idempotent mode.
+	Existing bool
+	Err      error
+}
+
+type batchURL struct {
+	position int
+	url      entity.URL
+	custom   bool
+}
+
+// CreateURLAliasBatch shortens urls with a fixed number of storage calls and returns results in order of urls.
+// Invalid and conflicting urls do not fail the batch, they get errors in their results.
+// Generated aliases colliding with existing ones are regenerated in the next storage call.
+func (s *URLService) CreateURLAliasBatch(ctx context.Context, urls []entity.URL) (_ []CreateResult, err error) {
+	ctx, span := startSpan(ctx, "URLService.CreateURLAliasBatch", attribute.Int("size", len(urls)))
+	defer func() { endSpan(span, err) }()
+
+	if len(urls) == 0 {
+		s.logger.Error("URLService.CreateURLAliasBatch", zap.String("error", ErrEmptyBatch.Error()))
+		return nil, ErrEmptyBatch
+	}
+	if len(urls) > s.cfg.MaxBatchSize {
+		s.logger.Error("URLService.CreateURLAliasBatch", zap.Int("size", len(urls)), zap.String("error", ErrBatchTooLarge.Error()))
+		return nil, ErrBatchTooLarge
+	}
+
+	results := make([]CreateResult, len(urls))
+	batch := make([]batchURL, 0, len(urls))
+
+	now := time.Now()
+	for i, URL := range urls {
+		original, input, err := s.validateOriginal(ctx, "URLService.CreateURLAliasBatch", URL.Original)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if !URL.ExpiresAt.IsZero() && !URL.ExpiresAt.After(now) {
+			results[i].Err = ErrExpirationInPast
+			continue
+		}
+
+		custom := strings.TrimSpace(URL.Alias)
+		if custom != "" {
+			if err = s.validateCustomAlias(custom); err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+
+		batch = append(batch, batchURL{
+			position: i,
+			url: entity.URL{
+				Original:  original,
+				Alias:     custom,
+				Input:     input,
+				ExpiresAt: URL.ExpiresAt,
+			},
+			custom: custom != "",
+		})
+	}
+
+	for attempt := 1; len(batch) > 0; attempt++ {
+		batchURLs := make([]entity.URL, len(batch))
+		for i := range batch {
+			if !batch[i].custom {
+				batch[i].url.Alias, err = s.generator.Generate(batch[i].url.Original, attempt, s.aliasLength())
+				if err != nil {
+					s.logger.Error("URLService.CreateURLAliasBatch - s.generator.Generate()", zap.String("error", err.Error()))
+					return nil, ErrInternalError
+				}
+			}
+			batchURLs[i] = batch[i].url
+		}
+
+		errs, err := s.url.CreateURLs(ctx, batchURLs)
+		if err != nil {
+			s.logger.Error("URLService.CreateURLAliasBatch - s.url.CreateURLs", zap.String("error", err.Error()))
+			return nil, ErrInternalError
+		}
+
+		var retry []batchURL
+		for i, item := range batch {
+			if !item.custom {
+				collided := errors.Is(errs[i], storageerrors.ErrURLAliasExists)
+				s.recordGeneration(collided)
+				if collided && attempt < s.cfg.MaxAttempts {
+					retry = append(retry, item)
+					continue
+				}
+			}
+
+			result, recreate := s.batchResult(ctx, item, errs[i], attempt)
+			if recreate {
+				retry = append(retry, item)
+				continue
+			}
+			results[item.position] = result
+		}
+
+		batch = retry
+	}
+
+	s.logger.Info("URLService.CreateURLAliasBatch - batch was created", zap.Int("size", len(urls)))
+
+	return results, nil
+}
+
+// batchResult returns result of the url or reports that the url should be created again,
+// which happens in idempotent mode when the conflicting url disappeared after the conflict.
+func (s *URLService) batchResult(ctx context.Context, item batchURL, err error, attempt int) (CreateResult, bool) {
+	switch {
+	case err == nil:
+		return CreateResult{Alias: item.url.Alias}, false
+	case errors.Is(err, storageerrors.ErrOriginalURLExists) && s.cfg.Idempotent:
+		custom := ""
+		if item.custom {
+			custom = item.url.Alias
+		}
+		alias, existing, err := s.existingAlias(ctx, item.url.Original, custom)
+		if errors.Is(err, storageerrors.ErrOriginalNotFound) {
+			if attempt < s.cfg.MaxAttempts {
+				return CreateResult{}, true
+			}
+			s.logger.Error("URLService.CreateURLAliasBatch - s.url.GetAliasByOriginal",
+				zap.String("original", item.url.Original), zap.String("error", err.Error()))
+			return CreateResult{Err: ErrInternalError}, false
+		}
+		return CreateResult{Alias: alias, Existing: existing, Err: err}, false
+	default:
+		return CreateResult{Err: err}, false
+	}
+}
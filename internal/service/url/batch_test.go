@@ -0,0 +1,199 @@
+package urlservice
+
+import (
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	mock_storage "github.com/romandnk/shortener/internal/storage/mock"
+	mock_generate "github.com/romandnk/shortener/pkg/generator/mock"
+	mock_logger "github.com/romandnk/shortener/pkg/logger/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestURLService_CreateURLAliasBatch(t *testing.T) {
+	cfg := customAliasConfig
+	cfg.MaxAttempts = 2
+	cfg.MaxBatchSize = 10
+
+	input := []entity.URL{
+		{Original: " http://google.com/ "},
+		{Original: "invalid"},
+		{Original: "http://ya.ru/", Alias: "custom"},
+		{Original: "http://go.dev/"},
+		{Original: "http://past.com/", ExpiresAt: time.Now().Add(-time.Hour)},
+		{Original: "http://exists.com/", ExpiresAt: testExpiresAt},
+	}
+
+	testCases := []struct {
+		name            string
+		cfg             Config
+		generatorMock   func(m *mock_generate.MockGenerator)
+		urlMock         func(m *mock_storage.MockURL)
+		expectedResults []CreateResult
+	}{
+		{
+			name: "OK with collision",
+			cfg:  cfg,
+			generatorMock: func(m *mock_generate.MockGenerator) {
+				gomock.InOrder(
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("aaaaaaaaaa", nil),
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("bbbbbbbbbb", nil),
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("cccccccccc", nil),
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("dddddddddd", nil),
+				)
+			},
+			urlMock: func(m *mock_storage.MockURL) {
+				gomock.InOrder(
+					m.EXPECT().CreateURLs(gomock.Any(), []entity.URL{
+						{Original: "http://google.com/", Alias: "aaaaaaaaaa"},
+						{Original: "http://ya.ru/", Alias: "custom"},
+						{Original: "http://go.dev/", Alias: "bbbbbbbbbb"},
+						{Original: "http://exists.com/", Alias: "cccccccccc", ExpiresAt: testExpiresAt},
+					}).Return([]error{
+						nil,
+						storageerrors.ErrURLAliasExists,
+						storageerrors.ErrURLAliasExists,
+						storageerrors.ErrOriginalURLExists,
+					}, nil),
+					m.EXPECT().CreateURLs(gomock.Any(), []entity.URL{
+						{Original: "http://go.dev/", Alias: "dddddddddd"},
+					}).Return([]error{nil}, nil),
+				)
+			},
+			expectedResults: []CreateResult{
+				{Alias: "aaaaaaaaaa"},
+				{Err: ErrInvalidOriginalURL},
+				{Err: storageerrors.ErrURLAliasExists},
+				{Alias: "dddddddddd"},
+				{Err: ErrExpirationInPast},
+				{Err: storageerrors.ErrOriginalURLExists},
+			},
+		},
+		{
+			name: "idempotent",
+			cfg: func() Config {
+				cfg := cfg
+				cfg.Idempotent = true
+				return cfg
+			}(),
+			generatorMock: func(m *mock_generate.MockGenerator) {
+				m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("aaaaaaaaaa", nil).Times(3)
+			},
+			urlMock: func(m *mock_storage.MockURL) {
+				m.EXPECT().CreateURLs(gomock.Any(), gomock.Any()).Return([]error{
+					nil,
+					nil,
+					nil,
+					storageerrors.ErrOriginalURLExists,
+				}, nil)
+				m.EXPECT().GetAliasByOriginal(gomock.Any(), "http://exists.com/").Return("eeeeeeeeee", nil)
+			},
+			expectedResults: []CreateResult{
+				{Alias: "aaaaaaaaaa"},
+				{Err: ErrInvalidOriginalURL},
+				{Alias: "custom"},
+				{Alias: "aaaaaaaaaa"},
+				{Err: ErrExpirationInPast},
+				{Alias: "eeeeeeeeee", Existing: true},
+			},
+		},
+		{
+			name: "idempotent existing url disappeared",
+			cfg: func() Config {
+				cfg := cfg
+				cfg.Idempotent = true
+				return cfg
+			}(),
+			generatorMock: func(m *mock_generate.MockGenerator) {
+				gomock.InOrder(
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("aaaaaaaaaa", nil),
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("bbbbbbbbbb", nil),
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("cccccccccc", nil),
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("dddddddddd", nil),
+				)
+			},
+			urlMock: func(m *mock_storage.MockURL) {
+				gomock.InOrder(
+					m.EXPECT().CreateURLs(gomock.Any(), []entity.URL{
+						{Original: "http://google.com/", Alias: "aaaaaaaaaa"},
+						{Original: "http://ya.ru/", Alias: "custom"},
+						{Original: "http://go.dev/", Alias: "bbbbbbbbbb"},
+						{Original: "http://exists.com/", Alias: "cccccccccc", ExpiresAt: testExpiresAt},
+					}).Return([]error{
+						nil,
+						nil,
+						nil,
+						storageerrors.ErrOriginalURLExists,
+					}, nil),
+					m.EXPECT().GetAliasByOriginal(gomock.Any(), "http://exists.com/").Return("", storageerrors.ErrOriginalNotFound),
+					m.EXPECT().CreateURLs(gomock.Any(), []entity.URL{
+						{Original: "http://exists.com/", Alias: "dddddddddd", ExpiresAt: testExpiresAt},
+					}).Return([]error{nil}, nil),
+				)
+			},
+			expectedResults: []CreateResult{
+				{Alias: "aaaaaaaaaa"},
+				{Err: ErrInvalidOriginalURL},
+				{Alias: "custom"},
+				{Alias: "bbbbbbbbbb"},
+				{Err: ErrExpirationInPast},
+				{Alias: "dddddddddd"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			logger := mock_logger.NewMockLogger(ctrl)
+			logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+			logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+			generator := mock_generate.NewMockGenerator(ctrl)
+			tc.generatorMock(generator)
+
+			url := mock_storage.NewMockURL(ctrl)
+			tc.urlMock(url)
+
+			service := NewURLService(tc.cfg, generator, url, allowAllDomains, logger)
+
+			results, err := service.CreateURLAliasBatch(context.Background(), input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedResults, results)
+		})
+	}
+}
+
+func TestURLService_CreateURLAliasBatchErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_logger.NewMockLogger(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	generator := mock_generate.NewMockGenerator(ctrl)
+	generator.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("aaaaaaaaaa", nil)
+
+	url := mock_storage.NewMockURL(ctrl)
+	url.EXPECT().CreateURLs(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	service := NewURLService(Config{MaxAttempts: 1, MaxBatchSize: 1}, generator, url, allowAllDomains, logger)
+	ctx := context.Background()
+
+	_, err := service.CreateURLAliasBatch(ctx, nil)
+	require.ErrorIs(t, err, ErrEmptyBatch)
+
+	_, err = service.CreateURLAliasBatch(ctx, make([]entity.URL, 2))
+	require.ErrorIs(t, err, ErrBatchTooLarge)
+
+	_, err = service.CreateURLAliasBatch(ctx, []entity.URL{{Original: "http://google.com/"}})
+	require.ErrorIs(t, err, ErrInternalError)
+}
@@ -0,0 +1,38 @@
+package urlservice
+
+import (
+	"github.com/romandnk/shortener/pkg/generator"
+	"github.com/romandnk/shortener/pkg/urlnorm"
+)
+
+type Config struct {
+	// Idempotent makes CreateURLAlias return the existing alias
+	// instead of an error when the original url was already shortened.
+	Idempotent bool `yaml:"idempotent" env:"URL_SERVICE_IDEMPOTENT"`
+	// MaxAttempts is how many aliases are generated for one url
+	// while generated aliases collide with existing ones.
+	MaxAttempts int `yaml:"max_attempts" env-default:"5"`
+	// CollisionRate is a share of collided aliases within CollisionWindow
+	// generations, reaching which increases alias length by one symbol.
+	CollisionRate   float64 `yaml:"collision_rate" env-default:"0.01"`
+	CollisionWindow int     `yaml:"collision_window" env-default:"1000"`
+	MaxAliasLength  int     `yaml:"max_alias_length" env-default:"16"`
+	// Alphabet and AliasLength of generated aliases are set from the generator configuration,
+	// zero values are the default alphabet and constant.AliasLength.
+	Alphabet    generator.Alphabet `yaml:"-"`
+	AliasLength int                `yaml:"-"`
+	// CustomAliasMinLength and CustomAliasMaxLength bound aliases chosen by clients,
+	// the minimum is raised to generator.MinLength, zero CustomAliasMaxLength disables custom aliases.
+	CustomAliasMinLength int `yaml:"custom_alias_min_length" env-default:"4"`
+	CustomAliasMaxLength int `yaml:"custom_alias_max_length" env-default:"32"`
+	// ReservedAliases cannot be chosen by clients, the check is case-insensitive.
+	ReservedAliases []string `yaml:"reserved_aliases" env-default:"api,swagger,services"`
+	// MaxBatchSize is how many urls can be shortened with one batch request.
+	MaxBatchSize int `yaml:"max_batch_size" env-default:"1000"`
+	// Normalization brings original urls to one form before they are validated and stored.
+	Normalization urlnorm.Config `yaml:"normalization"`
+	// Policy restricts which normalized original urls can be shortened.
+	Policy PolicyConfig `yaml:"policy"`
+	// Shorteners rejects links to other url shorteners and to this service.
+	Shorteners ShortenerConfig `yaml:"shorteners"`
+}
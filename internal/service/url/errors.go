@@ -11,7 +11,54 @@ var (
 	ErrEmptyOriginalURL   = errors.New("url cannot be empty")
 	ErrOriginalURLTooLong = errors.New("max url length is 2048")
 
+	ErrExpirationInPast   = errors.New("expiration time must be in the future")
+	ErrInvalidTTL         = errors.New("ttl must be positive")
+	ErrExpirationConflict = errors.New("only one of expiration time and ttl can be set")
+
 	ErrEmptyURLAlias       = errors.New("empty url unique id")
-	ErrInvalidAliasFormat  = errors.New("unique id must be 10 symbols length")
+	ErrInvalidAliasFormat  = errors.New("unique id has invalid length")
+	ErrInvalidAliasSymbols = errors.New("unique id contains invalid symbols")
+	ErrReservedAlias       = errors.New("unique id is reserved")
+	ErrCustomAliasDisabled = errors.New("custom unique ids are disabled")
 	ErrOriginalURLNotFound = errors.New("original url is not found")
+	ErrOriginalURLExpired  = errors.New("url alias is expired")
+	ErrOriginalURLBlocked  = errors.New("url alias is disabled, its domain is blocked")
+)
+
+var (
+	ErrEmptyBatch    = errors.New("batch cannot be empty")
+	ErrBatchTooLarge = errors.New("batch is too large")
+
+	ErrInvalidPageSize = errors.New("page size must be positive")
+)
+
+// Reasons of rejected urls, they are stable and can be checked by clients.
+const (
+	ReasonSchemeNotAllowed = "SCHEME_NOT_ALLOWED"
+	ReasonPrivateHost      = "PRIVATE_HOST"
+	ReasonCredentials      = "CREDENTIALS_NOT_ALLOWED"
+	ReasonDomainBlocked    = "DOMAIN_BLOCKED"
+	ReasonDomainNotAllowed = "DOMAIN_NOT_ALLOWED"
+	ReasonShortenerURL     = "SHORTENER_NOT_ALLOWED"
+	ReasonSelfLink         = "SELF_LINK"
+)
+
+var (
+	ErrSchemeNotAllowed = &RejectedURLError{Reason: ReasonSchemeNotAllowed, Message: "url scheme is not allowed"}
+	ErrPrivateHost      = &RejectedURLError{Reason: ReasonPrivateHost, Message: "url host is private"}
+	ErrCredentialsInURL = &RejectedURLError{Reason: ReasonCredentials, Message: "url cannot contain credentials"}
+	ErrDomainBlocked    = &RejectedURLError{Reason: ReasonDomainBlocked, Message: "url domain is blocked"}
+	ErrDomainNotAllowed = &RejectedURLError{Reason: ReasonDomainNotAllowed, Message: "url domain is not in the allowlist"}
+	ErrShortenerURL     = &RejectedURLError{Reason: ReasonShortenerURL, Message: "url points to another url shortener"}
+	ErrSelfLink         = &RejectedURLError{Reason: ReasonSelfLink, Message: "url points to this url shortener"}
 )
+
+// RejectedURLError is returned for well-formed urls the policy does not allow to shorten.
+type RejectedURLError struct {
+	Reason  string
+	Message string
+}
+
+func (e *RejectedURLError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,194 @@
+package urlservice
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PolicyConfig struct {
+	// AllowedSchemes are schemes of original urls, the check is case-insensitive.
+	AllowedSchemes []string `yaml:"allowed_schemes" env-default:"http,https"`
+	// AllowPrivateHosts permits loopback, private, link-local and other non-public addresses
+	// and local host names like localhost or names without a dot.
+	AllowPrivateHosts bool `yaml:"allow_private_hosts" env:"URL_POLICY_ALLOW_PRIVATE_HOSTS"`
+	// AllowCredentials permits user and password in original urls.
+	AllowCredentials bool `yaml:"allow_credentials" env:"URL_POLICY_ALLOW_CREDENTIALS"`
+	// ResolveHosts rejects host names resolving to non-public addresses,
+	// creation of links then depends on DNS and takes up to ResolveTimeout longer.
+	ResolveHosts   bool          `yaml:"resolve_hosts" env:"URL_POLICY_RESOLVE_HOSTS"`
+	ResolveTimeout time.Duration `yaml:"resolve_timeout" env-default:"2s"`
+}
+
+// nonPublicPrefixes are special purpose ranges not covered by netip.Addr methods.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// localSuffixes are suffixes of host names which never resolve to public addresses.
+var localSuffixes = []string{".localhost", ".local", ".internal", ".home.arpa"}
+
+// resolver looks up addresses of host names.
+type resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// policy checks normalized original urls.
+type policy struct {
+	cfg      PolicyConfig
+	schemes  map[string]struct{}
+	resolver resolver
+}
+
+func newPolicy(cfg PolicyConfig) *policy {
+	p := policy{
+		cfg:      cfg,
+		schemes:  make(map[string]struct{}, len(cfg.AllowedSchemes)),
+		resolver: net.DefaultResolver,
+	}
+	schemes := cfg.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	for _, scheme := range schemes {
+		p.schemes[strings.ToLower(strings.TrimSpace(scheme))] = struct{}{}
+	}
+
+	return &p
+}
+
+// check returns ErrInvalidOriginalURL for urls without a host and RejectedURLError for urls denied by the policy.
+func (p *policy) check(ctx context.Context, u *url.URL) error {
+	if _, ok := p.schemes[u.Scheme]; !ok {
+		return ErrSchemeNotAllowed
+	}
+	if u.Opaque != "" {
+		return nil
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return ErrInvalidOriginalURL
+	}
+	if u.User != nil && !p.cfg.AllowCredentials {
+		return ErrCredentialsInURL
+	}
+	if p.cfg.AllowPrivateHosts {
+		return nil
+	}
+
+	if addr, ok := parseHostAddr(host); ok {
+		if !isPublic(addr) {
+			return ErrPrivateHost
+		}
+		return nil
+	}
+
+	if !strings.Contains(host, ".") || host == "localhost" {
+		return ErrPrivateHost
+	}
+	for _, suffix := range localSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return ErrPrivateHost
+		}
+	}
+
+	if p.cfg.ResolveHosts {
+		return p.resolve(ctx, host)
+	}
+	return nil
+}
+
+func (p *policy) resolve(ctx context.Context, host string) error {
+	if p.cfg.ResolveTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.cfg.ResolveTimeout)
+		defer cancel()
+	}
+
+	addrs, err := p.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		// hosts which do not resolve now are not reachable from the service either
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil
+		}
+		return err
+	}
+	for _, addr := range addrs {
+		if !isPublic(addr) {
+			return ErrPrivateHost
+		}
+	}
+
+	return nil
+}
+
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() || addr.IsMulticast() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseHostAddr parses ip addresses including IPv4 written with fewer parts or in octal and hex,
+// like 2130706433 or 0x7f.1, which browsers and resolvers accept as 127.0.0.1.
+func parseHostAddr(host string) (netip.Addr, bool) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.WithZone(""), true
+	}
+
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return netip.Addr{}, false
+	}
+
+	values := make([]uint64, len(parts))
+	for i, part := range parts {
+		base := 10
+		switch {
+		case strings.HasPrefix(part, "0x"):
+			part, base = part[2:], 16
+		case len(part) > 1 && strings.HasPrefix(part, "0"):
+			part, base = part[1:], 8
+		}
+		value, err := strconv.ParseUint(part, base, 32)
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		values[i] = value
+	}
+
+	// the last part fills all remaining bytes
+	var ip uint64
+	for i, value := range values[:len(values)-1] {
+		if value > 0xff {
+			return netip.Addr{}, false
+		}
+		ip |= value << (24 - 8*i)
+	}
+	last := values[len(values)-1]
+	if last >= 1<<(8*(5-len(values))) {
+		return netip.Addr{}, false
+	}
+	ip |= last
+
+	return netip.AddrFrom4([4]byte{byte(ip >> 24), byte(ip >> 16), byte(ip >> 8), byte(ip)}), true
+}
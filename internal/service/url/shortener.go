@@ -0,0 +1,97 @@
+package urlservice
+
+import (
+	"context"
+	"errors"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/generator"
+	"golang.org/x/net/idna"
+	"net"
+	"net/url"
+	"strings"
+)
+
+type ShortenerConfig struct {
+	// Hosts are known url shorteners, links to them and their subdomains hide the real destination.
+	Hosts []string `yaml:"hosts" env-default:"bit.ly,bitly.com,tinyurl.com,t.co,goo.gl,ow.ly,is.gd,v.gd,buff.ly,rebrand.ly,cutt.ly,shorturl.at,tiny.cc,rb.gy,t.ly,clck.ru"`
+	// PublicHosts are host names short links of this service are served on,
+	// links to them would redirect back to the service.
+	PublicHosts []string `yaml:"public_hosts" env:"URL_PUBLIC_HOSTS"`
+	// ResolveOwnAliases stores the original url of a link to an own alias instead of rejecting it.
+	ResolveOwnAliases bool `yaml:"resolve_own_aliases" env:"URL_RESOLVE_OWN_ALIASES"`
+}
+
+// shorteners rejects links to url shorteners including this one.
+type shorteners struct {
+	hosts  map[string]struct{}
+	public map[string]struct{}
+}
+
+func newShorteners(cfg ShortenerConfig) *shorteners {
+	return &shorteners{
+		hosts:  hostSet(cfg.Hosts),
+		public: hostSet(cfg.PublicHosts),
+	}
+}
+
+// hostSet brings configured hosts to the form of normalized urls, ports are ignored.
+func hostSet(hosts []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.TrimSuffix(host, ".")
+		if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+			host = ascii
+		}
+		if host != "" {
+			set[host] = struct{}{}
+		}
+	}
+	return set
+}
+
+// check returns ErrSelfLink for public hosts of the service and ErrShortenerURL
+// for known shorteners and their subdomains.
+func (s *shorteners) check(host string) error {
+	if _, ok := s.public[host]; ok {
+		return ErrSelfLink
+	}
+	for h := host; h != ""; {
+		if _, ok := s.hosts[h]; ok {
+			return ErrShortenerURL
+		}
+		_, h, _ = strings.Cut(h, ".")
+	}
+	return nil
+}
+
+// resolveOwnAlias returns the original url of a link to an alias of this service,
+// links to other pages of the service and to unknown aliases stay self links.
+func (s *URLService) resolveOwnAlias(ctx context.Context, u *url.URL) (string, error) {
+	alias := strings.TrimPrefix(u.EscapedPath(), "/")
+	if alias == "" || strings.Contains(alias, "/") || !generator.AlphabetURLSafe.Contains(alias) {
+		return "", ErrSelfLink
+	}
+
+	original, err := s.url.GetOriginalByAlias(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrURLAliasNotFound) || errors.Is(err, storageerrors.ErrURLExpired) {
+			return "", ErrSelfLink
+		}
+		return "", err
+	}
+
+	// rules could change since the alias was created
+	parsed, err := url.Parse(original)
+	if err != nil {
+		return "", err
+	}
+	if err = s.checkOriginal(ctx, parsed); err != nil {
+		return "", err
+	}
+
+	return original, nil
+}
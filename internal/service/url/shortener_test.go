@@ -0,0 +1,35 @@
+package urlservice
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestShorteners_Check(t *testing.T) {
+	s := newShorteners(ShortenerConfig{
+		Hosts:       []string{"bit.ly", " T.CO ", "пример.рф"},
+		PublicHosts: []string{"Sho.rt:8080", "go.sho.rt."},
+	})
+
+	testCases := []struct {
+		host          string
+		expectedError error
+	}{
+		{host: "google.com"},
+		{host: "bit.ly", expectedError: ErrShortenerURL},
+		{host: "www.bit.ly", expectedError: ErrShortenerURL},
+		{host: "notbit.ly"},
+		{host: "t.co", expectedError: ErrShortenerURL},
+		{host: "xn--e1afmkfd.xn--p1ai", expectedError: ErrShortenerURL},
+		{host: "sho.rt", expectedError: ErrSelfLink},
+		{host: "go.sho.rt", expectedError: ErrSelfLink},
+		{host: "www.sho.rt"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.host, func(t *testing.T) {
+			require.ErrorIs(t, s.check(tc.host), tc.expectedError)
+		})
+	}
+}
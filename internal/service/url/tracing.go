@@ -0,0 +1,28 @@
+package urlservice
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/romandnk/shortener/internal/service/url"
+
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, method, trace.WithAttributes(attrs...))
+}
+
+// endSpan marks the span failed on internal errors only,
+// invalid input and missing urls are expected results.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetAttributes(attribute.String("error.message", err.Error()))
+		if errors.Is(err, ErrInternalError) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
@@ -5,88 +5,295 @@ import (
 	"errors"
 	"github.com/romandnk/shortener/internal/constant"
 	"github.com/romandnk/shortener/internal/entity"
+	domainservice "github.com/romandnk/shortener/internal/service/domain"
 	"github.com/romandnk/shortener/internal/storage"
 	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
 	"github.com/romandnk/shortener/pkg/generator"
 	"github.com/romandnk/shortener/pkg/logger"
+	"github.com/romandnk/shortener/pkg/urlnorm"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 	"unicode/utf8"
 )
 
+// DomainChecker tells whether links to a host can be created and followed.
+type DomainChecker interface {
+	// CheckHost returns domainservice.ErrDomainBlocked or domainservice.ErrDomainNotAllowed for denied hosts.
+	CheckHost(ctx context.Context, host string) error
+}
+
 type URLService struct {
-	generator generator.Generator
-	url       storage.URL
-	logger    logger.Logger
+	cfg        Config
+	generator  generator.Generator
+	normalizer *urlnorm.Normalizer
+	policy     *policy
+	shorteners *shorteners
+	url        storage.URL
+	domains    DomainChecker
+	logger     logger.Logger
+
+	mu     sync.Mutex
+	length int
+	window Stats
+	stats  Stats
 }
 
-func NewURLService(generator generator.Generator, url storage.URL, logger logger.Logger) *URLService {
-	return &URLService{
-		generator: generator,
-		url:       url,
-		logger:    logger,
-	}
+// Stats counts generated aliases and collisions with existing ones.
+type Stats struct {
+	Generated   uint64
+	Collisions  uint64
+	AliasLength int
 }
 
-func (s *URLService) CreateURLAlias(ctx context.Context, original string) (string, error) {
-	original = strings.TrimSpace(original)
-	if original == "" {
-		s.logger.Error("URLService.CreateURLAlias", zap.String("error", ErrEmptyOriginalURL.Error()))
-		return "", ErrEmptyOriginalURL
+func NewURLService(cfg Config, generator generator.Generator, url storage.URL, domains DomainChecker, logger logger.Logger) *URLService {
+	s := &URLService{
+		cfg:        cfg,
+		generator:  generator,
+		normalizer: urlnorm.New(cfg.Normalization),
+		policy:     newPolicy(cfg.Policy),
+		shorteners: newShorteners(cfg.Shorteners),
+		url:        url,
+		domains:    domains,
+		logger:     logger,
 	}
+	s.length = s.generatedLength()
 
-	if utf8.RuneCountInString(original) > 2048 {
-		s.logger.Error("URLService.CreateURLAlias", zap.String("error", ErrOriginalURLTooLong.Error()))
-		return "", ErrOriginalURLTooLong
-	}
+	return s
+}
 
-	_, err := url.ParseRequestURI(original)
+// CreateURLAlias returns alias of the original url and reports whether
+// the alias already existed, which is possible only in idempotent mode.
+// URL alias is optional and is generated if empty.
+func (s *URLService) CreateURLAlias(ctx context.Context, URL entity.URL) (_ string, _ bool, err error) {
+	ctx, span := startSpan(ctx, "URLService.CreateURLAlias")
+	defer func() { endSpan(span, err) }()
+
+	original, input, err := s.validateOriginal(ctx, "URLService.CreateURLAlias", URL.Original)
 	if err != nil {
-		s.logger.Error("URLService.CreateURLAlias", zap.String("original", original), zap.String("error", err.Error()))
-		return "", ErrInvalidOriginalURL
+		return "", false, err
 	}
 
-	alias, err := s.generator.Random()
-	if err != nil {
-		s.logger.Error("URLService.CreateURLAlias - s.generator.Random()", zap.String("error", err.Error()))
-		return "", ErrInternalError
+	if !URL.ExpiresAt.IsZero() && !URL.ExpiresAt.After(time.Now()) {
+		s.logger.Error("URLService.CreateURLAlias", zap.String("error", ErrExpirationInPast.Error()))
+		return "", false, ErrExpirationInPast
 	}
 
-	URL := entity.URL{
-		Original: original,
-		Alias:    alias,
+	custom := strings.TrimSpace(URL.Alias)
+	if custom != "" {
+		if err = s.validateCustomAlias(custom); err != nil {
+			s.logger.Error("URLService.CreateURLAlias", zap.String("alias", custom), zap.String("error", err.Error()))
+			return "", false, err
+		}
 	}
 
-	err = s.url.CreateURL(ctx, URL)
+	var alias string
+	for attempt := 1; ; attempt++ {
+		alias = custom
+		if alias == "" {
+			alias, err = s.generator.Generate(original, attempt, s.aliasLength())
+			if err != nil {
+				s.logger.Error("URLService.CreateURLAlias - s.generator.Generate()", zap.String("error", err.Error()))
+				return "", false, ErrInternalError
+			}
+		}
+
+		err = s.url.CreateURL(ctx, entity.URL{
+			Original:  original,
+			Alias:     alias,
+			Input:     input,
+			ExpiresAt: URL.ExpiresAt,
+		})
+		collided := custom == "" && errors.Is(err, storageerrors.ErrURLAliasExists)
+		if custom == "" {
+			s.recordGeneration(collided)
+		}
+
+		if s.cfg.Idempotent && errors.Is(err, storageerrors.ErrOriginalURLExists) {
+			var existing bool
+			alias, existing, err = s.existingAlias(ctx, original, custom)
+			if !errors.Is(err, storageerrors.ErrOriginalNotFound) {
+				return alias, existing, err
+			}
+			if attempt >= s.cfg.MaxAttempts {
+				s.logger.Error("URLService.CreateURLAlias - s.url.GetAliasByOriginal",
+					zap.String("original", original), zap.String("error", err.Error()))
+				return "", false, ErrInternalError
+			}
+
+			s.logger.Info("URLService.CreateURLAlias - existing url disappeared, retrying",
+				zap.String("original", original), zap.Int("attempt", attempt))
+			continue
+		}
+
+		if !collided || attempt >= s.cfg.MaxAttempts {
+			break
+		}
+
+		s.logger.Info("URLService.CreateURLAlias - alias collision, retrying",
+			zap.String("alias", alias), zap.Int("attempt", attempt))
+	}
 	if err != nil {
 		if errors.Is(err, storageerrors.ErrOriginalURLExists) {
 			s.logger.Error("URLService.CreateURLAlias", zap.String("original", original), zap.String("error", err.Error()))
-			return "", err
+			return "", false, err
 		}
 		if errors.Is(err, storageerrors.ErrURLAliasExists) {
 			s.logger.Error("URLService.CreateURLAlias", zap.String("alias", alias), zap.String("error", err.Error()))
-			return "", err
+			return "", false, err
 		}
 		s.logger.Error("URLService.CreateURLAlias - s.url.CreateURL", zap.String("error", err.Error()))
-		return "", ErrInternalError
+		return "", false, ErrInternalError
 	}
 
 	s.logger.Info("URLService.CreateURLAlias - alias was created successfully", zap.String("alias", alias))
 
-	return alias, nil
+	return alias, false, nil
 }
 
-func (s *URLService) GetOriginalByAlias(ctx context.Context, alias string) (string, error) {
-	alias = strings.TrimSpace(alias)
-	if alias == "" {
-		s.logger.Error("URLService.GetOriginalByAlias", zap.String("error", ErrEmptyURLAlias.Error()))
-		return "", ErrEmptyURLAlias
+// ExpirationTime returns expiration time of a new url given either an absolute
+// expiration time or a ttl relative to now. Zero time means the url never expires.
+func ExpirationTime(expiresAt time.Time, ttl time.Duration) (time.Time, error) {
+	if ttl == 0 {
+		return expiresAt, nil
+	}
+	if !expiresAt.IsZero() {
+		return time.Time{}, ErrExpirationConflict
+	}
+	if ttl < 0 {
+		return time.Time{}, ErrInvalidTTL
 	}
+	return time.Now().Add(ttl), nil
+}
 
-	if utf8.RuneCountInString(alias) != constant.AliasLength {
-		s.logger.Error("URLService.GetOriginalByAlias", zap.String("error", ErrInvalidAliasFormat.Error()))
-		return "", ErrInvalidAliasFormat
+// existingAlias returns the alias the original url is already shortened with.
+// ErrOriginalNotFound is returned as is if the url expired or was deleted after
+// the conflict, so that the caller creates it again.
+func (s *URLService) existingAlias(ctx context.Context, original, custom string) (string, bool, error) {
+	alias, err := s.url.GetAliasByOriginal(ctx, original)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrOriginalNotFound) {
+			return "", false, storageerrors.ErrOriginalNotFound
+		}
+		s.logger.Error("URLService.CreateURLAlias - s.url.GetAliasByOriginal",
+			zap.String("original", original), zap.String("error", err.Error()))
+		return "", false, ErrInternalError
+	}
+
+	// the url is shortened with another alias than the client asked for
+	if custom != "" && custom != alias {
+		s.logger.Error("URLService.CreateURLAlias", zap.String("original", original),
+			zap.String("error", storageerrors.ErrOriginalURLExists.Error()))
+		return "", false, storageerrors.ErrOriginalURLExists
+	}
+
+	s.logger.Info("URLService.CreateURLAlias - existing alias was returned", zap.String("alias", alias))
+
+	return alias, true, nil
+}
+
+// Stats returns alias generation counters since the service start.
+func (s *URLService) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	stats.AliasLength = s.length
+
+	return stats
+}
+
+func (s *URLService) aliasLength() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.length
+}
+
+// generatedLength returns the initial length of generated aliases.
+func (s *URLService) generatedLength() int {
+	if s.cfg.AliasLength <= 0 {
+		return constant.AliasLength
+	}
+	return s.cfg.AliasLength
+}
+
+func (s *URLService) alphabet() generator.Alphabet {
+	if s.cfg.Alphabet == "" {
+		return generator.AlphabetDefault
+	}
+	return s.cfg.Alphabet
+}
+
+func (s *URLService) maxAliasLength() int {
+	if s.cfg.MaxAliasLength < s.generatedLength() {
+		return s.generatedLength()
+	}
+	return s.cfg.MaxAliasLength
+}
+
+// validateCustomAlias checks aliases chosen by clients, they are never shorter than generated ones can be.
+func (s *URLService) validateCustomAlias(alias string) error {
+	if s.cfg.CustomAliasMaxLength == 0 {
+		return ErrCustomAliasDisabled
+	}
+
+	length := utf8.RuneCountInString(alias)
+	if length < max(s.cfg.CustomAliasMinLength, generator.MinLength) || length > s.cfg.CustomAliasMaxLength {
+		return ErrInvalidAliasFormat
+	}
+
+	if !s.alphabet().Contains(alias) {
+		return ErrInvalidAliasSymbols
+	}
+
+	for _, reserved := range s.cfg.ReservedAliases {
+		if strings.EqualFold(alias, reserved) {
+			return ErrReservedAlias
+		}
+	}
+
+	return nil
+}
+
+// recordGeneration counts collisions within a window of generations and
+// increases alias length when collisions become too frequent, so the keyspace
+// never silently fills up. The length is kept in memory of each replica.
+func (s *URLService) recordGeneration(collided bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.Generated++
+	s.window.Generated++
+	if collided {
+		s.stats.Collisions++
+		s.window.Collisions++
+	}
+
+	if s.cfg.CollisionWindow <= 0 || s.window.Generated < uint64(s.cfg.CollisionWindow) {
+		return
+	}
+
+	rate := float64(s.window.Collisions) / float64(s.window.Generated)
+	if s.window.Collisions > 0 && rate >= s.cfg.CollisionRate && s.length < s.maxAliasLength() {
+		s.length++
+		s.logger.Info("URLService - alias length was increased",
+			zap.Int("length", s.length), zap.Float64("collision rate", rate))
+	}
+
+	s.window = Stats{}
+}
+
+func (s *URLService) GetOriginalByAlias(ctx context.Context, alias string) (_ string, err error) {
+	ctx, span := startSpan(ctx, "URLService.GetOriginalByAlias", attribute.String("alias", alias))
+	defer func() { endSpan(span, err) }()
+
+	alias, err = s.validateAlias("URLService.GetOriginalByAlias", alias)
+	if err != nil {
+		return "", err
 	}
 
 	original, err := s.url.GetOriginalByAlias(ctx, alias)
@@ -95,11 +302,213 @@ func (s *URLService) GetOriginalByAlias(ctx context.Context, alias string) (stri
 			s.logger.Error("URLService.GetOriginalByAlias", zap.String("alias", alias), zap.String("error", err.Error()))
 			return "", ErrOriginalURLNotFound
 		}
+		if errors.Is(err, storageerrors.ErrURLExpired) {
+			s.logger.Error("URLService.GetOriginalByAlias", zap.String("alias", alias), zap.String("error", err.Error()))
+			return "", ErrOriginalURLExpired
+		}
 		s.logger.Error("URLService.GetOriginalByAlias - s.url.GetOriginalByAlias", zap.String("error", err.Error()))
 		return "", ErrInternalError
 	}
 
+	// links are disabled while their domain is blocked, so blocking applies to existing links too
+	if u, parseErr := url.Parse(original); parseErr == nil && u.Host != "" {
+		if err = s.checkDomain(ctx, u.Hostname()); err != nil {
+			s.logger.Error("URLService.GetOriginalByAlias", zap.String("alias", alias), zap.String("error", err.Error()))
+			var rejected *RejectedURLError
+			if !errors.As(err, &rejected) {
+				return "", ErrInternalError
+			}
+			return "", ErrOriginalURLBlocked
+		}
+	}
+
 	s.logger.Info("URLService.GetOriginalByAlias - alias was received successfully", zap.String("alias", alias))
 
 	return original, nil
 }
+
+func (s *URLService) DeleteByAlias(ctx context.Context, alias string) (err error) {
+	ctx, span := startSpan(ctx, "URLService.DeleteByAlias", attribute.String("alias", alias))
+	defer func() { endSpan(span, err) }()
+
+	alias, err = s.validateAlias("URLService.DeleteByAlias", alias)
+	if err != nil {
+		return err
+	}
+
+	err = s.url.DeleteByAlias(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrURLAliasNotFound) {
+			s.logger.Error("URLService.DeleteByAlias", zap.String("alias", alias), zap.String("error", err.Error()))
+			return ErrOriginalURLNotFound
+		}
+		s.logger.Error("URLService.DeleteByAlias - s.url.DeleteByAlias", zap.String("error", err.Error()))
+		return ErrInternalError
+	}
+
+	s.logger.Info("URLService.DeleteByAlias - alias was deleted successfully", zap.String("alias", alias))
+
+	return nil
+}
+
+func (s *URLService) UpdateOriginal(ctx context.Context, alias, original string) (err error) {
+	ctx, span := startSpan(ctx, "URLService.UpdateOriginal", attribute.String("alias", alias))
+	defer func() { endSpan(span, err) }()
+
+	alias, err = s.validateAlias("URLService.UpdateOriginal", alias)
+	if err != nil {
+		return err
+	}
+
+	original, input, err := s.validateOriginal(ctx, "URLService.UpdateOriginal", original)
+	if err != nil {
+		return err
+	}
+
+	err = s.url.UpdateOriginal(ctx, entity.URL{
+		Original: original,
+		Alias:    alias,
+		Input:    input,
+	})
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrURLAliasNotFound) {
+			s.logger.Error("URLService.UpdateOriginal", zap.String("alias", alias), zap.String("error", err.Error()))
+			return ErrOriginalURLNotFound
+		}
+		if errors.Is(err, storageerrors.ErrOriginalURLExists) {
+			s.logger.Error("URLService.UpdateOriginal", zap.String("original", original), zap.String("error", err.Error()))
+			return err
+		}
+		s.logger.Error("URLService.UpdateOriginal - s.url.UpdateOriginal", zap.String("error", err.Error()))
+		return ErrInternalError
+	}
+
+	s.logger.Info("URLService.UpdateOriginal - original url was updated successfully", zap.String("alias", alias))
+
+	return nil
+}
+
+// ListURLs returns a page of not expired urls and the cursor of the next page, which is empty after the last page.
+func (s *URLService) ListURLs(ctx context.Context, cursor string, limit int) (_ []entity.URL, _ string, err error) {
+	ctx, span := startSpan(ctx, "URLService.ListURLs")
+	defer func() { endSpan(span, err) }()
+
+	if limit < 1 {
+		s.logger.Error("URLService.ListURLs", zap.String("error", ErrInvalidPageSize.Error()))
+		return nil, "", ErrInvalidPageSize
+	}
+
+	urls, next, err := s.url.GetURLs(ctx, cursor, limit)
+	if err != nil {
+		s.logger.Error("URLService.ListURLs - s.url.GetURLs", zap.String("error", err.Error()))
+		return nil, "", ErrInternalError
+	}
+
+	return urls, next, nil
+}
+
+// validateOriginal returns the normalized original url allowed by the policy, or the original url
+// of an own alias if they are resolved,
+// and the trimmed input if normalization changed it, errors are logged with method name.
+func (s *URLService) validateOriginal(ctx context.Context, method, original string) (string, string, error) {
+	input := strings.TrimSpace(original)
+	if input == "" {
+		s.logger.Error(method, zap.String("error", ErrEmptyOriginalURL.Error()))
+		return "", "", ErrEmptyOriginalURL
+	}
+
+	if utf8.RuneCountInString(input) > 2048 {
+		s.logger.Error(method, zap.String("error", ErrOriginalURLTooLong.Error()))
+		return "", "", ErrOriginalURLTooLong
+	}
+
+	normalized, err := s.normalizer.Normalize(input)
+	var parsed *url.URL
+	if err == nil {
+		parsed, err = url.ParseRequestURI(normalized)
+	}
+	if err != nil {
+		s.logger.Error(method, zap.String("original", input), zap.String("error", err.Error()))
+		return "", "", ErrInvalidOriginalURL
+	}
+
+	err = s.checkOriginal(ctx, parsed)
+	if errors.Is(err, ErrSelfLink) && s.cfg.Shorteners.ResolveOwnAliases {
+		normalized, err = s.resolveOwnAlias(ctx, parsed)
+	}
+	if err != nil {
+		s.logger.Error(method, zap.String("original", input), zap.String("error", err.Error()))
+		var rejected *RejectedURLError
+		if !errors.Is(err, ErrInvalidOriginalURL) && !errors.As(err, &rejected) {
+			return "", "", ErrInternalError
+		}
+		return "", "", err
+	}
+
+	// punycode and escaping make urls longer
+	if utf8.RuneCountInString(normalized) > 2048 {
+		s.logger.Error(method, zap.String("error", ErrOriginalURLTooLong.Error()))
+		return "", "", ErrOriginalURLTooLong
+	}
+
+	if input == normalized {
+		input = ""
+	}
+
+	return normalized, input, nil
+}
+
+// checkOriginal checks the parsed normalized original url against shorteners, the policy and domain rules.
+func (s *URLService) checkOriginal(ctx context.Context, u *url.URL) error {
+	if u.Host != "" {
+		if err := s.shorteners.check(u.Hostname()); err != nil {
+			return err
+		}
+	}
+	if err := s.policy.check(ctx, u); err != nil {
+		return err
+	}
+	if u.Host != "" {
+		return s.checkDomain(ctx, u.Hostname())
+	}
+	return nil
+}
+
+// checkDomain maps errors of domain rules to rejected url errors.
+func (s *URLService) checkDomain(ctx context.Context, host string) error {
+	err := s.domains.CheckHost(ctx, host)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, domainservice.ErrDomainBlocked):
+		return ErrDomainBlocked
+	case errors.Is(err, domainservice.ErrDomainNotAllowed):
+		return ErrDomainNotAllowed
+	default:
+		return err
+	}
+}
+
+// validateAlias returns trimmed alias of either generated or custom format,
+// errors are logged with method name.
+func (s *URLService) validateAlias(method, alias string) (string, error) {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		s.logger.Error(method, zap.String("error", ErrEmptyURLAlias.Error()))
+		return "", ErrEmptyURLAlias
+	}
+
+	// aliases created with earlier length settings stay reachable
+	if length := utf8.RuneCountInString(alias); length < generator.MinLength || length > constant.MaxStoredAliasLength {
+		s.logger.Error(method, zap.String("error", ErrInvalidAliasFormat.Error()))
+		return "", ErrInvalidAliasFormat
+	}
+
+	// aliases generated with an earlier alphabet stay reachable
+	if !generator.AlphabetURLSafe.Contains(alias) {
+		s.logger.Error(method, zap.String("error", ErrInvalidAliasSymbols.Error()))
+		return "", ErrInvalidAliasSymbols
+	}
+
+	return alias, nil
+}
@@ -2,17 +2,45 @@ package urlservice
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redismock/v9"
+	"github.com/romandnk/shortener/internal/constant"
 	"github.com/romandnk/shortener/internal/entity"
+	domainservice "github.com/romandnk/shortener/internal/service/domain"
 	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
 	mock_storage "github.com/romandnk/shortener/internal/storage/mock"
+	redisstorage "github.com/romandnk/shortener/internal/storage/redis"
+	generatorpkg "github.com/romandnk/shortener/pkg/generator"
 	mock_generate "github.com/romandnk/shortener/pkg/generator/mock"
 	mock_logger "github.com/romandnk/shortener/pkg/logger/mock"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/romandnk/shortener/pkg/urlnorm"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"strings"
 	"testing"
+	"time"
 )
 
+var testExpiresAt = time.Now().Add(time.Hour)
+
+// domainCheckerFunc checks hosts with the function.
+type domainCheckerFunc func(ctx context.Context, host string) error
+
+func (f domainCheckerFunc) CheckHost(ctx context.Context, host string) error {
+	return f(ctx, host)
+}
+
+var allowAllDomains = domainCheckerFunc(func(context.Context, string) error { return nil })
+
+var customAliasConfig = Config{
+	CustomAliasMinLength: 4,
+	CustomAliasMaxLength: 32,
+	ReservedAliases:      []string{"api", "swagger", "services"},
+}
+
 func TestURLService_CreateURLAlias(t *testing.T) {
 	type loggerArgs struct {
 		msg  string
@@ -20,9 +48,11 @@ func TestURLService_CreateURLAlias(t *testing.T) {
 	}
 
 	type urlArgs struct {
-		ctx   context.Context
-		url   entity.URL
-		error error
+		ctx           context.Context
+		url           entity.URL
+		error         error
+		existingAlias string
+		existingError error
 	}
 
 	type generatorArgs struct {
@@ -36,7 +66,11 @@ func TestURLService_CreateURLAlias(t *testing.T) {
 
 	testCases := []struct {
 		name               string
+		cfg                Config
 		inputOriginal      string
+		inputAlias         string
+		inputExpiresAt     time.Time
+		domains            DomainChecker
 		loggerArgs         loggerArgs
 		loggerMock         loggerBehaviour
 		urlArgs            urlArgs
@@ -44,6 +78,7 @@ func TestURLService_CreateURLAlias(t *testing.T) {
 		generatorBehaviour generatorBehaviour
 		urlMock            repoBehaviour
 		expectedAlias      string
+		expectedExisting   bool
 		expectedError      error
 	}{
 		{
@@ -67,13 +102,237 @@ func TestURLService_CreateURLAlias(t *testing.T) {
 				expectedRandomString: "abcdefghig",
 			},
 			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
-				m.EXPECT().Random().Return(args.expectedRandomString, args.error)
+				m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return(args.expectedRandomString, args.error)
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
+			},
+			expectedAlias: "abcdefghig",
+		},
+		{
+			name:           "OK with expiration",
+			inputOriginal:  "http://google.com/",
+			inputExpiresAt: testExpiresAt,
+			loggerArgs: loggerArgs{
+				msg:  "URLService.CreateURLAlias - alias was created successfully",
+				args: []any{zap.String("alias", "abcdefghig")},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Info(args.msg, args.args)
+			},
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+				url: entity.URL{
+					Original:  "http://google.com/",
+					Alias:     "abcdefghig",
+					ExpiresAt: testExpiresAt,
+				},
+			},
+			generatorArgs: generatorArgs{
+				expectedRandomString: "abcdefghig",
+			},
+			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
+				m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return(args.expectedRandomString, args.error)
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
+			},
+			expectedAlias: "abcdefghig",
+		},
+		{
+			name:          "OK normalized",
+			cfg:           Config{Normalization: urlnorm.Config{StripParams: []string{"utm_*"}}},
+			inputOriginal: "HTTPS://Google.COM:443/a/./b?q=go&utm_source=mail",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.CreateURLAlias - alias was created successfully",
+				args: []any{zap.String("alias", "abcdefghig")},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Info(args.msg, args.args)
+			},
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+				url: entity.URL{
+					Original: "https://google.com/a/b?q=go",
+					Alias:    "abcdefghig",
+					Input:    "HTTPS://Google.COM:443/a/./b?q=go&utm_source=mail",
+				},
+			},
+			generatorArgs: generatorArgs{
+				expectedRandomString: "abcdefghig",
+			},
+			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
+				m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return(args.expectedRandomString, args.error)
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
+			},
+			expectedAlias: "abcdefghig",
+		},
+		{
+			name:          "invalid original url",
+			inputOriginal: "http://exa mple.com/",
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error("URLService.CreateURLAlias", gomock.Any())
+			},
+			expectedError: ErrInvalidOriginalURL,
+		},
+		{
+			name:          "original url host is private",
+			inputOriginal: "http://169.254.169.254/latest/meta-data/",
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error("URLService.CreateURLAlias", []any{
+					zap.String("original", "http://169.254.169.254/latest/meta-data/"),
+					zap.String("error", ErrPrivateHost.Error()),
+				})
+			},
+			expectedError: ErrPrivateHost,
+		},
+		{
+			name:          "original url domain is blocked",
+			inputOriginal: "https://www.Evil.com/login",
+			domains: domainCheckerFunc(func(_ context.Context, host string) error {
+				if host == "www.evil.com" {
+					return domainservice.ErrDomainBlocked
+				}
+				return nil
+			}),
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error("URLService.CreateURLAlias", []any{
+					zap.String("original", "https://www.Evil.com/login"),
+					zap.String("error", ErrDomainBlocked.Error()),
+				})
+			},
+			expectedError: ErrDomainBlocked,
+		},
+		{
+			name:          "original url points to another shortener",
+			cfg:           Config{Shorteners: ShortenerConfig{Hosts: []string{"bit.ly"}}},
+			inputOriginal: "https://www.bit.ly/abc",
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error("URLService.CreateURLAlias", []any{
+					zap.String("original", "https://www.bit.ly/abc"),
+					zap.String("error", ErrShortenerURL.Error()),
+				})
+			},
+			expectedError: ErrShortenerURL,
+		},
+		{
+			name:          "original url points to this shortener",
+			cfg:           Config{Shorteners: ShortenerConfig{PublicHosts: []string{"sho.rt:443"}}},
+			inputOriginal: "https://Sho.rt/abcdefghig",
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error("URLService.CreateURLAlias", []any{
+					zap.String("original", "https://Sho.rt/abcdefghig"),
+					zap.String("error", ErrSelfLink.Error()),
+				})
+			},
+			expectedError: ErrSelfLink,
+		},
+		{
+			name: "OK own alias is resolved",
+			cfg: Config{Shorteners: ShortenerConfig{
+				PublicHosts:       []string{"sho.rt"},
+				ResolveOwnAliases: true,
+			}},
+			inputOriginal: "https://sho.rt/existing12",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.CreateURLAlias - alias was created successfully",
+				args: []any{zap.String("alias", "abcdefghig")},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Info(args.msg, args.args)
+			},
+			generatorArgs: generatorArgs{
+				expectedRandomString: "abcdefghig",
+			},
+			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
+				m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return(args.expectedRandomString, args.error)
+			},
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+				url: entity.URL{
+					Original: "https://google.com/",
+					Alias:    "abcdefghig",
+					Input:    "https://sho.rt/existing12",
+				},
 			},
 			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
-				m.EXPECT().CreateURL(args.ctx, args.url).Return(args.error)
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), "existing12").Return("https://google.com/", nil)
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
 			},
 			expectedAlias: "abcdefghig",
 		},
+		{
+			name: "own alias is not found",
+			cfg: Config{Shorteners: ShortenerConfig{
+				PublicHosts:       []string{"sho.rt"},
+				ResolveOwnAliases: true,
+			}},
+			inputOriginal: "https://sho.rt/unknown123",
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error("URLService.CreateURLAlias", []any{
+					zap.String("original", "https://sho.rt/unknown123"),
+					zap.String("error", ErrSelfLink.Error()),
+				})
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), "unknown123").Return("", storageerrors.ErrURLAliasNotFound)
+			},
+			expectedError: ErrSelfLink,
+		},
+		{
+			name: "own alias points to a blocked domain",
+			cfg: Config{Shorteners: ShortenerConfig{
+				PublicHosts:       []string{"sho.rt"},
+				ResolveOwnAliases: true,
+			}},
+			inputOriginal: "https://sho.rt/existing12",
+			domains: domainCheckerFunc(func(_ context.Context, host string) error {
+				if host == "evil.com" {
+					return domainservice.ErrDomainBlocked
+				}
+				return nil
+			}),
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error("URLService.CreateURLAlias", []any{
+					zap.String("original", "https://sho.rt/existing12"),
+					zap.String("error", ErrDomainBlocked.Error()),
+				})
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), "existing12").Return("https://evil.com/", nil)
+			},
+			expectedError: ErrDomainBlocked,
+		},
+		{
+			name: "own page is not resolved",
+			cfg: Config{Shorteners: ShortenerConfig{
+				PublicHosts:       []string{"sho.rt"},
+				ResolveOwnAliases: true,
+			}},
+			inputOriginal: "https://sho.rt/api/v1/urls",
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error("URLService.CreateURLAlias", []any{
+					zap.String("original", "https://sho.rt/api/v1/urls"),
+					zap.String("error", ErrSelfLink.Error()),
+				})
+			},
+			expectedError: ErrSelfLink,
+		},
+		{
+			name:           "expiration time in the past",
+			inputOriginal:  "http://google.com/",
+			inputExpiresAt: time.Now().Add(-time.Minute),
+			loggerArgs: loggerArgs{
+				msg:  "URLService.CreateURLAlias",
+				args: []any{zap.String("error", ErrExpirationInPast.Error())},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			expectedError: ErrExpirationInPast,
+		},
 		{
 			name: "empty original url",
 			loggerArgs: loggerArgs{
@@ -116,7 +375,7 @@ func TestURLService_CreateURLAlias(t *testing.T) {
 				expectedRandomString: "abcdefghig",
 			},
 			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
-				m.EXPECT().Random().Return(args.expectedRandomString, args.error)
+				m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return(args.expectedRandomString, args.error)
 			},
 			urlArgs: urlArgs{
 				ctx: context.Background(),
@@ -127,145 +386,796 @@ func TestURLService_CreateURLAlias(t *testing.T) {
 				error: storageerrors.ErrOriginalURLExists,
 			},
 			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
-				m.EXPECT().CreateURL(args.ctx, args.url).Return(args.error)
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
 			},
 			expectedAlias: "",
 			expectedError: storageerrors.ErrOriginalURLExists,
 		},
-	}
-
-	for _, tc := range testCases {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			ctx := context.Background()
-
-			urlStorage := mock_storage.NewMockURL(ctrl)
-			generator := mock_generate.NewMockGenerator(ctrl)
-			log := mock_logger.NewMockLogger(ctrl)
-
-			urlService := URLService{
-				generator: generator,
-				url:       urlStorage,
-				logger:    log,
-			}
-
-			if tc.loggerMock != nil {
-				tc.loggerMock(log, tc.loggerArgs)
-			}
-			if tc.generatorBehaviour != nil {
-				tc.generatorBehaviour(generator, tc.generatorArgs)
-			}
-			if tc.urlMock != nil {
-				tc.urlMock(urlStorage, tc.urlArgs)
-			}
-
-			output, err := urlService.CreateURLAlias(ctx, tc.inputOriginal)
-			require.ErrorIs(t, err, tc.expectedError)
-			require.Equal(t, tc.expectedAlias, output)
-		})
-	}
-}
-
-func TestURLService_GetOriginalByAlias(t *testing.T) {
-	type loggerArgs struct {
-		msg  string
-		args []any
-	}
-
-	type urlArgs struct {
-		ctx      context.Context
-		alias    string
-		original string
-		error    error
-	}
-
-	type loggerBehaviour func(m *mock_logger.MockLogger, args loggerArgs)
-	type repoBehaviour func(m *mock_storage.MockURL, args urlArgs)
-
-	testCases := []struct {
-		name             string
-		inputAlias       string
-		loggerArgs       loggerArgs
-		loggerMock       loggerBehaviour
-		urlArgs          urlArgs
-		urlMock          repoBehaviour
-		expectedOriginal string
-		expectedError    error
-	}{
 		{
-			name:       "OK",
-			inputAlias: "abcdefghig",
+			name:          "OK custom alias",
+			cfg:           customAliasConfig,
+			inputOriginal: "http://google.com/",
+			inputAlias:    "google",
 			loggerArgs: loggerArgs{
-				msg:  "URLService.GetOriginalByAlias - alias was received successfully",
-				args: []any{zap.String("alias", "abcdefghig")},
+				msg:  "URLService.CreateURLAlias - alias was created successfully",
+				args: []any{zap.String("alias", "google")},
 			},
 			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
 				m.EXPECT().Info(args.msg, args.args)
 			},
 			urlArgs: urlArgs{
-				ctx:      context.Background(),
-				alias:    "abcdefghig",
-				original: "http://google.com/",
+				ctx: context.Background(),
+				url: entity.URL{
+					Original: "http://google.com/",
+					Alias:    "google",
+				},
 			},
 			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
-				m.EXPECT().GetOriginalByAlias(args.ctx, args.alias).Return(args.original, args.error)
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
 			},
-			expectedOriginal: "http://google.com/",
+			expectedAlias: "google",
 		},
 		{
-			name: "empty alias",
+			name:          "custom alias already exists",
+			cfg:           customAliasConfig,
+			inputOriginal: "http://google.com/",
+			inputAlias:    "google",
 			loggerArgs: loggerArgs{
-				msg:  "URLService.GetOriginalByAlias",
-				args: []any{zap.String("error", ErrEmptyURLAlias.Error())},
+				msg: "URLService.CreateURLAlias",
+				args: []any{
+					zap.String("alias", "google"),
+					zap.String("error", storageerrors.ErrURLAliasExists.Error()),
+				},
 			},
 			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
 				m.EXPECT().Error(args.msg, args.args)
 			},
-			expectedOriginal: "",
-			expectedError:    ErrEmptyURLAlias,
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+				url: entity.URL{
+					Original: "http://google.com/",
+					Alias:    "google",
+				},
+				error: storageerrors.ErrURLAliasExists,
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
+			},
+			expectedError: storageerrors.ErrURLAliasExists,
 		},
 		{
-			name:       "original url is not found",
-			inputAlias: "abcdefghig",
+			name:          "custom alias too short",
+			cfg:           customAliasConfig,
+			inputOriginal: "http://google.com/",
+			inputAlias:    "go",
 			loggerArgs: loggerArgs{
-				msg: "URLService.GetOriginalByAlias",
-				args: []any{
-					zap.String("alias", "abcdefghig"),
-					zap.String("error", storageerrors.ErrURLAliasNotFound.Error()),
-				},
+				msg:  "URLService.CreateURLAlias",
+				args: []any{zap.String("alias", "go"), zap.String("error", ErrInvalidAliasFormat.Error())},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			expectedError: ErrInvalidAliasFormat,
+		},
+		{
+			name:          "custom alias contains invalid symbols",
+			cfg:           customAliasConfig,
+			inputOriginal: "http://google.com/",
+			inputAlias:    "goo/gle",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.CreateURLAlias",
+				args: []any{zap.String("alias", "goo/gle"), zap.String("error", ErrInvalidAliasSymbols.Error())},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			expectedError: ErrInvalidAliasSymbols,
+		},
+		{
+			name:          "custom alias is reserved",
+			cfg:           customAliasConfig,
+			inputOriginal: "http://google.com/",
+			inputAlias:    "Swagger",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.CreateURLAlias",
+				args: []any{zap.String("alias", "Swagger"), zap.String("error", ErrReservedAlias.Error())},
 			},
 			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
 				m.EXPECT().Error(args.msg, args.args)
 			},
+			expectedError: ErrReservedAlias,
+		},
+		{
+			name:          "OK after alias collision",
+			cfg:           Config{MaxAttempts: 3},
+			inputOriginal: "http://google.com/",
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Info("URLService.CreateURLAlias - alias collision, retrying",
+					[]any{zap.String("alias", "abcdefghig"), zap.Int("attempt", 1)})
+				m.EXPECT().Info("URLService.CreateURLAlias - alias was created successfully",
+					[]any{zap.String("alias", "abcdefghi2")})
+			},
+			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
+				gomock.InOrder(
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("abcdefghig", nil),
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("abcdefghi2", nil),
+				)
+			},
 			urlArgs: urlArgs{
-				ctx:   context.Background(),
-				alias: "abcdefghig",
-				error: storageerrors.ErrURLAliasNotFound,
+				ctx: context.Background(),
 			},
 			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
-				m.EXPECT().GetOriginalByAlias(args.ctx, args.alias).Return(args.original, args.error)
+				gomock.InOrder(
+					m.EXPECT().CreateURL(gomock.Any(), entity.URL{Original: "http://google.com/", Alias: "abcdefghig"}).
+						Return(storageerrors.ErrURLAliasExists),
+					m.EXPECT().CreateURL(gomock.Any(), entity.URL{Original: "http://google.com/", Alias: "abcdefghi2"}).
+						Return(nil),
+				)
 			},
-			expectedError: ErrOriginalURLNotFound,
+			expectedAlias: "abcdefghi2",
 		},
-	}
-
-	for _, tc := range testCases {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
+		{
+			name:          "alias collision attempts are exhausted",
+			cfg:           Config{MaxAttempts: 1},
+			inputOriginal: "http://google.com/",
+			loggerArgs: loggerArgs{
+				msg: "URLService.CreateURLAlias",
+				args: []any{
+					zap.String("alias", "abcdefghig"),
+					zap.String("error", storageerrors.ErrURLAliasExists.Error()),
+				},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			generatorArgs: generatorArgs{
+				expectedRandomString: "abcdefghig",
+			},
+			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
+				m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return(args.expectedRandomString, args.error)
+			},
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+				url: entity.URL{
+					Original: "http://google.com/",
+					Alias:    "abcdefghig",
+				},
+				error: storageerrors.ErrURLAliasExists,
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
+			},
+			expectedError: storageerrors.ErrURLAliasExists,
+		},
+		{
+			name:          "idempotent original url already exists",
+			cfg:           Config{Idempotent: true},
+			inputOriginal: "http://google.com/",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.CreateURLAlias - existing alias was returned",
+				args: []any{zap.String("alias", "existing12")},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Info(args.msg, args.args)
+			},
+			generatorArgs: generatorArgs{
+				expectedRandomString: "abcdefghig",
+			},
+			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
+				m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return(args.expectedRandomString, args.error)
+			},
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+				url: entity.URL{
+					Original: "http://google.com/",
+					Alias:    "abcdefghig",
+				},
+				error:         storageerrors.ErrOriginalURLExists,
+				existingAlias: "existing12",
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
+				m.EXPECT().GetAliasByOriginal(gomock.Any(), args.url.Original).Return(args.existingAlias, args.existingError)
+			},
+			expectedAlias:    "existing12",
+			expectedExisting: true,
+		},
+		{
+			name:          "idempotent custom alias differs from existing",
+			cfg:           Config{Idempotent: true, CustomAliasMinLength: 4, CustomAliasMaxLength: 32},
+			inputOriginal: "http://google.com/",
+			inputAlias:    "google",
+			loggerArgs: loggerArgs{
+				msg: "URLService.CreateURLAlias",
+				args: []any{
+					zap.String("original", "http://google.com/"),
+					zap.String("error", storageerrors.ErrOriginalURLExists.Error()),
+				},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+				url: entity.URL{
+					Original: "http://google.com/",
+					Alias:    "google",
+				},
+				error:         storageerrors.ErrOriginalURLExists,
+				existingAlias: "existing12",
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
+				m.EXPECT().GetAliasByOriginal(gomock.Any(), args.url.Original).Return(args.existingAlias, args.existingError)
+			},
+			expectedError: storageerrors.ErrOriginalURLExists,
+		},
+		{
+			name:          "idempotent existing alias lookup failed",
+			cfg:           Config{Idempotent: true, MaxAttempts: 3},
+			inputOriginal: "http://google.com/",
+			loggerArgs: loggerArgs{
+				msg: "URLService.CreateURLAlias - s.url.GetAliasByOriginal",
+				args: []any{
+					zap.String("original", "http://google.com/"),
+					zap.String("error", "connection refused"),
+				},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			generatorArgs: generatorArgs{
+				expectedRandomString: "abcdefghig",
+			},
+			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
+				m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return(args.expectedRandomString, args.error)
+			},
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+				url: entity.URL{
+					Original: "http://google.com/",
+					Alias:    "abcdefghig",
+				},
+				error:         storageerrors.ErrOriginalURLExists,
+				existingError: errors.New("connection refused"),
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
+				m.EXPECT().GetAliasByOriginal(gomock.Any(), args.url.Original).Return(args.existingAlias, args.existingError)
+			},
+			expectedError: ErrInternalError,
+		},
+		{
+			name:          "idempotent existing url disappeared",
+			cfg:           Config{Idempotent: true, MaxAttempts: 3},
+			inputOriginal: "http://google.com/",
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Info("URLService.CreateURLAlias - existing url disappeared, retrying",
+					[]any{zap.String("original", "http://google.com/"), zap.Int("attempt", 1)})
+				m.EXPECT().Info("URLService.CreateURLAlias - alias was created successfully",
+					[]any{zap.String("alias", "abcdefghi2")})
+			},
+			generatorBehaviour: func(m *mock_generate.MockGenerator, args generatorArgs) {
+				gomock.InOrder(
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("abcdefghig", nil),
+					m.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("abcdefghi2", nil),
+				)
+			},
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				gomock.InOrder(
+					m.EXPECT().CreateURL(gomock.Any(), entity.URL{Original: "http://google.com/", Alias: "abcdefghig"}).
+						Return(storageerrors.ErrOriginalURLExists),
+					m.EXPECT().GetAliasByOriginal(gomock.Any(), "http://google.com/").
+						Return("", storageerrors.ErrOriginalNotFound),
+					m.EXPECT().CreateURL(gomock.Any(), entity.URL{Original: "http://google.com/", Alias: "abcdefghi2"}).
+						Return(nil),
+				)
+			},
+			expectedAlias: "abcdefghi2",
+		},
+		{
+			name:          "idempotent existing url disappeared on last attempt",
+			cfg:           Config{Idempotent: true, MaxAttempts: 1, CustomAliasMinLength: 4, CustomAliasMaxLength: 32},
+			inputOriginal: "http://google.com/",
+			inputAlias:    "google",
+			loggerArgs: loggerArgs{
+				msg: "URLService.CreateURLAlias - s.url.GetAliasByOriginal",
+				args: []any{
+					zap.String("original", "http://google.com/"),
+					zap.String("error", storageerrors.ErrOriginalNotFound.Error()),
+				},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			urlArgs: urlArgs{
+				ctx: context.Background(),
+				url: entity.URL{
+					Original: "http://google.com/",
+					Alias:    "google",
+				},
+				error:         storageerrors.ErrOriginalURLExists,
+				existingError: storageerrors.ErrOriginalNotFound,
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().CreateURL(gomock.Any(), args.url).Return(args.error)
+				m.EXPECT().GetAliasByOriginal(gomock.Any(), args.url.Original).Return(args.existingAlias, args.existingError)
+			},
+			expectedError: ErrInternalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+
+			urlStorage := mock_storage.NewMockURL(ctrl)
+			generator := mock_generate.NewMockGenerator(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			domains := tc.domains
+			if domains == nil {
+				domains = allowAllDomains
+			}
+
+			urlService := NewURLService(tc.cfg, generator, urlStorage, domains, log)
+
+			if tc.loggerMock != nil {
+				tc.loggerMock(log, tc.loggerArgs)
+			}
+			if tc.generatorBehaviour != nil {
+				tc.generatorBehaviour(generator, tc.generatorArgs)
+			}
+			if tc.urlMock != nil {
+				tc.urlMock(urlStorage, tc.urlArgs)
+			}
+
+			output, existing, err := urlService.CreateURLAlias(ctx, entity.URL{
+				Original:  tc.inputOriginal,
+				Alias:     tc.inputAlias,
+				ExpiresAt: tc.inputExpiresAt,
+			})
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedAlias, output)
+			require.Equal(t, tc.expectedExisting, existing)
+		})
+	}
+}
+
+func TestURLService_AliasLengthGrowth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	urlStorage := mock_storage.NewMockURL(ctrl)
+	generator := mock_generate.NewMockGenerator(ctrl)
+	log := mock_logger.NewMockLogger(ctrl)
+
+	cfg := Config{
+		MaxAttempts:     1,
+		CollisionRate:   0.5,
+		CollisionWindow: 2,
+		MaxAliasLength:  constant.AliasLength + 1,
+	}
+	urlService := NewURLService(cfg, generator, urlStorage, allowAllDomains, log)
+
+	log.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	// two collisions in a window of two generations grow alias length once
+	generator.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("abcdefghig", nil).Times(2)
+	urlStorage.EXPECT().CreateURL(gomock.Any(), gomock.Any()).Return(storageerrors.ErrURLAliasExists).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, _, err := urlService.CreateURLAlias(ctx, entity.URL{Original: "http://google.com/"})
+		require.ErrorIs(t, err, storageerrors.ErrURLAliasExists)
+	}
+
+	require.Equal(t, Stats{Generated: 2, Collisions: 2, AliasLength: constant.AliasLength + 1}, urlService.Stats())
+
+	// alias length never exceeds the configured maximum
+	generator.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength+1).Return("abcdefghig1", nil).Times(2)
+	urlStorage.EXPECT().CreateURL(gomock.Any(), gomock.Any()).Return(storageerrors.ErrURLAliasExists).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, _, err := urlService.CreateURLAlias(ctx, entity.URL{Original: "http://google.com/"})
+		require.ErrorIs(t, err, storageerrors.ErrURLAliasExists)
+	}
+
+	require.Equal(t, Stats{Generated: 4, Collisions: 4, AliasLength: constant.AliasLength + 1}, urlService.Stats())
+}
+
+// TestURLService_CreateURLAliasIdempotentAfterCollision checks against the redis repo that
+// an alias collision leaves the original free, so that re-creating it returns the alias it got.
+func TestURLService_CreateURLAliasIdempotentAfterCollision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	generator := mock_generate.NewMockGenerator(ctrl)
+	log := mock_logger.NewMockLogger(ctrl)
+
+	urlStorage := redisstorage.NewURLRepo(&redisdb.Redis{Client: db})
+	urlService := NewURLService(Config{Idempotent: true, MaxAttempts: 2}, generator, urlStorage, allowAllDomains, log)
+
+	log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	gomock.InOrder(
+		generator.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("abcdefghig", nil),
+		generator.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("bcdefghigj", nil),
+		generator.EXPECT().Generate(gomock.Any(), gomock.Any(), constant.AliasLength).Return("cdefghigjk", nil),
+	)
+	expectCreate := func(alias string, res int64) {
+		mock.CustomMatch(ignoreScriptHash).
+			ExpectEvalSha("", []string{"http://google.com/", alias, "expiry:" + alias, "input:" + alias, "clicks:" + alias}, "0", "0", "", "").
+			SetVal(res)
+	}
+	expectCreate("abcdefghig", 2)
+	expectCreate("bcdefghigj", 0)
+	expectCreate("cdefghigjk", 1)
+	mock.ExpectGet("http://google.com/").SetVal("bcdefghigj")
+
+	alias, existing, err := urlService.CreateURLAlias(ctx, entity.URL{Original: "http://google.com/"})
+	require.NoError(t, err)
+	require.False(t, existing)
+	require.Equal(t, "bcdefghigj", alias)
+
+	alias, existing, err = urlService.CreateURLAlias(ctx, entity.URL{Original: "http://google.com/"})
+	require.NoError(t, err)
+	require.True(t, existing)
+	require.Equal(t, "bcdefghigj", alias)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+// ignoreScriptHash matches redis commands except for the script hash, which belongs to the repo.
+func ignoreScriptHash(expected, actual []any) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %v, actual %v", expected, actual)
+	}
+	for i := range expected {
+		if i != 1 && expected[i] != actual[i] {
+			return fmt.Errorf("expected %v, actual %v", expected, actual)
+		}
+	}
+	return nil
+}
+
+func TestURLService_CreateURLAliasGeneratorInput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	urlStorage := mock_storage.NewMockURL(ctrl)
+	generator := mock_generate.NewMockGenerator(ctrl)
+	log := mock_logger.NewMockLogger(ctrl)
+
+	urlService := NewURLService(Config{MaxAttempts: 2}, generator, urlStorage, allowAllDomains, log)
+
+	// generators deriving aliases from urls get the normalized url and the attempt
+	gomock.InOrder(
+		generator.EXPECT().Generate("https://google.com/", 1, constant.AliasLength).Return("abcdefghig", nil),
+		urlStorage.EXPECT().CreateURL(gomock.Any(), entity.URL{
+			Original: "https://google.com/",
+			Alias:    "abcdefghig",
+			Input:    "https://Google.com",
+		}).Return(storageerrors.ErrURLAliasExists),
+		log.EXPECT().Info("URLService.CreateURLAlias - alias collision, retrying", gomock.Any()),
+		generator.EXPECT().Generate("https://google.com/", 2, constant.AliasLength).Return("bcdefghigj", nil),
+		urlStorage.EXPECT().CreateURL(gomock.Any(), entity.URL{
+			Original: "https://google.com/",
+			Alias:    "bcdefghigj",
+			Input:    "https://Google.com",
+		}).Return(nil),
+		log.EXPECT().Info("URLService.CreateURLAlias - alias was created successfully", gomock.Any()),
+	)
+
+	alias, existing, err := urlService.CreateURLAlias(ctx, entity.URL{Original: "https://Google.com"})
+	require.NoError(t, err)
+	require.False(t, existing)
+	require.Equal(t, "bcdefghigj", alias)
+}
+
+func TestURLService_AliasConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	urlStorage := mock_storage.NewMockURL(ctrl)
+	generator := mock_generate.NewMockGenerator(ctrl)
+	log := mock_logger.NewMockLogger(ctrl)
+	log.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	cfg := Config{
+		MaxAttempts:          1,
+		CustomAliasMinLength: 4,
+		CustomAliasMaxLength: 32,
+		Alphabet:             generatorpkg.AlphabetHuman,
+		AliasLength:          6,
+	}
+	urlService := NewURLService(cfg, generator, urlStorage, allowAllDomains, log)
+
+	// aliases are generated with the configured length
+	generator.EXPECT().Generate(gomock.Any(), gomock.Any(), 6).Return("abcdef", nil)
+	urlStorage.EXPECT().CreateURL(gomock.Any(), gomock.Any()).Return(nil)
+	alias, _, err := urlService.CreateURLAlias(ctx, entity.URL{Original: "http://google.com/"})
+	require.NoError(t, err)
+	require.Equal(t, "abcdef", alias)
+	require.Equal(t, 6, urlService.Stats().AliasLength)
+
+	// custom aliases follow the configured alphabet
+	_, _, err = urlService.CreateURLAlias(ctx, entity.URL{Original: "http://google.com/", Alias: "google"})
+	require.ErrorIs(t, err, ErrInvalidAliasSymbols)
+
+	// aliases of earlier alphabets stay reachable
+	urlStorage.EXPECT().GetOriginalByAlias(gomock.Any(), "l0_I1-").Return("http://google.com/", nil)
+	original, err := urlService.GetOriginalByAlias(ctx, "l0_I1-")
+	require.NoError(t, err)
+	require.Equal(t, "http://google.com/", original)
+
+	// aliases of earlier lengths stay reachable after the length is raised and custom aliases are disabled
+	cfg.AliasLength, cfg.CustomAliasMaxLength = 12, 0
+	urlService = NewURLService(cfg, generator, urlStorage, allowAllDomains, log)
+
+	urlStorage.EXPECT().GetOriginalByAlias(gomock.Any(), "abcdef").Return("http://google.com/", nil)
+	original, err = urlService.GetOriginalByAlias(ctx, "abcdef")
+	require.NoError(t, err)
+	require.Equal(t, "http://google.com/", original)
+
+	_, err = urlService.GetOriginalByAlias(ctx, "abc")
+	require.ErrorIs(t, err, ErrInvalidAliasFormat)
+
+	_, _, err = urlService.CreateURLAlias(ctx, entity.URL{Original: "http://google.com/", Alias: "abcdef"})
+	require.ErrorIs(t, err, ErrCustomAliasDisabled)
+}
+
+func TestURLService_GetOriginalByAlias(t *testing.T) {
+	type loggerArgs struct {
+		msg  string
+		args []any
+	}
+
+	type urlArgs struct {
+		ctx      context.Context
+		alias    string
+		original string
+		error    error
+	}
+
+	type loggerBehaviour func(m *mock_logger.MockLogger, args loggerArgs)
+	type repoBehaviour func(m *mock_storage.MockURL, args urlArgs)
+
+	testCases := []struct {
+		name             string
+		cfg              Config
+		inputAlias       string
+		domains          DomainChecker
+		loggerArgs       loggerArgs
+		loggerMock       loggerBehaviour
+		urlArgs          urlArgs
+		urlMock          repoBehaviour
+		expectedOriginal string
+		expectedError    error
+	}{
+		{
+			name:       "OK",
+			inputAlias: "abcdefghig",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.GetOriginalByAlias - alias was received successfully",
+				args: []any{zap.String("alias", "abcdefghig")},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Info(args.msg, args.args)
+			},
+			urlArgs: urlArgs{
+				ctx:      context.Background(),
+				alias:    "abcdefghig",
+				original: "http://google.com/",
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.alias).Return(args.original, args.error)
+			},
+			expectedOriginal: "http://google.com/",
+		},
+		{
+			name:       "OK custom alias",
+			cfg:        customAliasConfig,
+			inputAlias: "google",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.GetOriginalByAlias - alias was received successfully",
+				args: []any{zap.String("alias", "google")},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Info(args.msg, args.args)
+			},
+			urlArgs: urlArgs{
+				ctx:      context.Background(),
+				alias:    "google",
+				original: "http://google.com/",
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.alias).Return(args.original, args.error)
+			},
+			expectedOriginal: "http://google.com/",
+		},
+		{
+			name:       "alias too short",
+			inputAlias: "goo",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.GetOriginalByAlias",
+				args: []any{zap.String("error", ErrInvalidAliasFormat.Error())},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			expectedError: ErrInvalidAliasFormat,
+		},
+		{
+			name:       "alias too long",
+			inputAlias: strings.Repeat("a", 129),
+			loggerArgs: loggerArgs{
+				msg:  "URLService.GetOriginalByAlias",
+				args: []any{zap.String("error", ErrInvalidAliasFormat.Error())},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			expectedError: ErrInvalidAliasFormat,
+		},
+		{
+			name:       "alias contains invalid symbols",
+			inputAlias: "abcdefghi.",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.GetOriginalByAlias",
+				args: []any{zap.String("error", ErrInvalidAliasSymbols.Error())},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			expectedError: ErrInvalidAliasSymbols,
+		},
+		{
+			name: "empty alias",
+			loggerArgs: loggerArgs{
+				msg:  "URLService.GetOriginalByAlias",
+				args: []any{zap.String("error", ErrEmptyURLAlias.Error())},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			expectedOriginal: "",
+			expectedError:    ErrEmptyURLAlias,
+		},
+		{
+			name:       "original url is not found",
+			inputAlias: "abcdefghig",
+			loggerArgs: loggerArgs{
+				msg: "URLService.GetOriginalByAlias",
+				args: []any{
+					zap.String("alias", "abcdefghig"),
+					zap.String("error", storageerrors.ErrURLAliasNotFound.Error()),
+				},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			urlArgs: urlArgs{
+				ctx:   context.Background(),
+				alias: "abcdefghig",
+				error: storageerrors.ErrURLAliasNotFound,
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.alias).Return(args.original, args.error)
+			},
+			expectedError: ErrOriginalURLNotFound,
+		},
+		{
+			name:       "original url is expired",
+			inputAlias: "abcdefghig",
+			loggerArgs: loggerArgs{
+				msg: "URLService.GetOriginalByAlias",
+				args: []any{
+					zap.String("alias", "abcdefghig"),
+					zap.String("error", storageerrors.ErrURLExpired.Error()),
+				},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			urlArgs: urlArgs{
+				ctx:   context.Background(),
+				alias: "abcdefghig",
+				error: storageerrors.ErrURLExpired,
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.alias).Return(args.original, args.error)
+			},
+			expectedError: ErrOriginalURLExpired,
+		},
+		{
+			name:       "original url domain is blocked",
+			inputAlias: "abcdefghig",
+			domains: domainCheckerFunc(func(_ context.Context, host string) error {
+				require.Equal(t, "evil.com", host)
+				return domainservice.ErrDomainBlocked
+			}),
+			loggerArgs: loggerArgs{
+				msg: "URLService.GetOriginalByAlias",
+				args: []any{
+					zap.String("alias", "abcdefghig"),
+					zap.String("error", ErrDomainBlocked.Error()),
+				},
+			},
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error(args.msg, args.args)
+			},
+			urlArgs: urlArgs{
+				ctx:      context.Background(),
+				alias:    "abcdefghig",
+				original: "https://evil.com:8443/login",
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.alias).Return(args.original, args.error)
+			},
+			expectedError: ErrOriginalURLBlocked,
+		},
+		{
+			name:       "domain rules are not available",
+			inputAlias: "abcdefghig",
+			domains: domainCheckerFunc(func(context.Context, string) error {
+				return domainservice.ErrInternalError
+			}),
+			loggerMock: func(m *mock_logger.MockLogger, args loggerArgs) {
+				m.EXPECT().Error("URLService.GetOriginalByAlias", gomock.Any())
+			},
+			urlArgs: urlArgs{
+				ctx:      context.Background(),
+				alias:    "abcdefghig",
+				original: "https://google.com/",
+			},
+			urlMock: func(m *mock_storage.MockURL, args urlArgs) {
+				m.EXPECT().GetOriginalByAlias(gomock.Any(), args.alias).Return(args.original, args.error)
+			},
+			expectedError: ErrInternalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
 			ctx := context.Background()
 
 			urlStorage := mock_storage.NewMockURL(ctrl)
 			log := mock_logger.NewMockLogger(ctrl)
 
+			domains := tc.domains
+			if domains == nil {
+				domains = allowAllDomains
+			}
+
 			urlService := URLService{
-				url:    urlStorage,
-				logger: log,
+				cfg:     tc.cfg,
+				url:     urlStorage,
+				domains: domains,
+				logger:  log,
 			}
 
 			if tc.loggerMock != nil {
@@ -281,3 +1191,281 @@ func TestURLService_GetOriginalByAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestExpirationTime(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		expiresAt     time.Time
+		ttl           time.Duration
+		expected      time.Time
+		expectedError error
+	}{
+		{
+			name: "never expires",
+		},
+		{
+			name:      "expiration time",
+			expiresAt: expiresAt,
+			expected:  expiresAt,
+		},
+		{
+			name:          "both expiration time and ttl",
+			expiresAt:     expiresAt,
+			ttl:           time.Hour,
+			expectedError: ErrExpirationConflict,
+		},
+		{
+			name:          "negative ttl",
+			ttl:           -time.Hour,
+			expectedError: ErrInvalidTTL,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ExpirationTime(tc.expiresAt, tc.ttl)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("ttl", func(t *testing.T) {
+		actual, err := ExpirationTime(time.Time{}, time.Hour)
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(time.Hour), actual, time.Second)
+	})
+}
+
+func TestURLService_DeleteByAlias(t *testing.T) {
+	type mockBehaviour func(url *mock_storage.MockURL, log *mock_logger.MockLogger)
+
+	testCases := []struct {
+		name          string
+		inputAlias    string
+		mock          mockBehaviour
+		expectedError error
+	}{
+		{
+			name:       "OK",
+			inputAlias: "abcdefghig",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				url.EXPECT().DeleteByAlias(gomock.Any(), "abcdefghig").Return(nil)
+				log.EXPECT().Info("URLService.DeleteByAlias - alias was deleted successfully",
+					[]any{zap.String("alias", "abcdefghig")})
+			},
+		},
+		{
+			name:       "alias contains invalid symbols",
+			inputAlias: "abcdefghi.",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("URLService.DeleteByAlias", []any{zap.String("error", ErrInvalidAliasSymbols.Error())})
+			},
+			expectedError: ErrInvalidAliasSymbols,
+		},
+		{
+			name:       "alias is not found",
+			inputAlias: "abcdefghig",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				url.EXPECT().DeleteByAlias(gomock.Any(), "abcdefghig").Return(storageerrors.ErrURLAliasNotFound)
+				log.EXPECT().Error("URLService.DeleteByAlias", []any{
+					zap.String("alias", "abcdefghig"),
+					zap.String("error", storageerrors.ErrURLAliasNotFound.Error()),
+				})
+			},
+			expectedError: ErrOriginalURLNotFound,
+		},
+		{
+			name:       "storage error",
+			inputAlias: "abcdefghig",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				url.EXPECT().DeleteByAlias(gomock.Any(), "abcdefghig").Return(errors.New("connection refused"))
+				log.EXPECT().Error("URLService.DeleteByAlias - s.url.DeleteByAlias",
+					[]any{zap.String("error", "connection refused")})
+			},
+			expectedError: ErrInternalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			urlStorage := mock_storage.NewMockURL(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			tc.mock(urlStorage, log)
+
+			urlService := NewURLService(Config{}, nil, urlStorage, allowAllDomains, log)
+
+			err := urlService.DeleteByAlias(context.Background(), tc.inputAlias)
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestURLService_ListURLs(t *testing.T) {
+	urls := []entity.URL{{Original: "https://google.com", Alias: "abcdefghig"}}
+
+	type mockBehaviour func(url *mock_storage.MockURL, log *mock_logger.MockLogger)
+
+	testCases := []struct {
+		name          string
+		inputCursor   string
+		inputLimit    int
+		mock          mockBehaviour
+		expectedURLs  []entity.URL
+		expectedNext  string
+		expectedError error
+	}{
+		{
+			name:        "OK",
+			inputCursor: "abcdefghif",
+			inputLimit:  1,
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				url.EXPECT().GetURLs(gomock.Any(), "abcdefghif", 1).Return(urls, "abcdefghig", nil)
+			},
+			expectedURLs: urls,
+			expectedNext: "abcdefghig",
+		},
+		{
+			name:       "invalid page size",
+			inputLimit: 0,
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("URLService.ListURLs", []any{zap.String("error", ErrInvalidPageSize.Error())})
+			},
+			expectedError: ErrInvalidPageSize,
+		},
+		{
+			name:       "storage error",
+			inputLimit: 1,
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				url.EXPECT().GetURLs(gomock.Any(), "", 1).Return(nil, "", errors.New("connection refused"))
+				log.EXPECT().Error("URLService.ListURLs - s.url.GetURLs",
+					[]any{zap.String("error", "connection refused")})
+			},
+			expectedError: ErrInternalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			urlStorage := mock_storage.NewMockURL(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			tc.mock(urlStorage, log)
+
+			urlService := NewURLService(Config{}, nil, urlStorage, allowAllDomains, log)
+
+			urls, next, err := urlService.ListURLs(context.Background(), tc.inputCursor, tc.inputLimit)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedURLs, urls)
+			require.Equal(t, tc.expectedNext, next)
+		})
+	}
+}
+
+func TestURLService_UpdateOriginal(t *testing.T) {
+	type mockBehaviour func(url *mock_storage.MockURL, log *mock_logger.MockLogger)
+
+	testCases := []struct {
+		name          string
+		inputAlias    string
+		inputOriginal string
+		mock          mockBehaviour
+		expectedError error
+	}{
+		{
+			name:          "OK",
+			inputAlias:    "abcdefghig",
+			inputOriginal: " http://google.com/ ",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				url.EXPECT().UpdateOriginal(gomock.Any(), entity.URL{Original: "http://google.com/", Alias: "abcdefghig"}).Return(nil)
+				log.EXPECT().Info("URLService.UpdateOriginal - original url was updated successfully",
+					[]any{zap.String("alias", "abcdefghig")})
+			},
+		},
+		{
+			name:          "OK normalized",
+			inputAlias:    "abcdefghig",
+			inputOriginal: "HTTP://Google.com:80/a/../b",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				url.EXPECT().UpdateOriginal(gomock.Any(), entity.URL{
+					Original: "http://google.com/b",
+					Alias:    "abcdefghig",
+					Input:    "HTTP://Google.com:80/a/../b",
+				}).Return(nil)
+				log.EXPECT().Info("URLService.UpdateOriginal - original url was updated successfully",
+					[]any{zap.String("alias", "abcdefghig")})
+			},
+		},
+		{
+			name:       "empty original url",
+			inputAlias: "abcdefghig",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("URLService.UpdateOriginal", []any{zap.String("error", ErrEmptyOriginalURL.Error())})
+			},
+			expectedError: ErrEmptyOriginalURL,
+		},
+		{
+			name:          "empty alias",
+			inputOriginal: "http://google.com/",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				log.EXPECT().Error("URLService.UpdateOriginal", []any{zap.String("error", ErrEmptyURLAlias.Error())})
+			},
+			expectedError: ErrEmptyURLAlias,
+		},
+		{
+			name:          "alias is not found",
+			inputAlias:    "abcdefghig",
+			inputOriginal: "http://google.com/",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				url.EXPECT().UpdateOriginal(gomock.Any(), entity.URL{Original: "http://google.com/", Alias: "abcdefghig"}).Return(storageerrors.ErrURLAliasNotFound)
+				log.EXPECT().Error("URLService.UpdateOriginal", []any{
+					zap.String("alias", "abcdefghig"),
+					zap.String("error", storageerrors.ErrURLAliasNotFound.Error()),
+				})
+			},
+			expectedError: ErrOriginalURLNotFound,
+		},
+		{
+			name:          "original url already exists",
+			inputAlias:    "abcdefghig",
+			inputOriginal: "http://google.com/",
+			mock: func(url *mock_storage.MockURL, log *mock_logger.MockLogger) {
+				url.EXPECT().UpdateOriginal(gomock.Any(), entity.URL{Original: "http://google.com/", Alias: "abcdefghig"}).Return(storageerrors.ErrOriginalURLExists)
+				log.EXPECT().Error("URLService.UpdateOriginal", []any{
+					zap.String("original", "http://google.com/"),
+					zap.String("error", storageerrors.ErrOriginalURLExists.Error()),
+				})
+			},
+			expectedError: storageerrors.ErrOriginalURLExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			urlStorage := mock_storage.NewMockURL(ctrl)
+			log := mock_logger.NewMockLogger(ctrl)
+
+			tc.mock(urlStorage, log)
+
+			urlService := NewURLService(Config{}, nil, urlStorage, allowAllDomains, log)
+
+			err := urlService.UpdateOriginal(context.Background(), tc.inputAlias, tc.inputOriginal)
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
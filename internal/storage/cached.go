@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/logger"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CacheConfig struct {
+	Enabled bool `yaml:"enabled" env:"CACHE_ENABLED"`
+	// TTL of cached originals, urls which expire earlier are cached until they expire.
+	TTL time.Duration `yaml:"ttl" env:"CACHE_TTL" env-default:"1h"`
+	// NegativeTTL of unknown aliases, zero disables caching of misses.
+	NegativeTTL time.Duration `yaml:"negative_ttl" env:"CACHE_NEGATIVE_TTL" env-default:"1m"`
+	// Timeout of cache calls, so a slow cache does not delay requests to the source.
+	Timeout time.Duration `yaml:"timeout" env:"CACHE_TIMEOUT" env-default:"100ms"`
+}
+
+// CachedURL reads originals through the cache and writes created urls to it.
+// The source stays the source of truth: cache errors are logged and the source is used instead.
+type CachedURL struct {
+	URLSource
+	cache  URLCache
+	cfg    CacheConfig
+	logger logger.Logger
+	now    func() time.Time
+}
+
+func NewCachedURL(source URLSource, cache URLCache, cfg CacheConfig, logger logger.Logger) *CachedURL {
+	return &CachedURL{
+		URLSource: source,
+		cache:     cache,
+		cfg:       cfg,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+func (r *CachedURL) CreateURL(ctx context.Context, url entity.URL) error {
+	if err := r.URLSource.CreateURL(ctx, url); err != nil {
+		return err
+	}
+
+	// overwrites the alias cached as missing
+	r.setOriginal(ctx, url, r.cache.SetOriginal)
+
+	return nil
+}
+
+// CreateURLs drops created aliases from the cache with one call instead of caching them,
+// so aliases cached as missing become visible. They are cached on the first read.
+func (r *CachedURL) CreateURLs(ctx context.Context, urls []entity.URL) ([]error, error) {
+	errs, err := r.URLSource.CreateURLs(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]string, 0, len(urls))
+	for i, url := range urls {
+		if errs[i] == nil {
+			created = append(created, url.Alias)
+		}
+	}
+	if len(created) > 0 {
+		r.delete(ctx, created...)
+	}
+
+	return errs, nil
+}
+
+func (r *CachedURL) GetOriginalByAlias(ctx context.Context, alias string) (string, error) {
+	cacheCtx, cancel := r.cacheContext(ctx)
+	original, ok, err := r.cache.GetOriginal(cacheCtx, alias)
+	cancel()
+	switch {
+	case errors.Is(err, storageerrors.ErrURLAliasNotFound):
+		return "", err
+	case err != nil:
+		r.logger.Error("CachedURL.GetOriginalByAlias - r.cache.GetOriginal", zap.String("error", err.Error()))
+	case ok:
+		return original, nil
+	}
+
+	url, err := r.URLSource.GetURLByAlias(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrURLAliasNotFound) && r.cfg.NegativeTTL > 0 {
+			cacheCtx, cancel := r.cacheContext(ctx)
+			defer cancel()
+			if err := r.cache.SetMissing(cacheCtx, alias, r.cfg.NegativeTTL); err != nil {
+				r.logger.Error("CachedURL.GetOriginalByAlias - r.cache.SetMissing", zap.String("error", err.Error()))
+			}
+		}
+		return "", err
+	}
+
+	if !url.ExpiresAt.IsZero() && !url.ExpiresAt.After(r.now()) {
+		return "", storageerrors.ErrURLExpired
+	}
+
+	// the alias changed after the source was read is not filled with the old original
+	r.setOriginal(ctx, url, r.cache.AddOriginal)
+
+	return url.Original, nil
+}
+
+// UpdateOriginal returns the cache error if the alias is not invalidated,
+// otherwise the old original would be served until the cache ttl passes.
+// Updating again invalidates the alias once the cache is back.
+func (r *CachedURL) UpdateOriginal(ctx context.Context, url entity.URL) error {
+	if err := r.URLSource.UpdateOriginal(ctx, url); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, url.Alias)
+}
+
+// DeleteByAlias returns the cache error if the alias is not invalidated, like UpdateOriginal.
+// Unknown aliases are invalidated too, so deleting again clears an alias left in the cache.
+func (r *CachedURL) DeleteByAlias(ctx context.Context, alias string) error {
+	err := r.URLSource.DeleteByAlias(ctx, alias)
+	if errors.Is(err, storageerrors.ErrURLAliasNotFound) {
+		r.delete(ctx, alias)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return r.invalidate(ctx, alias)
+}
+
+// setOriginal caches the url with set no longer than until it expires.
+func (r *CachedURL) setOriginal(ctx context.Context, url entity.URL, set func(ctx context.Context, alias, original string, ttl time.Duration) error) {
+	ttl := r.cfg.TTL
+	if !url.ExpiresAt.IsZero() {
+		ttl = min(ttl, url.ExpiresAt.Sub(r.now()))
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	cacheCtx, cancel := r.cacheContext(ctx)
+	defer cancel()
+
+	if err := set(cacheCtx, url.Alias, url.Original, ttl); err != nil {
+		r.logger.Error("CachedURL.setOriginal", zap.String("error", err.Error()))
+	}
+}
+
+// delete drops aliases from the cache and only logs errors.
+func (r *CachedURL) delete(ctx context.Context, aliases ...string) {
+	if err := r.invalidate(ctx, aliases...); err != nil {
+		r.logger.Error("CachedURL.delete", zap.String("error", err.Error()))
+	}
+}
+
+func (r *CachedURL) invalidate(ctx context.Context, aliases ...string) error {
+	cacheCtx, cancel := r.cacheContext(ctx)
+	defer cancel()
+
+	if err := r.cache.Delete(cacheCtx, aliases...); err != nil {
+		return fmt.Errorf("CachedURL.invalidate - r.cache.Delete: %w", err)
+	}
+	return nil
+}
+
+func (r *CachedURL) cacheContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.cfg.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.cfg.Timeout)
+}
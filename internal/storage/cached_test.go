@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redismock/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	mock_storage "github.com/romandnk/shortener/internal/storage/mock"
+	redisstorage "github.com/romandnk/shortener/internal/storage/redis"
+	mock_logger "github.com/romandnk/shortener/pkg/logger/mock"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestCachedURL_GetOriginalByAlias(t *testing.T) {
+	const (
+		alias    = "testtest11"
+		original = "https://google.com"
+	)
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := CacheConfig{TTL: time.Hour, NegativeTTL: time.Minute}
+	cacheErr := errors.New("connection refused")
+
+	testCases := []struct {
+		name             string
+		mock             func(source *mock_storage.MockURLSource, cache *mock_storage.MockURLCache, logger *mock_logger.MockLogger)
+		expectedOriginal string
+		expectedError    error
+	}{
+		{
+			name: "cache hit",
+			mock: func(source *mock_storage.MockURLSource, cache *mock_storage.MockURLCache, logger *mock_logger.MockLogger) {
+				cache.EXPECT().GetOriginal(gomock.Any(), alias).Return(original, true, nil)
+			},
+			expectedOriginal: original,
+		},
+		{
+			name: "cached as missing",
+			mock: func(source *mock_storage.MockURLSource, cache *mock_storage.MockURLCache, logger *mock_logger.MockLogger) {
+				cache.EXPECT().GetOriginal(gomock.Any(), alias).Return("", true, storageerrors.ErrURLAliasNotFound)
+			},
+			expectedError: storageerrors.ErrURLAliasNotFound,
+		},
+		{
+			name: "cache miss",
+			mock: func(source *mock_storage.MockURLSource, cache *mock_storage.MockURLCache, logger *mock_logger.MockLogger) {
+				cache.EXPECT().GetOriginal(gomock.Any(), alias).Return("", false, nil)
+				source.EXPECT().GetURLByAlias(gomock.Any(), alias).Return(entity.URL{Original: original, Alias: alias}, nil)
+				cache.EXPECT().AddOriginal(gomock.Any(), alias, original, time.Hour).Return(nil)
+			},
+			expectedOriginal: original,
+		},
+		{
+			name: "cache miss of url expiring before ttl",
+			mock: func(source *mock_storage.MockURLSource, cache *mock_storage.MockURLCache, logger *mock_logger.MockLogger) {
+				cache.EXPECT().GetOriginal(gomock.Any(), alias).Return("", false, nil)
+				source.EXPECT().GetURLByAlias(gomock.Any(), alias).
+					Return(entity.URL{Original: original, Alias: alias, ExpiresAt: now.Add(time.Minute)}, nil)
+				cache.EXPECT().AddOriginal(gomock.Any(), alias, original, time.Minute).Return(nil)
+			},
+			expectedOriginal: original,
+		},
+		{
+			name: "expired url is not cached",
+			mock: func(source *mock_storage.MockURLSource, cache *mock_storage.MockURLCache, logger *mock_logger.MockLogger) {
+				cache.EXPECT().GetOriginal(gomock.Any(), alias).Return("", false, nil)
+				source.EXPECT().GetURLByAlias(gomock.Any(), alias).
+					Return(entity.URL{Original: original, Alias: alias, ExpiresAt: now}, nil)
+			},
+			expectedError: storageerrors.ErrURLExpired,
+		},
+		{
+			name: "missing alias is cached",
+			mock: func(source *mock_storage.MockURLSource, cache *mock_storage.MockURLCache, logger *mock_logger.MockLogger) {
+				cache.EXPECT().GetOriginal(gomock.Any(), alias).Return("", false, nil)
+				source.EXPECT().GetURLByAlias(gomock.Any(), alias).Return(entity.URL{}, storageerrors.ErrURLAliasNotFound)
+				cache.EXPECT().SetMissing(gomock.Any(), alias, time.Minute).Return(nil)
+			},
+			expectedError: storageerrors.ErrURLAliasNotFound,
+		},
+		{
+			name: "cache is down",
+			mock: func(source *mock_storage.MockURLSource, cache *mock_storage.MockURLCache, logger *mock_logger.MockLogger) {
+				cache.EXPECT().GetOriginal(gomock.Any(), alias).Return("", false, cacheErr)
+				source.EXPECT().GetURLByAlias(gomock.Any(), alias).Return(entity.URL{Original: original, Alias: alias}, nil)
+				cache.EXPECT().AddOriginal(gomock.Any(), alias, original, time.Hour).Return(cacheErr)
+				logger.EXPECT().Error(gomock.Any(), gomock.Any()).Times(2)
+			},
+			expectedOriginal: original,
+		},
+		{
+			name: "source error",
+			mock: func(source *mock_storage.MockURLSource, cache *mock_storage.MockURLCache, logger *mock_logger.MockLogger) {
+				cache.EXPECT().GetOriginal(gomock.Any(), alias).Return("", false, nil)
+				source.EXPECT().GetURLByAlias(gomock.Any(), alias).Return(entity.URL{}, cacheErr)
+			},
+			expectedError: cacheErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			source := mock_storage.NewMockURLSource(ctrl)
+			cache := mock_storage.NewMockURLCache(ctrl)
+			logger := mock_logger.NewMockLogger(ctrl)
+			tc.mock(source, cache, logger)
+
+			repo := NewCachedURL(source, cache, cfg, logger)
+			repo.now = func() time.Time { return now }
+
+			actualOriginal, err := repo.GetOriginalByAlias(context.Background(), alias)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedOriginal, actualOriginal)
+		})
+	}
+}
+
+func TestCachedURL_Write(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	url := entity.URL{Original: "https://google.com", Alias: "testtest11"}
+	other := entity.URL{Original: "https://ya.ru", Alias: "testtest11"}
+	errCache := errors.New("connection refused")
+
+	source := mock_storage.NewMockURLSource(ctrl)
+	cache := mock_storage.NewMockURLCache(ctrl)
+	logger := mock_logger.NewMockLogger(ctrl)
+	repo := NewCachedURL(source, cache, CacheConfig{TTL: time.Hour, Timeout: time.Second}, logger)
+
+	gomock.InOrder(
+		source.EXPECT().CreateURL(ctx, url).Return(nil),
+		cache.EXPECT().SetOriginal(gomock.Any(), url.Alias, url.Original, time.Hour).Return(nil),
+
+		source.EXPECT().CreateURL(ctx, url).Return(storageerrors.ErrURLAliasExists),
+
+		source.EXPECT().UpdateOriginal(ctx, entity.URL{Original: "https://ya.ru", Alias: url.Alias}).Return(nil),
+		cache.EXPECT().Delete(gomock.Any(), url.Alias).Return(nil),
+
+		source.EXPECT().UpdateOriginal(ctx, url).Return(nil),
+		cache.EXPECT().Delete(gomock.Any(), url.Alias).Return(errCache),
+
+		source.EXPECT().DeleteByAlias(ctx, url.Alias).Return(nil),
+		cache.EXPECT().Delete(gomock.Any(), url.Alias).Return(errCache),
+
+		// deleting again clears the alias left in the cache
+		source.EXPECT().DeleteByAlias(ctx, url.Alias).Return(storageerrors.ErrURLAliasNotFound),
+		cache.EXPECT().Delete(gomock.Any(), url.Alias).Return(nil),
+
+		source.EXPECT().CreateURLs(ctx, []entity.URL{url, other}).Return([]error{nil, storageerrors.ErrURLAliasExists}, nil),
+		cache.EXPECT().Delete(gomock.Any(), url.Alias).Return(nil),
+	)
+
+	require.NoError(t, repo.CreateURL(ctx, url))
+	require.ErrorIs(t, repo.CreateURL(ctx, url), storageerrors.ErrURLAliasExists)
+	require.NoError(t, repo.UpdateOriginal(ctx, entity.URL{Original: "https://ya.ru", Alias: url.Alias}))
+	require.ErrorIs(t, repo.UpdateOriginal(ctx, url), errCache)
+	require.ErrorIs(t, repo.DeleteByAlias(ctx, url.Alias), errCache)
+	require.ErrorIs(t, repo.DeleteByAlias(ctx, url.Alias), storageerrors.ErrURLAliasNotFound)
+
+	errs, err := repo.CreateURLs(ctx, []entity.URL{url, other})
+	require.NoError(t, err)
+	require.Equal(t, []error{nil, storageerrors.ErrURLAliasExists}, errs)
+}
+
+// TestCachedURL_ReadRacingUpdate checks that a read which got the original before an update
+// does not cache it after the update invalidated the alias.
+func TestCachedURL_ReadRacingUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	old := entity.URL{Original: "https://google.com", Alias: "testtest11"}
+	updated := entity.URL{Original: "https://ya.ru", Alias: "testtest11"}
+
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	source := mock_storage.NewMockURLSource(ctrl)
+	logger := mock_logger.NewMockLogger(ctrl)
+	repo := NewCachedURL(source, redisstorage.NewURLCacheRepo(&redisdb.Redis{Client: db}), CacheConfig{TTL: time.Hour}, logger)
+
+	mock.ExpectGet("url_cache:testtest11").RedisNil()
+	mock.ExpectSet("url_cache:testtest11", "-", constant.URLCacheInvalidationTTL).SetVal("OK")
+	mock.ExpectSetNX("url_cache:testtest11", old.Original, time.Hour).SetVal(false)
+	mock.ExpectGet("url_cache:testtest11").SetVal("-")
+	mock.ExpectSetNX("url_cache:testtest11", updated.Original, time.Hour).SetVal(false)
+
+	gomock.InOrder(
+		// the update commits while the original is read
+		source.EXPECT().GetURLByAlias(gomock.Any(), old.Alias).DoAndReturn(func(ctx context.Context, alias string) (entity.URL, error) {
+			require.NoError(t, repo.UpdateOriginal(ctx, updated))
+			return old, nil
+		}),
+		source.EXPECT().UpdateOriginal(gomock.Any(), updated).Return(nil),
+		source.EXPECT().GetURLByAlias(gomock.Any(), old.Alias).Return(updated, nil),
+	)
+
+	original, err := repo.GetOriginalByAlias(ctx, old.Alias)
+	require.NoError(t, err)
+	require.Equal(t, old.Original, original)
+
+	original, err = repo.GetOriginalByAlias(ctx, old.Alias)
+	require.NoError(t, err)
+	require.Equal(t, updated.Original, original)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
@@ -8,4 +8,13 @@ var (
 	ErrOriginalURLExists = errors.New("original url already exists")
 	ErrURLAliasExists    = errors.New("url alias already exists")
 	ErrURLAliasNotFound  = errors.New("url alias is not found")
+	ErrOriginalNotFound  = errors.New("original url is not found")
+	ErrURLExpired        = errors.New("url alias is expired")
 )
+
+var (
+	ErrAPIKeyExists   = errors.New("api key already exists")
+	ErrAPIKeyNotFound = errors.New("api key is not found")
+)
+
+var ErrDomainRuleNotFound = errors.New("domain rule is not found")
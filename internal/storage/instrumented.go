@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"time"
+)
+
+// Observer receives latencies of storage operations.
+type Observer interface {
+	ObserveStorage(backend, operation string, failed bool, duration time.Duration)
+}
+
+// Instrument wraps repositories of the storage to report operation latencies to the observer.
+func Instrument(s *Storage, backend string, observer Observer) *Storage {
+	o := instrumented{backend: backend, observer: observer}
+
+	instrumentedStorage := *s
+	if s.URL != nil {
+		instrumentedStorage.URL = &instrumentedURL{instrumented: o, url: s.URL}
+	}
+	if s.Click != nil {
+		instrumentedStorage.Click = &instrumentedClick{instrumented: o, click: s.Click}
+	}
+	if s.APIKey != nil {
+		instrumentedStorage.APIKey = &instrumentedAPIKey{instrumented: o, apiKey: s.APIKey}
+	}
+	if s.Domain != nil {
+		instrumentedStorage.Domain = &instrumentedDomain{instrumented: o, domain: s.Domain}
+	}
+	if s.Sequence != nil {
+		instrumentedStorage.Sequence = &instrumentedSequence{instrumented: o, sequence: s.Sequence}
+	}
+	if s.Purger != nil {
+		instrumentedStorage.Purger = &instrumentedPurger{instrumented: o, purger: s.Purger}
+	}
+
+	return &instrumentedStorage
+}
+
+// expectedErrors are results of operations rather than failures of the storage.
+var expectedErrors = []error{
+	storageerrors.ErrOriginalURLExists,
+	storageerrors.ErrURLAliasExists,
+	storageerrors.ErrURLAliasNotFound,
+	storageerrors.ErrOriginalNotFound,
+	storageerrors.ErrURLExpired,
+	storageerrors.ErrAPIKeyExists,
+	storageerrors.ErrAPIKeyNotFound,
+	storageerrors.ErrDomainRuleNotFound,
+}
+
+type instrumented struct {
+	backend  string
+	observer Observer
+}
+
+func (i instrumented) observe(operation string, start time.Time, err error) {
+	failed := err != nil
+	for _, expected := range expectedErrors {
+		if errors.Is(err, expected) {
+			failed = false
+			break
+		}
+	}
+	i.observer.ObserveStorage(i.backend, operation, failed, time.Since(start))
+}
+
+type instrumentedURL struct {
+	instrumented
+	url URL
+}
+
+func (r *instrumentedURL) CreateURL(ctx context.Context, url entity.URL) error {
+	start := time.Now()
+	err := r.url.CreateURL(ctx, url)
+	r.observe("CreateURL", start, err)
+	return err
+}
+
+func (r *instrumentedURL) CreateURLs(ctx context.Context, urls []entity.URL) ([]error, error) {
+	start := time.Now()
+	errs, err := r.url.CreateURLs(ctx, urls)
+	r.observe("CreateURLs", start, err)
+	return errs, err
+}
+
+func (r *instrumentedURL) GetOriginalByAlias(ctx context.Context, alias string) (string, error) {
+	start := time.Now()
+	res, err := r.url.GetOriginalByAlias(ctx, alias)
+	r.observe("GetOriginalByAlias", start, err)
+	return res, err
+}
+
+func (r *instrumentedURL) GetAliasByOriginal(ctx context.Context, original string) (string, error) {
+	start := time.Now()
+	res, err := r.url.GetAliasByOriginal(ctx, original)
+	r.observe("GetAliasByOriginal", start, err)
+	return res, err
+}
+
+func (r *instrumentedURL) GetURLs(ctx context.Context, cursor string, limit int) ([]entity.URL, string, error) {
+	start := time.Now()
+	urls, next, err := r.url.GetURLs(ctx, cursor, limit)
+	r.observe("GetURLs", start, err)
+	return urls, next, err
+}
+
+func (r *instrumentedURL) DeleteByAlias(ctx context.Context, alias string) error {
+	start := time.Now()
+	err := r.url.DeleteByAlias(ctx, alias)
+	r.observe("DeleteByAlias", start, err)
+	return err
+}
+
+func (r *instrumentedURL) UpdateOriginal(ctx context.Context, url entity.URL) error {
+	start := time.Now()
+	err := r.url.UpdateOriginal(ctx, url)
+	r.observe("UpdateOriginal", start, err)
+	return err
+}
+
+type instrumentedClick struct {
+	instrumented
+	click Click
+}
+
+func (r *instrumentedClick) CreateClicks(ctx context.Context, clicks []entity.Click) error {
+	start := time.Now()
+	err := r.click.CreateClicks(ctx, clicks)
+	r.observe("CreateClicks", start, err)
+	return err
+}
+
+func (r *instrumentedClick) GetClickStats(ctx context.Context, alias string) (entity.ClickStats, error) {
+	start := time.Now()
+	res, err := r.click.GetClickStats(ctx, alias)
+	r.observe("GetClickStats", start, err)
+	return res, err
+}
+
+type instrumentedAPIKey struct {
+	instrumented
+	apiKey APIKey
+}
+
+func (r *instrumentedAPIKey) CreateAPIKey(ctx context.Context, key entity.APIKey) error {
+	start := time.Now()
+	err := r.apiKey.CreateAPIKey(ctx, key)
+	r.observe("CreateAPIKey", start, err)
+	return err
+}
+
+func (r *instrumentedAPIKey) GetAPIKeyByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	start := time.Now()
+	res, err := r.apiKey.GetAPIKeyByHash(ctx, hash)
+	r.observe("GetAPIKeyByHash", start, err)
+	return res, err
+}
+
+func (r *instrumentedAPIKey) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	start := time.Now()
+	res, err := r.apiKey.GetAPIKeys(ctx)
+	r.observe("GetAPIKeys", start, err)
+	return res, err
+}
+
+func (r *instrumentedAPIKey) DeleteAPIKey(ctx context.Context, name string) error {
+	start := time.Now()
+	err := r.apiKey.DeleteAPIKey(ctx, name)
+	r.observe("DeleteAPIKey", start, err)
+	return err
+}
+
+type instrumentedDomain struct {
+	instrumented
+	domain Domain
+}
+
+func (r *instrumentedDomain) SaveDomainRule(ctx context.Context, rule entity.DomainRule) error {
+	start := time.Now()
+	err := r.domain.SaveDomainRule(ctx, rule)
+	r.observe("SaveDomainRule", start, err)
+	return err
+}
+
+func (r *instrumentedDomain) GetDomainRules(ctx context.Context) ([]entity.DomainRule, error) {
+	start := time.Now()
+	res, err := r.domain.GetDomainRules(ctx)
+	r.observe("GetDomainRules", start, err)
+	return res, err
+}
+
+func (r *instrumentedDomain) DeleteDomainRule(ctx context.Context, pattern string) error {
+	start := time.Now()
+	err := r.domain.DeleteDomainRule(ctx, pattern)
+	r.observe("DeleteDomainRule", start, err)
+	return err
+}
+
+type instrumentedSequence struct {
+	instrumented
+	sequence Sequence
+}
+
+func (r *instrumentedSequence) NextAliasID(ctx context.Context) (uint64, error) {
+	start := time.Now()
+	res, err := r.sequence.NextAliasID(ctx)
+	r.observe("NextAliasID", start, err)
+	return res, err
+}
+
+type instrumentedPurger struct {
+	instrumented
+	purger Purger
+}
+
+func (r *instrumentedPurger) DeleteExpired(ctx context.Context) (int64, error) {
+	start := time.Now()
+	res, err := r.purger.DeleteExpired(ctx)
+	r.observe("DeleteExpired", start, err)
+	return res, err
+}
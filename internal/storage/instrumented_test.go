@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	mock_storage "github.com/romandnk/shortener/internal/storage/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type observation struct {
+	backend   string
+	operation string
+	failed    bool
+}
+
+type observer struct {
+	observations []observation
+}
+
+func (o *observer) ObserveStorage(backend, operation string, failed bool, _ time.Duration) {
+	o.observations = append(o.observations, observation{backend, operation, failed})
+}
+
+func TestInstrument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	url := mock_storage.NewMockURL(ctrl)
+	obs := &observer{}
+
+	repo := Instrument(&Storage{URL: url}, "postgres", obs)
+	require.Nil(t, repo.Purger)
+
+	ctx := context.Background()
+
+	url.EXPECT().GetOriginalByAlias(ctx, "testtest11").Return("https://google.com", nil)
+	url.EXPECT().GetOriginalByAlias(ctx, "testtest12").Return("", storageerrors.ErrURLAliasNotFound)
+	url.EXPECT().DeleteByAlias(ctx, "testtest11").Return(errors.New("connection refused"))
+
+	original, err := repo.URL.GetOriginalByAlias(ctx, "testtest11")
+	require.NoError(t, err)
+	require.Equal(t, "https://google.com", original)
+
+	_, err = repo.URL.GetOriginalByAlias(ctx, "testtest12")
+	require.ErrorIs(t, err, storageerrors.ErrURLAliasNotFound)
+
+	require.Error(t, repo.URL.DeleteByAlias(ctx, "testtest11"))
+
+	require.Equal(t, []observation{
+		{"postgres", "GetOriginalByAlias", false},
+		{"postgres", "GetOriginalByAlias", false},
+		{"postgres", "DeleteByAlias", true},
+	}, obs.observations)
+}
@@ -0,0 +1,83 @@
+package memorystorage
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"sort"
+	"sync"
+)
+
+type APIKeyRepo struct {
+	mu     sync.RWMutex
+	byName map[string]entity.APIKey
+	// nameByHash maps hash of the key to its name
+	nameByHash map[string]string
+}
+
+func NewAPIKeyRepo() *APIKeyRepo {
+	return &APIKeyRepo{
+		byName:     make(map[string]entity.APIKey),
+		nameByHash: make(map[string]string),
+	}
+}
+
+func (r *APIKeyRepo) CreateAPIKey(ctx context.Context, key entity.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byName[key.Name]; ok {
+		return storageerrors.ErrAPIKeyExists
+	}
+	if _, ok := r.nameByHash[key.Hash]; ok {
+		return storageerrors.ErrAPIKeyExists
+	}
+
+	r.byName[key.Name] = key
+	r.nameByHash[key.Hash] = key.Name
+
+	return nil
+}
+
+func (r *APIKeyRepo) GetAPIKeyByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name, ok := r.nameByHash[hash]
+	if !ok {
+		return entity.APIKey{}, storageerrors.ErrAPIKeyNotFound
+	}
+
+	return r.byName[name], nil
+}
+
+func (r *APIKeyRepo) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]entity.APIKey, 0, len(r.byName))
+	for _, key := range r.byName {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+
+	return keys, nil
+}
+
+func (r *APIKeyRepo) DeleteAPIKey(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.byName[name]
+	if !ok {
+		return storageerrors.ErrAPIKeyNotFound
+	}
+
+	delete(r.byName, name)
+	delete(r.nameByHash, key.Hash)
+
+	return nil
+}
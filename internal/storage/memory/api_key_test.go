@@ -0,0 +1,37 @@
+package memorystorage
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyRepo(t *testing.T) {
+	ctx := context.Background()
+	apiKeyStorage := NewAPIKeyRepo()
+
+	first := entity.APIKey{Name: "second", Hash: "hash1", CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	second := entity.APIKey{Name: "first", Hash: "hash2", CreatedAt: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)}
+
+	require.NoError(t, apiKeyStorage.CreateAPIKey(ctx, first))
+	require.NoError(t, apiKeyStorage.CreateAPIKey(ctx, second))
+	require.ErrorIs(t, apiKeyStorage.CreateAPIKey(ctx, entity.APIKey{Name: "second", Hash: "hash3"}), storageerrors.ErrAPIKeyExists)
+	require.ErrorIs(t, apiKeyStorage.CreateAPIKey(ctx, entity.APIKey{Name: "third", Hash: "hash1"}), storageerrors.ErrAPIKeyExists)
+
+	key, err := apiKeyStorage.GetAPIKeyByHash(ctx, "hash1")
+	require.NoError(t, err)
+	require.Equal(t, first, key)
+
+	keys, err := apiKeyStorage.GetAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []entity.APIKey{second, first}, keys)
+
+	require.NoError(t, apiKeyStorage.DeleteAPIKey(ctx, "second"))
+	require.ErrorIs(t, apiKeyStorage.DeleteAPIKey(ctx, "second"), storageerrors.ErrAPIKeyNotFound)
+
+	_, err = apiKeyStorage.GetAPIKeyByHash(ctx, "hash1")
+	require.ErrorIs(t, err, storageerrors.ErrAPIKeyNotFound)
+}
@@ -0,0 +1,65 @@
+package memorystorage
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	"sort"
+	"sync"
+	"time"
+)
+
+type ClickRepo struct {
+	mu sync.RWMutex
+	// daily maps alias to click counters by UTC day
+	daily map[string]map[time.Time]int64
+}
+
+func NewClickRepo() *ClickRepo {
+	return &ClickRepo{
+		daily: make(map[string]map[time.Time]int64),
+	}
+}
+
+// CreateClicks keeps only counters, click details are not stored in memory.
+func (r *ClickRepo) CreateClicks(ctx context.Context, clicks []entity.Click) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, click := range clicks {
+		counters, ok := r.daily[click.Alias]
+		if !ok {
+			counters = make(map[time.Time]int64)
+			r.daily[click.Alias] = counters
+		}
+
+		clickedAt := click.ClickedAt.UTC()
+		day := time.Date(clickedAt.Year(), clickedAt.Month(), clickedAt.Day(), 0, 0, 0, 0, time.UTC)
+		counters[day]++
+	}
+
+	return nil
+}
+
+func (r *ClickRepo) deleteClicks(alias string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.daily, alias)
+}
+
+func (r *ClickRepo) GetClickStats(ctx context.Context, alias string) (entity.ClickStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var stats entity.ClickStats
+	for day, clicks := range r.daily[alias] {
+		stats.Total += clicks
+		stats.Daily = append(stats.Daily, entity.DailyClicks{Day: day, Clicks: clicks})
+	}
+
+	sort.Slice(stats.Daily, func(i, j int) bool {
+		return stats.Daily[i].Day.Before(stats.Daily[j].Day)
+	})
+
+	return stats, nil
+}
@@ -0,0 +1,35 @@
+package memorystorage
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestClickRepo_GetClickStats(t *testing.T) {
+	ctx := context.Background()
+	clickStorage := NewClickRepo()
+
+	require.NoError(t, clickStorage.CreateClicks(ctx, []entity.Click{
+		{Alias: "testtest11", ClickedAt: time.Date(2030, 1, 3, 23, 0, 0, 0, time.UTC)},
+		{Alias: "testtest11", ClickedAt: time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Alias: "testtest11", ClickedAt: time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{Alias: "testtest12", ClickedAt: time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)},
+	}))
+
+	stats, err := clickStorage.GetClickStats(ctx, "testtest11")
+	require.NoError(t, err)
+	require.Equal(t, entity.ClickStats{
+		Total: 3,
+		Daily: []entity.DailyClicks{
+			{Day: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Clicks: 2},
+			{Day: time.Date(2030, 1, 3, 0, 0, 0, 0, time.UTC), Clicks: 1},
+		},
+	}, stats)
+
+	stats, err = clickStorage.GetClickStats(ctx, "testtest13")
+	require.NoError(t, err)
+	require.Equal(t, entity.ClickStats{}, stats)
+}
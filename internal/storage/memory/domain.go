@@ -0,0 +1,58 @@
+package memorystorage
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"sort"
+	"sync"
+)
+
+type DomainRepo struct {
+	mu sync.RWMutex
+	// lists maps patterns to lists
+	lists map[string]entity.DomainList
+}
+
+func NewDomainRepo() *DomainRepo {
+	return &DomainRepo{
+		lists: make(map[string]entity.DomainList),
+	}
+}
+
+func (r *DomainRepo) SaveDomainRule(ctx context.Context, rule entity.DomainRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lists[rule.Pattern] = rule.List
+
+	return nil
+}
+
+func (r *DomainRepo) GetDomainRules(ctx context.Context) ([]entity.DomainRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := make([]entity.DomainRule, 0, len(r.lists))
+	for pattern, list := range r.lists {
+		rules = append(rules, entity.DomainRule{Pattern: pattern, List: list})
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Pattern < rules[j].Pattern
+	})
+
+	return rules, nil
+}
+
+func (r *DomainRepo) DeleteDomainRule(ctx context.Context, pattern string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.lists[pattern]; !ok {
+		return storageerrors.ErrDomainRuleNotFound
+	}
+	delete(r.lists, pattern)
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package memorystorage
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDomainRepo(t *testing.T) {
+	ctx := context.Background()
+	domainStorage := NewDomainRepo()
+
+	require.NoError(t, domainStorage.SaveDomainRule(ctx, entity.DomainRule{Pattern: "*.evil.com", List: entity.DomainBlock}))
+	require.NoError(t, domainStorage.SaveDomainRule(ctx, entity.DomainRule{Pattern: "safe.evil.com", List: entity.DomainBlock}))
+	require.NoError(t, domainStorage.SaveDomainRule(ctx, entity.DomainRule{Pattern: "safe.evil.com", List: entity.DomainAllow}))
+
+	rules, err := domainStorage.GetDomainRules(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []entity.DomainRule{
+		{Pattern: "*.evil.com", List: entity.DomainBlock},
+		{Pattern: "safe.evil.com", List: entity.DomainAllow},
+	}, rules)
+
+	require.NoError(t, domainStorage.DeleteDomainRule(ctx, "*.evil.com"))
+	require.ErrorIs(t, domainStorage.DeleteDomainRule(ctx, "*.evil.com"), storageerrors.ErrDomainRuleNotFound)
+
+	rules, err = domainStorage.GetDomainRules(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []entity.DomainRule{{Pattern: "safe.evil.com", List: entity.DomainAllow}}, rules)
+}
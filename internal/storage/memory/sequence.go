@@ -0,0 +1,18 @@
+package memorystorage
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type SequenceRepo struct {
+	last atomic.Uint64
+}
+
+func NewSequenceRepo() *SequenceRepo {
+	return &SequenceRepo{}
+}
+
+func (r *SequenceRepo) NextAliasID(ctx context.Context) (uint64, error) {
+	return r.last.Add(1), nil
+}
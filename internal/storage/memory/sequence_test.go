@@ -0,0 +1,18 @@
+package memorystorage
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSequenceRepo_NextAliasID(t *testing.T) {
+	ctx := context.Background()
+	sequence := NewSequenceRepo()
+
+	for expected := uint64(1); expected <= 3; expected++ {
+		id, err := sequence.NextAliasID(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, id)
+	}
+}
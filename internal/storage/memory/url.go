@@ -0,0 +1,200 @@
+package memorystorage
+
+import (
+	"context"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+type URLRepo struct {
+	mu      sync.RWMutex
+	byAlias map[string]entity.URL
+	// aliasByOriginal maps original url to its alias
+	aliasByOriginal map[string]string
+	// clicks of deleted urls are deleted too
+	clicks *ClickRepo
+}
+
+func NewURLRepo(clicks *ClickRepo) *URLRepo {
+	return &URLRepo{
+		byAlias:         make(map[string]entity.URL),
+		aliasByOriginal: make(map[string]string),
+		clicks:          clicks,
+	}
+}
+
+func expired(url entity.URL, now time.Time) bool {
+	return !url.ExpiresAt.IsZero() && !url.ExpiresAt.After(now)
+}
+
+func (r *URLRepo) CreateURL(ctx context.Context, url entity.URL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	if alias, ok := r.aliasByOriginal[url.Original]; ok {
+		if !expired(r.byAlias[alias], now) {
+			return storageerrors.ErrOriginalURLExists
+		}
+	}
+	if existing, ok := r.byAlias[url.Alias]; ok && !expired(existing, now) {
+		return storageerrors.ErrURLAliasExists
+	}
+
+	// expired urls are replaced by new ones
+	if alias, ok := r.aliasByOriginal[url.Original]; ok {
+		r.delete(r.byAlias[alias])
+	}
+	if existing, ok := r.byAlias[url.Alias]; ok {
+		r.delete(existing)
+	}
+
+	r.byAlias[url.Alias] = url
+	r.aliasByOriginal[url.Original] = url.Alias
+
+	return nil
+}
+
+// CreateURLs creates urls one by one, errors of conflicting urls are returned in their positions.
+func (r *URLRepo) CreateURLs(ctx context.Context, urls []entity.URL) ([]error, error) {
+	errs := make([]error, len(urls))
+	for i, url := range urls {
+		errs[i] = r.CreateURL(ctx, url)
+	}
+	return errs, nil
+}
+
+func (r *URLRepo) GetOriginalByAlias(ctx context.Context, alias string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	url, ok := r.byAlias[alias]
+	if !ok {
+		return "", storageerrors.ErrURLAliasNotFound
+	}
+	if expired(url, time.Now()) {
+		return "", storageerrors.ErrURLExpired
+	}
+
+	return url.Original, nil
+}
+
+// GetURLs returns not expired urls ordered by alias, the cursor is the last alias of the previous page.
+func (r *URLRepo) GetURLs(ctx context.Context, cursor string, limit int) ([]entity.URL, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+
+	aliases := make([]string, 0, len(r.byAlias))
+	for alias, url := range r.byAlias {
+		if alias > cursor && !expired(url, now) {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+
+	if len(aliases) > limit {
+		aliases = aliases[:limit]
+	}
+	urls := make([]entity.URL, 0, len(aliases))
+	for _, alias := range aliases {
+		urls = append(urls, r.byAlias[alias])
+	}
+
+	var next string
+	if len(urls) == limit {
+		next = urls[len(urls)-1].Alias
+	}
+
+	return urls, next, nil
+}
+
+func (r *URLRepo) GetAliasByOriginal(ctx context.Context, original string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alias, ok := r.aliasByOriginal[original]
+	if !ok {
+		return "", storageerrors.ErrOriginalNotFound
+	}
+
+	return alias, nil
+}
+
+// DeleteByAlias deletes the url together with its clicks, so the alias created again starts with no stats.
+func (r *URLRepo) DeleteByAlias(ctx context.Context, alias string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url, ok := r.byAlias[alias]
+	if !ok {
+		return storageerrors.ErrURLAliasNotFound
+	}
+
+	r.delete(url)
+
+	return nil
+}
+
+// UpdateOriginal points the alias to another original url, expired aliases cannot be updated.
+func (r *URLRepo) UpdateOriginal(ctx context.Context, updated entity.URL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	alias, original := updated.Alias, updated.Original
+	url, ok := r.byAlias[alias]
+	if !ok || expired(url, now) {
+		return storageerrors.ErrURLAliasNotFound
+	}
+	if url.Original == original {
+		return nil
+	}
+
+	if existing, ok := r.aliasByOriginal[original]; ok {
+		if !expired(r.byAlias[existing], now) {
+			return storageerrors.ErrOriginalURLExists
+		}
+		r.delete(r.byAlias[existing])
+	}
+
+	delete(r.aliasByOriginal, url.Original)
+	url.Original = original
+	url.Input = updated.Input
+	r.byAlias[alias] = url
+	r.aliasByOriginal[original] = alias
+
+	return nil
+}
+
+// DeleteExpired removes all expired urls.
+func (r *URLRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	var deleted int64
+	for _, url := range r.byAlias {
+		if expired(url, now) {
+			r.delete(url)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// delete removes the url with its clicks, so that a reused alias does not inherit them.
+// It must be called with the write lock held.
+func (r *URLRepo) delete(url entity.URL) {
+	delete(r.byAlias, url.Alias)
+	delete(r.aliasByOriginal, url.Original)
+	r.clicks.deleteClicks(url.Alias)
+}
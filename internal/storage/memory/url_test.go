@@ -0,0 +1,394 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestURLRepo_CreateURL(t *testing.T) {
+	existing := entity.URL{
+		Original: "http://test.com",
+		Alias:    "testtest11",
+	}
+
+	testCases := []struct {
+		name          string
+		existing      entity.URL
+		url           entity.URL
+		expectedError error
+	}{
+		{
+			name: "OK",
+			url: entity.URL{
+				Original: "http://test2.com",
+				Alias:    "testtest12",
+			},
+		},
+		{
+			name: "original url already exists",
+			url: entity.URL{
+				Original: "http://test.com",
+				Alias:    "testtest12",
+			},
+			expectedError: storageerrors.ErrOriginalURLExists,
+		},
+		{
+			name: "url alias already exists",
+			url: entity.URL{
+				Original: "http://test2.com",
+				Alias:    "testtest11",
+			},
+			expectedError: storageerrors.ErrURLAliasExists,
+		},
+		{
+			name: "expired url is replaced",
+			existing: entity.URL{
+				Original:  "http://test.com",
+				Alias:     "testtest11",
+				ExpiresAt: time.Now().Add(-time.Minute),
+			},
+			url: entity.URL{
+				Original: "http://test.com",
+				Alias:    "testtest11",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if tc.existing == (entity.URL{}) {
+				tc.existing = existing
+			}
+
+			urlStorage := NewURLRepo(NewClickRepo())
+			require.NoError(t, urlStorage.CreateURL(ctx, tc.existing))
+
+			err := urlStorage.CreateURL(ctx, tc.url)
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestURLRepo_CreateURLReusedAliasHasNoClicks(t *testing.T) {
+	ctx := context.Background()
+	clickStorage := NewClickRepo()
+	urlStorage := NewURLRepo(clickStorage)
+
+	require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+		Original:  "http://test.com",
+		Alias:     "testtest11",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}))
+	require.NoError(t, clickStorage.CreateClicks(ctx, []entity.Click{{Alias: "testtest11", ClickedAt: time.Now()}}))
+
+	require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+		Original: "http://test2.com",
+		Alias:    "testtest11",
+	}))
+
+	stats, err := clickStorage.GetClickStats(ctx, "testtest11")
+	require.NoError(t, err)
+	require.Zero(t, stats.Total)
+}
+
+func TestURLRepo_CreateURLConcurrent(t *testing.T) {
+	ctx := context.Background()
+	urlStorage := NewURLRepo(NewClickRepo())
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+	)
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := urlStorage.CreateURL(ctx, entity.URL{
+				Original: "http://test.com",
+				Alias:    fmt.Sprintf("alias%05d", i),
+			})
+			if err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+				return
+			}
+			assert.ErrorIs(t, err, storageerrors.ErrOriginalURLExists)
+		}(i)
+	}
+	wg.Wait()
+
+	require.Equal(t, 1, created)
+}
+
+func TestURLRepo_CreateURLs(t *testing.T) {
+	ctx := context.Background()
+	repo := NewURLRepo(NewClickRepo())
+
+	errs, err := repo.CreateURLs(ctx, []entity.URL{
+		{Original: "http://test.com", Alias: "testtest11"},
+		{Original: "http://test2.com", Alias: "testtest11"},
+		{Original: "http://test.com", Alias: "testtest12"},
+		{Original: "http://test3.com", Alias: "testtest13"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []error{nil, storageerrors.ErrURLAliasExists, storageerrors.ErrOriginalURLExists, nil}, errs)
+
+	original, err := repo.GetOriginalByAlias(ctx, "testtest13")
+	require.NoError(t, err)
+	require.Equal(t, "http://test3.com", original)
+}
+
+func TestURLRepo_GetOriginalByAlias(t *testing.T) {
+	testCases := []struct {
+		name             string
+		inputAlias       string
+		expectedOriginal string
+		expectedError    error
+	}{
+		{
+			name:             "OK",
+			inputAlias:       "testtest11",
+			expectedOriginal: "http://test.com",
+		},
+		{
+			name:          "url alias is not found",
+			inputAlias:    "testtest12",
+			expectedError: storageerrors.ErrURLAliasNotFound,
+		},
+		{
+			name:          "url alias is expired",
+			inputAlias:    "testtest13",
+			expectedError: storageerrors.ErrURLExpired,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			urlStorage := NewURLRepo(NewClickRepo())
+			require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+				Original: "http://test.com",
+				Alias:    "testtest11",
+			}))
+			require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+				Original:  "http://test3.com",
+				Alias:     "testtest13",
+				ExpiresAt: time.Now().Add(-time.Minute),
+			}))
+
+			original, err := urlStorage.GetOriginalByAlias(ctx, tc.inputAlias)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedOriginal, original)
+		})
+	}
+}
+
+func TestURLRepo_GetURLs(t *testing.T) {
+	repo := NewURLRepo(NewClickRepo())
+	ctx := context.Background()
+
+	urls := []entity.URL{
+		{Original: "http://test.com/3", Alias: "testtest13"},
+		{Original: "http://test.com/1", Alias: "testtest11"},
+		{Original: "http://test.com/2", Alias: "testtest12", ExpiresAt: time.Now().Add(-time.Hour)},
+		{Original: "http://test.com/4", Alias: "testtest14", ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	for _, url := range urls {
+		require.NoError(t, repo.CreateURL(ctx, url))
+	}
+
+	page, next, err := repo.GetURLs(ctx, "", 2)
+	require.NoError(t, err)
+	require.Equal(t, []entity.URL{urls[1], urls[0]}, page)
+	require.Equal(t, "testtest13", next)
+
+	page, next, err = repo.GetURLs(ctx, next, 2)
+	require.NoError(t, err)
+	require.Equal(t, []entity.URL{urls[3]}, page)
+	require.Empty(t, next)
+}
+
+func TestURLRepo_GetAliasByOriginal(t *testing.T) {
+	testCases := []struct {
+		name          string
+		inputOriginal string
+		expectedAlias string
+		expectedError error
+	}{
+		{
+			name:          "OK",
+			inputOriginal: "http://test.com",
+			expectedAlias: "testtest11",
+		},
+		{
+			name:          "original url is not found",
+			inputOriginal: "http://test2.com",
+			expectedError: storageerrors.ErrOriginalNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			urlStorage := NewURLRepo(NewClickRepo())
+			require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+				Original: "http://test.com",
+				Alias:    "testtest11",
+			}))
+
+			alias, err := urlStorage.GetAliasByOriginal(ctx, tc.inputOriginal)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedAlias, alias)
+		})
+	}
+}
+
+func TestURLRepo_DeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	clickStorage := NewClickRepo()
+	urlStorage := NewURLRepo(clickStorage)
+
+	require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+		Original:  "http://test.com",
+		Alias:     "testtest11",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}))
+	require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+		Original:  "http://test2.com",
+		Alias:     "testtest12",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+		Original: "http://test3.com",
+		Alias:    "testtest13",
+	}))
+
+	require.NoError(t, clickStorage.CreateClicks(ctx, []entity.Click{{Alias: "testtest11", ClickedAt: time.Now()}}))
+
+	deleted, err := urlStorage.DeleteExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	stats, err := clickStorage.GetClickStats(ctx, "testtest11")
+	require.NoError(t, err)
+	require.Zero(t, stats.Total)
+
+	_, err = urlStorage.GetOriginalByAlias(ctx, "testtest11")
+	require.ErrorIs(t, err, storageerrors.ErrURLAliasNotFound)
+
+	_, err = urlStorage.GetAliasByOriginal(ctx, "http://test.com")
+	require.ErrorIs(t, err, storageerrors.ErrOriginalNotFound)
+}
+
+func TestURLRepo_DeleteByAlias(t *testing.T) {
+	ctx := context.Background()
+	clickStorage := NewClickRepo()
+	urlStorage := NewURLRepo(clickStorage)
+
+	require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+		Original: "http://test.com",
+		Alias:    "testtest11",
+	}))
+	require.NoError(t, clickStorage.CreateClicks(ctx, []entity.Click{{Alias: "testtest11", ClickedAt: time.Now()}}))
+
+	require.NoError(t, urlStorage.DeleteByAlias(ctx, "testtest11"))
+
+	stats, err := clickStorage.GetClickStats(ctx, "testtest11")
+	require.NoError(t, err)
+	require.Zero(t, stats.Total)
+
+	require.ErrorIs(t, urlStorage.DeleteByAlias(ctx, "testtest11"), storageerrors.ErrURLAliasNotFound)
+
+	_, err = urlStorage.GetAliasByOriginal(ctx, "http://test.com")
+	require.ErrorIs(t, err, storageerrors.ErrOriginalNotFound)
+}
+
+func TestURLRepo_UpdateOriginal(t *testing.T) {
+	testCases := []struct {
+		name          string
+		alias         string
+		original      string
+		expectedError error
+	}{
+		{
+			name:     "OK",
+			alias:    "testtest11",
+			original: "http://test4.com",
+		},
+		{
+			name:     "OK same original",
+			alias:    "testtest11",
+			original: "http://test.com",
+		},
+		{
+			name:     "OK original of expired url",
+			alias:    "testtest11",
+			original: "http://test3.com",
+		},
+		{
+			name:          "original url already exists",
+			alias:         "testtest11",
+			original:      "http://test2.com",
+			expectedError: storageerrors.ErrOriginalURLExists,
+		},
+		{
+			name:          "url alias is expired",
+			alias:         "testtest13",
+			original:      "http://test4.com",
+			expectedError: storageerrors.ErrURLAliasNotFound,
+		},
+		{
+			name:          "url alias is not found",
+			alias:         "testtest14",
+			original:      "http://test4.com",
+			expectedError: storageerrors.ErrURLAliasNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			urlStorage := NewURLRepo(NewClickRepo())
+			require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{Original: "http://test.com", Alias: "testtest11"}))
+			require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{Original: "http://test2.com", Alias: "testtest12"}))
+			require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{
+				Original:  "http://test3.com",
+				Alias:     "testtest13",
+				ExpiresAt: time.Now().Add(-time.Minute),
+			}))
+
+			err := urlStorage.UpdateOriginal(ctx, entity.URL{Original: tc.original, Alias: tc.alias})
+			require.ErrorIs(t, err, tc.expectedError)
+			if tc.expectedError != nil {
+				return
+			}
+
+			original, err := urlStorage.GetOriginalByAlias(ctx, tc.alias)
+			require.NoError(t, err)
+			require.Equal(t, tc.original, original)
+
+			alias, err := urlStorage.GetAliasByOriginal(ctx, tc.original)
+			require.NoError(t, err)
+			require.Equal(t, tc.alias, alias)
+		})
+	}
+}
@@ -11,6 +11,7 @@ package mock_storage
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/romandnk/shortener/internal/entity"
 	gomock "go.uber.org/mock/gomock"
@@ -53,6 +54,50 @@ func (mr *MockURLMockRecorder) CreateURL(ctx, url any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateURL", reflect.TypeOf((*MockURL)(nil).CreateURL), ctx, url)
 }
 
+// CreateURLs mocks base method.
+func (m *MockURL) CreateURLs(ctx context.Context, urls []entity.URL) ([]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateURLs", ctx, urls)
+	ret0, _ := ret[0].([]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateURLs indicates an expected call of CreateURLs.
+func (mr *MockURLMockRecorder) CreateURLs(ctx, urls any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateURLs", reflect.TypeOf((*MockURL)(nil).CreateURLs), ctx, urls)
+}
+
+// DeleteByAlias mocks base method.
+func (m *MockURL) DeleteByAlias(ctx context.Context, alias string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByAlias", ctx, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByAlias indicates an expected call of DeleteByAlias.
+func (mr *MockURLMockRecorder) DeleteByAlias(ctx, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAlias", reflect.TypeOf((*MockURL)(nil).DeleteByAlias), ctx, alias)
+}
+
+// GetAliasByOriginal mocks base method.
+func (m *MockURL) GetAliasByOriginal(ctx context.Context, original string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAliasByOriginal", ctx, original)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAliasByOriginal indicates an expected call of GetAliasByOriginal.
+func (mr *MockURLMockRecorder) GetAliasByOriginal(ctx, original any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAliasByOriginal", reflect.TypeOf((*MockURL)(nil).GetAliasByOriginal), ctx, original)
+}
+
 // GetOriginalByAlias mocks base method.
 func (m *MockURL) GetOriginalByAlias(ctx context.Context, alias string) (string, error) {
 	m.ctrl.T.Helper()
@@ -67,3 +112,549 @@ func (mr *MockURLMockRecorder) GetOriginalByAlias(ctx, alias any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOriginalByAlias", reflect.TypeOf((*MockURL)(nil).GetOriginalByAlias), ctx, alias)
 }
+
+// GetURLs mocks base method.
+func (m *MockURL) GetURLs(ctx context.Context, cursor string, limit int) ([]entity.URL, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLs", ctx, cursor, limit)
+	ret0, _ := ret[0].([]entity.URL)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetURLs indicates an expected call of GetURLs.
+func (mr *MockURLMockRecorder) GetURLs(ctx, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLs", reflect.TypeOf((*MockURL)(nil).GetURLs), ctx, cursor, limit)
+}
+
+// UpdateOriginal mocks base method.
+func (m *MockURL) UpdateOriginal(ctx context.Context, url entity.URL) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOriginal", ctx, url)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOriginal indicates an expected call of UpdateOriginal.
+func (mr *MockURLMockRecorder) UpdateOriginal(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOriginal", reflect.TypeOf((*MockURL)(nil).UpdateOriginal), ctx, url)
+}
+
+// MockURLSource is a mock of URLSource interface.
+type MockURLSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockURLSourceMockRecorder
+}
+
+// MockURLSourceMockRecorder is the mock recorder for MockURLSource.
+type MockURLSourceMockRecorder struct {
+	mock *MockURLSource
+}
+
+// NewMockURLSource creates a new mock instance.
+func NewMockURLSource(ctrl *gomock.Controller) *MockURLSource {
+	mock := &MockURLSource{ctrl: ctrl}
+	mock.recorder = &MockURLSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockURLSource) EXPECT() *MockURLSourceMockRecorder {
+	return m.recorder
+}
+
+// CreateURL mocks base method.
+func (m *MockURLSource) CreateURL(ctx context.Context, url entity.URL) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateURL", ctx, url)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateURL indicates an expected call of CreateURL.
+func (mr *MockURLSourceMockRecorder) CreateURL(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateURL", reflect.TypeOf((*MockURLSource)(nil).CreateURL), ctx, url)
+}
+
+// CreateURLs mocks base method.
+func (m *MockURLSource) CreateURLs(ctx context.Context, urls []entity.URL) ([]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateURLs", ctx, urls)
+	ret0, _ := ret[0].([]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateURLs indicates an expected call of CreateURLs.
+func (mr *MockURLSourceMockRecorder) CreateURLs(ctx, urls any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateURLs", reflect.TypeOf((*MockURLSource)(nil).CreateURLs), ctx, urls)
+}
+
+// DeleteByAlias mocks base method.
+func (m *MockURLSource) DeleteByAlias(ctx context.Context, alias string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByAlias", ctx, alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByAlias indicates an expected call of DeleteByAlias.
+func (mr *MockURLSourceMockRecorder) DeleteByAlias(ctx, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAlias", reflect.TypeOf((*MockURLSource)(nil).DeleteByAlias), ctx, alias)
+}
+
+// GetAliasByOriginal mocks base method.
+func (m *MockURLSource) GetAliasByOriginal(ctx context.Context, original string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAliasByOriginal", ctx, original)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAliasByOriginal indicates an expected call of GetAliasByOriginal.
+func (mr *MockURLSourceMockRecorder) GetAliasByOriginal(ctx, original any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAliasByOriginal", reflect.TypeOf((*MockURLSource)(nil).GetAliasByOriginal), ctx, original)
+}
+
+// GetOriginalByAlias mocks base method.
+func (m *MockURLSource) GetOriginalByAlias(ctx context.Context, alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOriginalByAlias", ctx, alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOriginalByAlias indicates an expected call of GetOriginalByAlias.
+func (mr *MockURLSourceMockRecorder) GetOriginalByAlias(ctx, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOriginalByAlias", reflect.TypeOf((*MockURLSource)(nil).GetOriginalByAlias), ctx, alias)
+}
+
+// GetURLByAlias mocks base method.
+func (m *MockURLSource) GetURLByAlias(ctx context.Context, alias string) (entity.URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLByAlias", ctx, alias)
+	ret0, _ := ret[0].(entity.URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetURLByAlias indicates an expected call of GetURLByAlias.
+func (mr *MockURLSourceMockRecorder) GetURLByAlias(ctx, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLByAlias", reflect.TypeOf((*MockURLSource)(nil).GetURLByAlias), ctx, alias)
+}
+
+// GetURLs mocks base method.
+func (m *MockURLSource) GetURLs(ctx context.Context, cursor string, limit int) ([]entity.URL, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLs", ctx, cursor, limit)
+	ret0, _ := ret[0].([]entity.URL)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetURLs indicates an expected call of GetURLs.
+func (mr *MockURLSourceMockRecorder) GetURLs(ctx, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLs", reflect.TypeOf((*MockURLSource)(nil).GetURLs), ctx, cursor, limit)
+}
+
+// UpdateOriginal mocks base method.
+func (m *MockURLSource) UpdateOriginal(ctx context.Context, url entity.URL) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOriginal", ctx, url)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOriginal indicates an expected call of UpdateOriginal.
+func (mr *MockURLSourceMockRecorder) UpdateOriginal(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOriginal", reflect.TypeOf((*MockURLSource)(nil).UpdateOriginal), ctx, url)
+}
+
+// MockURLCache is a mock of URLCache interface.
+type MockURLCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockURLCacheMockRecorder
+}
+
+// MockURLCacheMockRecorder is the mock recorder for MockURLCache.
+type MockURLCacheMockRecorder struct {
+	mock *MockURLCache
+}
+
+// NewMockURLCache creates a new mock instance.
+func NewMockURLCache(ctrl *gomock.Controller) *MockURLCache {
+	mock := &MockURLCache{ctrl: ctrl}
+	mock.recorder = &MockURLCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockURLCache) EXPECT() *MockURLCacheMockRecorder {
+	return m.recorder
+}
+
+// AddOriginal mocks base method.
+func (m *MockURLCache) AddOriginal(ctx context.Context, alias, original string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOriginal", ctx, alias, original, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOriginal indicates an expected call of AddOriginal.
+func (mr *MockURLCacheMockRecorder) AddOriginal(ctx, alias, original, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOriginal", reflect.TypeOf((*MockURLCache)(nil).AddOriginal), ctx, alias, original, ttl)
+}
+
+// Delete mocks base method.
+func (m *MockURLCache) Delete(ctx context.Context, aliases ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range aliases {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockURLCacheMockRecorder) Delete(ctx any, aliases ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, aliases...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockURLCache)(nil).Delete), varargs...)
+}
+
+// GetOriginal mocks base method.
+func (m *MockURLCache) GetOriginal(ctx context.Context, alias string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOriginal", ctx, alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOriginal indicates an expected call of GetOriginal.
+func (mr *MockURLCacheMockRecorder) GetOriginal(ctx, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOriginal", reflect.TypeOf((*MockURLCache)(nil).GetOriginal), ctx, alias)
+}
+
+// SetMissing mocks base method.
+func (m *MockURLCache) SetMissing(ctx context.Context, alias string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMissing", ctx, alias, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMissing indicates an expected call of SetMissing.
+func (mr *MockURLCacheMockRecorder) SetMissing(ctx, alias, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMissing", reflect.TypeOf((*MockURLCache)(nil).SetMissing), ctx, alias, ttl)
+}
+
+// SetOriginal mocks base method.
+func (m *MockURLCache) SetOriginal(ctx context.Context, alias, original string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOriginal", ctx, alias, original, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOriginal indicates an expected call of SetOriginal.
+func (mr *MockURLCacheMockRecorder) SetOriginal(ctx, alias, original, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOriginal", reflect.TypeOf((*MockURLCache)(nil).SetOriginal), ctx, alias, original, ttl)
+}
+
+// MockClick is a mock of Click interface.
+type MockClick struct {
+	ctrl     *gomock.Controller
+	recorder *MockClickMockRecorder
+}
+
+// MockClickMockRecorder is the mock recorder for MockClick.
+type MockClickMockRecorder struct {
+	mock *MockClick
+}
+
+// NewMockClick creates a new mock instance.
+func NewMockClick(ctrl *gomock.Controller) *MockClick {
+	mock := &MockClick{ctrl: ctrl}
+	mock.recorder = &MockClickMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClick) EXPECT() *MockClickMockRecorder {
+	return m.recorder
+}
+
+// CreateClicks mocks base method.
+func (m *MockClick) CreateClicks(ctx context.Context, clicks []entity.Click) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClicks", ctx, clicks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClicks indicates an expected call of CreateClicks.
+func (mr *MockClickMockRecorder) CreateClicks(ctx, clicks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClicks", reflect.TypeOf((*MockClick)(nil).CreateClicks), ctx, clicks)
+}
+
+// GetClickStats mocks base method.
+func (m *MockClick) GetClickStats(ctx context.Context, alias string) (entity.ClickStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStats", ctx, alias)
+	ret0, _ := ret[0].(entity.ClickStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStats indicates an expected call of GetClickStats.
+func (mr *MockClickMockRecorder) GetClickStats(ctx, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStats", reflect.TypeOf((*MockClick)(nil).GetClickStats), ctx, alias)
+}
+
+// MockAPIKey is a mock of APIKey interface.
+type MockAPIKey struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyMockRecorder
+}
+
+// MockAPIKeyMockRecorder is the mock recorder for MockAPIKey.
+type MockAPIKeyMockRecorder struct {
+	mock *MockAPIKey
+}
+
+// NewMockAPIKey creates a new mock instance.
+func NewMockAPIKey(ctrl *gomock.Controller) *MockAPIKey {
+	mock := &MockAPIKey{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKey) EXPECT() *MockAPIKeyMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPIKey) CreateAPIKey(ctx context.Context, key entity.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIKeyMockRecorder) CreateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPIKey)(nil).CreateAPIKey), ctx, key)
+}
+
+// DeleteAPIKey mocks base method.
+func (m *MockAPIKey) DeleteAPIKey(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKey", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAPIKey indicates an expected call of DeleteAPIKey.
+func (mr *MockAPIKeyMockRecorder) DeleteAPIKey(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKey", reflect.TypeOf((*MockAPIKey)(nil).DeleteAPIKey), ctx, name)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockAPIKey) GetAPIKeyByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockAPIKeyMockRecorder) GetAPIKeyByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockAPIKey)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockAPIKey) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx)
+	ret0, _ := ret[0].([]entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockAPIKeyMockRecorder) GetAPIKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockAPIKey)(nil).GetAPIKeys), ctx)
+}
+
+// MockDomain is a mock of Domain interface.
+type MockDomain struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainMockRecorder
+}
+
+// MockDomainMockRecorder is the mock recorder for MockDomain.
+type MockDomainMockRecorder struct {
+	mock *MockDomain
+}
+
+// NewMockDomain creates a new mock instance.
+func NewMockDomain(ctrl *gomock.Controller) *MockDomain {
+	mock := &MockDomain{ctrl: ctrl}
+	mock.recorder = &MockDomainMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomain) EXPECT() *MockDomainMockRecorder {
+	return m.recorder
+}
+
+// DeleteDomainRule mocks base method.
+func (m *MockDomain) DeleteDomainRule(ctx context.Context, pattern string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDomainRule", ctx, pattern)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDomainRule indicates an expected call of DeleteDomainRule.
+func (mr *MockDomainMockRecorder) DeleteDomainRule(ctx, pattern any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDomainRule", reflect.TypeOf((*MockDomain)(nil).DeleteDomainRule), ctx, pattern)
+}
+
+// GetDomainRules mocks base method.
+func (m *MockDomain) GetDomainRules(ctx context.Context) ([]entity.DomainRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDomainRules", ctx)
+	ret0, _ := ret[0].([]entity.DomainRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomainRules indicates an expected call of GetDomainRules.
+func (mr *MockDomainMockRecorder) GetDomainRules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomainRules", reflect.TypeOf((*MockDomain)(nil).GetDomainRules), ctx)
+}
+
+// SaveDomainRule mocks base method.
+func (m *MockDomain) SaveDomainRule(ctx context.Context, rule entity.DomainRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDomainRule", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDomainRule indicates an expected call of SaveDomainRule.
+func (mr *MockDomainMockRecorder) SaveDomainRule(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDomainRule", reflect.TypeOf((*MockDomain)(nil).SaveDomainRule), ctx, rule)
+}
+
+// MockSequence is a mock of Sequence interface.
+type MockSequence struct {
+	ctrl     *gomock.Controller
+	recorder *MockSequenceMockRecorder
+}
+
+// MockSequenceMockRecorder is the mock recorder for MockSequence.
+type MockSequenceMockRecorder struct {
+	mock *MockSequence
+}
+
+// NewMockSequence creates a new mock instance.
+func NewMockSequence(ctrl *gomock.Controller) *MockSequence {
+	mock := &MockSequence{ctrl: ctrl}
+	mock.recorder = &MockSequenceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSequence) EXPECT() *MockSequenceMockRecorder {
+	return m.recorder
+}
+
+// NextAliasID mocks base method.
+func (m *MockSequence) NextAliasID(ctx context.Context) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextAliasID", ctx)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextAliasID indicates an expected call of NextAliasID.
+func (mr *MockSequenceMockRecorder) NextAliasID(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextAliasID", reflect.TypeOf((*MockSequence)(nil).NextAliasID), ctx)
+}
+
+// MockPurger is a mock of Purger interface.
+type MockPurger struct {
+	ctrl     *gomock.Controller
+	recorder *MockPurgerMockRecorder
+}
+
+// MockPurgerMockRecorder is the mock recorder for MockPurger.
+type MockPurgerMockRecorder struct {
+	mock *MockPurger
+}
+
+// NewMockPurger creates a new mock instance.
+func NewMockPurger(ctrl *gomock.Controller) *MockPurger {
+	mock := &MockPurger{ctrl: ctrl}
+	mock.recorder = &MockPurgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPurger) EXPECT() *MockPurgerMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockPurger) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockPurgerMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockPurger)(nil).DeleteExpired), ctx)
+}
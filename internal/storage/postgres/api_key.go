@@ -0,0 +1,105 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+)
+
+type APIKeyRepo struct {
+	*postgres.Postgres
+}
+
+func NewAPIKeyRepo(db *postgres.Postgres) *APIKeyRepo {
+	return &APIKeyRepo{db}
+}
+
+func (r *APIKeyRepo) CreateAPIKey(ctx context.Context, key entity.APIKey) error {
+	sql, args, _ := r.Builder.
+		Insert(constant.APIKeysTable).
+		Columns("name", "key_hash", "created_at").
+		Values(key.Name, key.Hash, key.CreatedAt).
+		ToSql()
+
+	_, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return storageerrors.ErrAPIKeyExists
+		}
+		return fmt.Errorf("APIKeyRepo.CreateAPIKey - r.Pool.Exec: %v", err)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepo) GetAPIKeyByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	sql, args, _ := r.Builder.
+		Select("name", "key_hash", "created_at").
+		From(constant.APIKeysTable).
+		Where(squirrel.Eq{"key_hash": hash}).
+		ToSql()
+
+	var key entity.APIKey
+	err := r.Pool.QueryRow(ctx, sql, args...).Scan(&key.Name, &key.Hash, &key.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return key, storageerrors.ErrAPIKeyNotFound
+		}
+		return key, fmt.Errorf("APIKeyRepo.GetAPIKeyByHash - r.Pool.QueryRow: %v", err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepo) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	sql, args, _ := r.Builder.
+		Select("name", "key_hash", "created_at").
+		From(constant.APIKeysTable).
+		OrderBy("name").
+		ToSql()
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo.GetAPIKeys - r.Pool.Query: %v", err)
+	}
+	defer rows.Close()
+
+	var keys []entity.APIKey
+	for rows.Next() {
+		var key entity.APIKey
+		if err = rows.Scan(&key.Name, &key.Hash, &key.CreatedAt); err != nil {
+			return nil, fmt.Errorf("APIKeyRepo.GetAPIKeys - rows.Scan: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("APIKeyRepo.GetAPIKeys - rows.Err: %v", err)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepo) DeleteAPIKey(ctx context.Context, name string) error {
+	sql, args, _ := r.Builder.
+		Delete(constant.APIKeysTable).
+		Where(squirrel.Eq{"name": name}).
+		ToSql()
+
+	tag, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("APIKeyRepo.DeleteAPIKey - r.Pool.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storageerrors.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyRepo_CreateAPIKey(t *testing.T) {
+	key := entity.APIKey{
+		Name:      "ci",
+		Hash:      "hash",
+		CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		name          string
+		error         error
+		expectedError error
+	}{
+		{
+			name: "OK",
+		},
+		{
+			name:          "key exists",
+			error:         &pgconn.PgError{Code: "23505"},
+			expectedError: storageerrors.ErrAPIKeyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			db := postgres.Postgres{
+				Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+				Pool:    mock,
+			}
+
+			query := regexp.QuoteMeta(`INSERT INTO api_keys (name,key_hash,created_at) VALUES ($1,$2,$3)`)
+			expect := mock.ExpectExec(query).WithArgs(key.Name, key.Hash, key.CreatedAt)
+			if tc.error != nil {
+				expect.WillReturnError(tc.error)
+			} else {
+				expect.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+
+			err = NewAPIKeyRepo(&db).CreateAPIKey(context.Background(), key)
+			require.ErrorIs(t, err, tc.expectedError)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
+
+func TestAPIKeyRepo_GetAPIKeyByHash(t *testing.T) {
+	key := entity.APIKey{
+		Name:      "ci",
+		Hash:      "hash",
+		CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		name          string
+		error         error
+		expectedKey   entity.APIKey
+		expectedError error
+	}{
+		{
+			name:        "OK",
+			expectedKey: key,
+		},
+		{
+			name:          "key not found",
+			error:         pgx.ErrNoRows,
+			expectedError: storageerrors.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			db := postgres.Postgres{
+				Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+				Pool:    mock,
+			}
+
+			query := regexp.QuoteMeta(`SELECT name, key_hash, created_at FROM api_keys WHERE key_hash = $1`)
+			expect := mock.ExpectQuery(query).WithArgs(key.Hash)
+			if tc.error != nil {
+				expect.WillReturnError(tc.error)
+			} else {
+				expect.WillReturnRows(pgxmock.NewRows([]string{"name", "key_hash", "created_at"}).
+					AddRow(key.Name, key.Hash, key.CreatedAt))
+			}
+
+			actualKey, err := NewAPIKeyRepo(&db).GetAPIKeyByHash(context.Background(), key.Hash)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedKey, actualKey)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
+
+func TestAPIKeyRepo_GetAPIKeys(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	db := postgres.Postgres{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		Pool:    mock,
+	}
+
+	createdAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedKeys := []entity.APIKey{
+		{Name: "admin", Hash: "hash1", CreatedAt: createdAt},
+		{Name: "ci", Hash: "hash2", CreatedAt: createdAt},
+	}
+
+	query := regexp.QuoteMeta(`SELECT name, key_hash, created_at FROM api_keys ORDER BY name`)
+	mock.ExpectQuery(query).WillReturnRows(pgxmock.NewRows([]string{"name", "key_hash", "created_at"}).
+		AddRow("admin", "hash1", createdAt).
+		AddRow("ci", "hash2", createdAt))
+
+	keys, err := NewAPIKeyRepo(&db).GetAPIKeys(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, expectedKeys, keys)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+func TestAPIKeyRepo_DeleteAPIKey(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rowsAffected  int64
+		error         error
+		expectedError error
+	}{
+		{
+			name:         "OK",
+			rowsAffected: 1,
+		},
+		{
+			name:          "key not found",
+			expectedError: storageerrors.ErrAPIKeyNotFound,
+		},
+		{
+			name:          "internal error",
+			error:         errors.New("connection refused"),
+			expectedError: errors.New("APIKeyRepo.DeleteAPIKey - r.Pool.Exec: connection refused"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			db := postgres.Postgres{
+				Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+				Pool:    mock,
+			}
+
+			query := regexp.QuoteMeta(`DELETE FROM api_keys WHERE name = $1`)
+			expect := mock.ExpectExec(query).WithArgs("ci")
+			if tc.error != nil {
+				expect.WillReturnError(tc.error)
+			} else {
+				expect.WillReturnResult(pgxmock.NewResult("DELETE", tc.rowsAffected))
+			}
+
+			err = NewAPIKeyRepo(&db).DeleteAPIKey(context.Background(), "ci")
+			if tc.expectedError != nil {
+				require.EqualError(t, err, tc.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
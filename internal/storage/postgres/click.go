@@ -0,0 +1,70 @@
+package postgresstorage
+
+import (
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+	"time"
+)
+
+var clickColumns = []string{"alias", "clicked_at", "referrer", "user_agent", "ip_hash"}
+
+type ClickRepo struct {
+	*postgres.Postgres
+}
+
+func NewClickRepo(db *postgres.Postgres) *ClickRepo {
+	return &ClickRepo{db}
+}
+
+func (r *ClickRepo) CreateClicks(ctx context.Context, clicks []entity.Click) error {
+	rows := make([][]any, 0, len(clicks))
+	for _, click := range clicks {
+		rows = append(rows, []any{click.Alias, click.ClickedAt, click.Referrer, click.UserAgent, click.IPHash})
+	}
+
+	_, err := r.Pool.CopyFrom(ctx, pgx.Identifier{constant.ClicksTable}, clickColumns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return fmt.Errorf("ClickRepo.CreateClicks - r.Pool.CopyFrom: %v", err)
+	}
+
+	return nil
+}
+
+func (r *ClickRepo) GetClickStats(ctx context.Context, alias string) (entity.ClickStats, error) {
+	var stats entity.ClickStats
+
+	sql, args, _ := r.Builder.
+		Select("(clicked_at AT TIME ZONE 'UTC')::date AS day", "count(*)").
+		From(constant.ClicksTable).
+		Where(squirrel.Eq{"alias": alias}).
+		GroupBy("day").
+		OrderBy("day").
+		ToSql()
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return stats, fmt.Errorf("ClickRepo.GetClickStats - r.Pool.Query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var daily entity.DailyClicks
+		if err = rows.Scan(&daily.Day, &daily.Clicks); err != nil {
+			return stats, fmt.Errorf("ClickRepo.GetClickStats - rows.Scan: %v", err)
+		}
+		daily.Day = time.Date(daily.Day.Year(), daily.Day.Month(), daily.Day.Day(), 0, 0, 0, 0, time.UTC)
+
+		stats.Total += daily.Clicks
+		stats.Daily = append(stats.Daily, daily)
+	}
+	if err = rows.Err(); err != nil {
+		return stats, fmt.Errorf("ClickRepo.GetClickStats - rows.Err: %v", err)
+	}
+
+	return stats, nil
+}
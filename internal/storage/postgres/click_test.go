@@ -0,0 +1,152 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestClickRepo_CreateClicks(t *testing.T) {
+	clickedAt := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		clicks        []entity.Click
+		copyError     error
+		expectedError bool
+	}{
+		{
+			name: "OK",
+			clicks: []entity.Click{
+				{Alias: "testtest11", ClickedAt: clickedAt, Referrer: "http://ref.com", UserAgent: "curl", IPHash: "hash"},
+				{Alias: "testtest12", ClickedAt: clickedAt},
+			},
+		},
+		{
+			name: "copy error",
+			clicks: []entity.Click{
+				{Alias: "testtest11", ClickedAt: clickedAt},
+			},
+			copyError:     errors.New("connection refused"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			db := postgres.Postgres{
+				Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+				Pool:    mock,
+			}
+
+			expect := mock.ExpectCopyFrom(pgx.Identifier{constant.ClicksTable}, clickColumns)
+			if tc.copyError != nil {
+				expect.WillReturnError(tc.copyError)
+			} else {
+				expect.WillReturnResult(int64(len(tc.clicks)))
+			}
+
+			clickStorage := NewClickRepo(&db)
+
+			err = clickStorage.CreateClicks(context.Background(), tc.clicks)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
+
+func TestClickRepo_GetClickStats(t *testing.T) {
+	dayOne := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	dayTwo := time.Date(2030, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		rows          *pgxmock.Rows
+		queryError    error
+		expectedStats entity.ClickStats
+		expectedError bool
+	}{
+		{
+			name: "OK",
+			rows: pgxmock.NewRows([]string{"day", "count"}).
+				AddRow(dayOne, int64(3)).
+				AddRow(dayTwo, int64(2)),
+			expectedStats: entity.ClickStats{
+				Total: 5,
+				Daily: []entity.DailyClicks{
+					{Day: dayOne, Clicks: 3},
+					{Day: dayTwo, Clicks: 2},
+				},
+			},
+		},
+		{
+			name: "no clicks",
+			rows: pgxmock.NewRows([]string{"day", "count"}),
+		},
+		{
+			name:          "query error",
+			queryError:    errors.New("connection refused"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			db := postgres.Postgres{
+				Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+				Pool:    mock,
+			}
+
+			sql, args, _ := db.Builder.
+				Select("(clicked_at AT TIME ZONE 'UTC')::date AS day", "count(*)").
+				From(constant.ClicksTable).
+				Where(squirrel.Eq{"alias": "testtest11"}).
+				GroupBy("day").
+				OrderBy("day").
+				ToSql()
+
+			expect := mock.ExpectQuery(regexp.QuoteMeta(sql)).WithArgs(args...)
+			if tc.queryError != nil {
+				expect.WillReturnError(tc.queryError)
+			} else {
+				expect.WillReturnRows(tc.rows)
+			}
+
+			clickStorage := NewClickRepo(&db)
+
+			stats, err := clickStorage.GetClickStats(context.Background(), "testtest11")
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedStats, stats)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package postgresstorage
+
+import (
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+)
+
+type DomainRepo struct {
+	*postgres.Postgres
+}
+
+func NewDomainRepo(db *postgres.Postgres) *DomainRepo {
+	return &DomainRepo{db}
+}
+
+func (r *DomainRepo) SaveDomainRule(ctx context.Context, rule entity.DomainRule) error {
+	sql, args, _ := r.Builder.
+		Insert(constant.DomainsTable).
+		Columns("pattern", "list").
+		Values(rule.Pattern, string(rule.List)).
+		Suffix("ON CONFLICT (pattern) DO UPDATE SET list = EXCLUDED.list").
+		ToSql()
+
+	_, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("DomainRepo.SaveDomainRule - r.Pool.Exec: %v", err)
+	}
+
+	return nil
+}
+
+func (r *DomainRepo) GetDomainRules(ctx context.Context) ([]entity.DomainRule, error) {
+	sql, args, _ := r.Builder.
+		Select("pattern", "list").
+		From(constant.DomainsTable).
+		OrderBy("pattern").
+		ToSql()
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("DomainRepo.GetDomainRules - r.Pool.Query: %v", err)
+	}
+	defer rows.Close()
+
+	var rules []entity.DomainRule
+	for rows.Next() {
+		var (
+			rule entity.DomainRule
+			list string
+		)
+		if err = rows.Scan(&rule.Pattern, &list); err != nil {
+			return nil, fmt.Errorf("DomainRepo.GetDomainRules - rows.Scan: %v", err)
+		}
+		rule.List = entity.DomainList(list)
+		rules = append(rules, rule)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DomainRepo.GetDomainRules - rows.Err: %v", err)
+	}
+
+	return rules, nil
+}
+
+func (r *DomainRepo) DeleteDomainRule(ctx context.Context, pattern string) error {
+	sql, args, _ := r.Builder.
+		Delete(constant.DomainsTable).
+		Where(squirrel.Eq{"pattern": pattern}).
+		ToSql()
+
+	tag, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("DomainRepo.DeleteDomainRule - r.Pool.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storageerrors.ErrDomainRuleNotFound
+	}
+
+	return nil
+}
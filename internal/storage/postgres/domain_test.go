@@ -0,0 +1,88 @@
+package postgresstorage
+
+import (
+	"context"
+	"github.com/Masterminds/squirrel"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func newDomainTestRepo(t *testing.T) (*DomainRepo, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+
+	db := postgres.Postgres{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		Pool:    mock,
+	}
+
+	return NewDomainRepo(&db), mock
+}
+
+func TestDomainRepo_SaveDomainRule(t *testing.T) {
+	repo, mock := newDomainTestRepo(t)
+
+	query := regexp.QuoteMeta(`INSERT INTO domain_rules (pattern,list) VALUES ($1,$2) ON CONFLICT (pattern) DO UPDATE SET list = EXCLUDED.list`)
+	mock.ExpectExec(query).WithArgs("*.evil.com", "block").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err := repo.SaveDomainRule(context.Background(), entity.DomainRule{Pattern: "*.evil.com", List: entity.DomainBlock})
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+func TestDomainRepo_GetDomainRules(t *testing.T) {
+	repo, mock := newDomainTestRepo(t)
+
+	query := regexp.QuoteMeta(`SELECT pattern, list FROM domain_rules ORDER BY pattern`)
+	mock.ExpectQuery(query).WillReturnRows(pgxmock.NewRows([]string{"pattern", "list"}).
+		AddRow("*.evil.com", "block").
+		AddRow("safe.evil.com", "allow"))
+
+	rules, err := repo.GetDomainRules(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []entity.DomainRule{
+		{Pattern: "*.evil.com", List: entity.DomainBlock},
+		{Pattern: "safe.evil.com", List: entity.DomainAllow},
+	}, rules)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+func TestDomainRepo_DeleteDomainRule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rowsAffected  int64
+		expectedError error
+	}{
+		{
+			name:         "OK",
+			rowsAffected: 1,
+		},
+		{
+			name:          "rule not found",
+			expectedError: storageerrors.ErrDomainRuleNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := newDomainTestRepo(t)
+
+			query := regexp.QuoteMeta(`DELETE FROM domain_rules WHERE pattern = $1`)
+			mock.ExpectExec(query).WithArgs("evil.com").WillReturnResult(pgxmock.NewResult("DELETE", tc.rowsAffected))
+
+			err := repo.DeleteDomainRule(context.Background(), "evil.com")
+			require.ErrorIs(t, err, tc.expectedError)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package postgresstorage
+
+import (
+	"context"
+	"fmt"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+)
+
+type SequenceRepo struct {
+	*postgres.Postgres
+}
+
+func NewSequenceRepo(db *postgres.Postgres) *SequenceRepo {
+	return &SequenceRepo{db}
+}
+
+func (r *SequenceRepo) NextAliasID(ctx context.Context) (uint64, error) {
+	sql, args, _ := r.Builder.
+		Select(fmt.Sprintf("nextval('%s')", constant.AliasSequence)).
+		ToSql()
+
+	var id int64
+	err := r.Pool.QueryRow(ctx, sql, args...).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("SequenceRepo.NextAliasID - r.Pool.QueryRow: %v", err)
+	}
+
+	return uint64(id), nil
+}
@@ -0,0 +1,37 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestSequenceRepo_NextAliasID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	db := postgres.Postgres{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		Pool:    mock,
+	}
+	repo := NewSequenceRepo(&db)
+
+	query := regexp.QuoteMeta(`SELECT nextval('alias_seq')`)
+	mock.ExpectQuery(query).WillReturnRows(pgxmock.NewRows([]string{"nextval"}).AddRow(int64(42)))
+	mock.ExpectQuery(query).WillReturnError(errors.New("connection refused"))
+
+	id, err := repo.NextAliasID(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), id)
+
+	_, err = repo.NextAliasID(context.Background())
+	require.Error(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
@@ -12,6 +12,7 @@ import (
 	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
 	"github.com/romandnk/shortener/pkg/storage/postgres"
 	"strings"
+	"time"
 )
 
 type URLRepo struct {
@@ -23,10 +24,36 @@ func NewURLRepo(db *postgres.Postgres) *URLRepo {
 }
 
 func (r *URLRepo) CreateURL(ctx context.Context, url entity.URL) error {
+	err := r.insertURL(ctx, url)
+	if !errors.Is(err, storageerrors.ErrOriginalURLExists) && !errors.Is(err, storageerrors.ErrURLAliasExists) {
+		return err
+	}
+
+	// expired urls keep their original and alias busy until they are purged
+	deleted, delErr := r.deleteExpired(ctx, squirrel.Or{
+		squirrel.Eq{"original": url.Original},
+		squirrel.Eq{"alias": url.Alias},
+	})
+	if delErr != nil {
+		return fmt.Errorf("URLRepo.CreateURL - r.deleteExpired: %w", delErr)
+	}
+	if deleted == 0 {
+		return err
+	}
+
+	return r.insertURL(ctx, url)
+}
+
+func (r *URLRepo) insertURL(ctx context.Context, url entity.URL) error {
+	var expiresAt *time.Time
+	if !url.ExpiresAt.IsZero() {
+		expiresAt = &url.ExpiresAt
+	}
+
 	sql, args, _ := r.Builder.
 		Insert(constant.URLSTable).
-		Columns("original", "alias").
-		Values(url.Original, url.Alias).
+		Columns("original", "alias", "input", "expires_at").
+		Values(url.Original, url.Alias, nullable(url.Input), expiresAt).
 		ToSql()
 
 	_, err := r.Pool.Exec(ctx, sql, args...)
@@ -48,21 +75,245 @@ func (r *URLRepo) CreateURL(ctx context.Context, url entity.URL) error {
 	return nil
 }
 
+// nullable stores empty strings as NULL.
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 func (r *URLRepo) GetOriginalByAlias(ctx context.Context, alias string) (string, error) {
+	url, err := r.GetURLByAlias(ctx, alias)
+	if err != nil {
+		return "", err
+	}
+
+	if !url.ExpiresAt.IsZero() && !url.ExpiresAt.After(time.Now()) {
+		return "", storageerrors.ErrURLExpired
+	}
+
+	return url.Original, nil
+}
+
+// GetURLByAlias returns the url with its expiration, expired urls are returned as well.
+func (r *URLRepo) GetURLByAlias(ctx context.Context, alias string) (entity.URL, error) {
 	sql, args, _ := r.Builder.
-		Select("original").
+		Select("original", "input", "expires_at").
 		From(constant.URLSTable).
 		Where(squirrel.Eq{"alias": alias}).
 		ToSql()
 
-	var original string
-	err := r.Pool.QueryRow(ctx, sql, args...).Scan(&original)
+	var (
+		original  string
+		input     *string
+		expiresAt *time.Time
+	)
+	err := r.Pool.QueryRow(ctx, sql, args...).Scan(&original, &input, &expiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.URL{}, storageerrors.ErrURLAliasNotFound
+		}
+		return entity.URL{}, fmt.Errorf("URLRepo.GetURLByAlias - r.Pool.Query: %v", err)
+	}
+
+	url := entity.URL{
+		Original: original,
+		Alias:    alias,
+	}
+	if input != nil {
+		url.Input = *input
+	}
+	if expiresAt != nil {
+		url.ExpiresAt = *expiresAt
+	}
+
+	return url, nil
+}
+
+// GetURLs returns not expired urls ordered by alias, the cursor is the last alias of the previous page.
+func (r *URLRepo) GetURLs(ctx context.Context, cursor string, limit int) ([]entity.URL, string, error) {
+	query := r.Builder.
+		Select("original", "alias", "input", "expires_at").
+		From(constant.URLSTable).
+		Where("(expires_at IS NULL OR expires_at > now())").
+		OrderBy("alias").
+		Limit(uint64(limit))
+	if cursor != "" {
+		query = query.Where(squirrel.Gt{"alias": cursor})
+	}
+
+	sql, args, _ := query.ToSql()
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("URLRepo.GetURLs - r.Pool.Query: %v", err)
+	}
+	defer rows.Close()
+
+	urls := make([]entity.URL, 0, limit)
+	for rows.Next() {
+		var (
+			url       entity.URL
+			input     *string
+			expiresAt *time.Time
+		)
+		if err = rows.Scan(&url.Original, &url.Alias, &input, &expiresAt); err != nil {
+			return nil, "", fmt.Errorf("URLRepo.GetURLs - rows.Scan: %v", err)
+		}
+		if input != nil {
+			url.Input = *input
+		}
+		if expiresAt != nil {
+			url.ExpiresAt = *expiresAt
+		}
+		urls = append(urls, url)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("URLRepo.GetURLs - rows.Err: %v", err)
+	}
+
+	var next string
+	if len(urls) == limit {
+		next = urls[len(urls)-1].Alias
+	}
+
+	return urls, next, nil
+}
+
+func (r *URLRepo) GetAliasByOriginal(ctx context.Context, original string) (string, error) {
+	sql, args, _ := r.Builder.
+		Select("alias").
+		From(constant.URLSTable).
+		Where(squirrel.Eq{"original": original}).
+		ToSql()
+
+	var alias string
+	err := r.Pool.QueryRow(ctx, sql, args...).Scan(&alias)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return original, storageerrors.ErrURLAliasNotFound
+			return alias, storageerrors.ErrOriginalNotFound
+		}
+		return alias, fmt.Errorf("URLRepo.GetAliasByOriginal - r.Pool.Query: %v", err)
+	}
+
+	return alias, nil
+}
+
+// DeleteByAlias deletes the url together with its clicks, so the alias created again starts with no stats.
+func (r *URLRepo) DeleteByAlias(ctx context.Context, alias string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("URLRepo.DeleteByAlias - r.Pool.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	sql, args, _ := r.Builder.
+		Delete(constant.URLSTable).
+		Where(squirrel.Eq{"alias": alias}).
+		ToSql()
+
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("URLRepo.DeleteByAlias - tx.Exec - url: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storageerrors.ErrURLAliasNotFound
+	}
+
+	sql, args, _ = r.Builder.
+		Delete(constant.ClicksTable).
+		Where(squirrel.Eq{"alias": alias}).
+		ToSql()
+
+	if _, err = tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("URLRepo.DeleteByAlias - tx.Exec - clicks: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("URLRepo.DeleteByAlias - tx.Commit: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateOriginal points the alias to another original url, expired aliases cannot be updated.
+func (r *URLRepo) UpdateOriginal(ctx context.Context, url entity.URL) error {
+	err := r.updateOriginal(ctx, url)
+	if !errors.Is(err, storageerrors.ErrOriginalURLExists) {
+		return err
+	}
+
+	// the original url may belong to an expired url which is not purged yet
+	deleted, delErr := r.deleteExpired(ctx, squirrel.Eq{"original": url.Original})
+	if delErr != nil {
+		return fmt.Errorf("URLRepo.UpdateOriginal - r.deleteExpired: %w", delErr)
+	}
+	if deleted == 0 {
+		return err
+	}
+
+	return r.updateOriginal(ctx, url)
+}
+
+func (r *URLRepo) updateOriginal(ctx context.Context, url entity.URL) error {
+	sql, args, _ := r.Builder.
+		Update(constant.URLSTable).
+		Set("original", url.Original).
+		Set("input", nullable(url.Input)).
+		Where(squirrel.Eq{"alias": url.Alias}).
+		Where("(expires_at IS NULL OR expires_at > now())").
+		ToSql()
+
+	tag, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return storageerrors.ErrOriginalURLExists
 		}
-		return original, fmt.Errorf("URLRepo.GetOriginalByAlias - r.Pool.Query: %v", err)
+		return fmt.Errorf("URLRepo.UpdateOriginal - r.Pool.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storageerrors.ErrURLAliasNotFound
+	}
+
+	return nil
+}
+
+// DeleteExpired removes all expired urls together with their clicks.
+func (r *URLRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	deleted, err := r.deleteExpired(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("URLRepo.DeleteExpired - r.deleteExpired: %w", err)
+	}
+	return deleted, nil
+}
+
+// deleteExpired removes expired urls matching the where condition, nil matches all urls.
+// Their clicks are deleted in the same statement, so that a reused alias does not inherit them.
+func (r *URLRepo) deleteExpired(ctx context.Context, where squirrel.Sqlizer) (int64, error) {
+	expired := squirrel.
+		Select("alias").
+		From(constant.URLSTable).
+		Where("expires_at <= now()")
+	query := r.Builder.
+		Delete(constant.URLSTable).
+		Where("expires_at <= now()")
+	if where != nil {
+		expired = expired.Where(where)
+		query = query.Where(where)
+	}
+	clicks := squirrel.
+		Delete(constant.ClicksTable).
+		Where(squirrel.Expr("alias IN (?)", expired))
+	query = query.PrefixExpr(squirrel.Expr("WITH deleted_clicks AS (?)", clicks))
+
+	sql, args, _ := query.ToSql()
+
+	tag, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
 	}
 
-	return original, nil
+	return tag.RowsAffected(), nil
 }
@@ -0,0 +1,158 @@
+package postgresstorage
+
+import (
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"time"
+)
+
+// batchTable receives urls of a batch by COPY and is dropped with the transaction.
+const batchTable = "urls_batch"
+
+// CreateURLs copies urls into a temporary table and inserts them with one statement,
+// so the batch takes a fixed number of round trips. Urls conflicting with existing
+// ones or with previous urls of the batch are skipped and get their error.
+func (r *URLRepo) CreateURLs(ctx context.Context, urls []entity.URL) ([]error, error) {
+	errs := make([]error, len(urls))
+	if len(urls) == 0 {
+		return errs, nil
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - r.Pool.Begin: %v", err)
+	}
+
+	errs, err = r.createURLs(ctx, tx, urls, errs)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - tx.Commit: %v", err)
+	}
+
+	return errs, nil
+}
+
+func (r *URLRepo) createURLs(ctx context.Context, tx pgx.Tx, urls []entity.URL, errs []error) ([]error, error) {
+	_, err := tx.Exec(ctx, "CREATE TEMP TABLE "+batchTable+
+		" (original TEXT, alias TEXT, input TEXT, expires_at TIMESTAMPTZ, position INT) ON COMMIT DROP")
+	if err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - tx.Exec - create: %v", err)
+	}
+
+	rows := make([][]any, 0, len(urls))
+	for i, url := range urls {
+		var expiresAt *time.Time
+		if !url.ExpiresAt.IsZero() {
+			expiresAt = &url.ExpiresAt
+		}
+		rows = append(rows, []any{url.Original, url.Alias, nullable(url.Input), expiresAt, i})
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{batchTable},
+		[]string{"original", "alias", "input", "expires_at", "position"}, pgx.CopyFromRows(rows))
+	if err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - tx.CopyFrom: %v", err)
+	}
+
+	// expired urls keep their original and alias busy until they are purged, their clicks are deleted with them
+	_, err = tx.Exec(ctx, "WITH expired AS (DELETE FROM "+constant.URLSTable+" u USING "+batchTable+" b"+
+		" WHERE u.expires_at <= now() AND (u.original = b.original OR u.alias = b.alias) RETURNING u.alias)"+
+		" DELETE FROM "+constant.ClicksTable+" WHERE alias IN (SELECT alias FROM expired)")
+	if err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - tx.Exec - delete expired: %v", err)
+	}
+
+	inserted, err := r.insertBatch(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicted []string
+	for i, url := range urls {
+		key := [2]string{url.Original, url.Alias}
+		if inserted[key] {
+			// the same url later in the batch is a duplicate
+			delete(inserted, key)
+			continue
+		}
+		conflicted = append(conflicted, url.Original)
+		errs[i] = storageerrors.ErrURLAliasExists
+	}
+	if len(conflicted) == 0 {
+		return errs, nil
+	}
+
+	existing, err := r.existingOriginals(ctx, tx, conflicted)
+	if err != nil {
+		return nil, err
+	}
+	for i, url := range urls {
+		if errs[i] != nil && existing[url.Original] {
+			errs[i] = storageerrors.ErrOriginalURLExists
+		}
+	}
+
+	return errs, nil
+}
+
+// insertBatch returns original and alias pairs of inserted urls.
+func (r *URLRepo) insertBatch(ctx context.Context, tx pgx.Tx) (map[[2]string]bool, error) {
+	rows, err := tx.Query(ctx, "INSERT INTO "+constant.URLSTable+" (original, alias, input, expires_at)"+
+		" SELECT original, alias, input, expires_at FROM "+batchTable+" ORDER BY position"+
+		" ON CONFLICT DO NOTHING RETURNING original, alias")
+	if err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - tx.Query - insert: %v", err)
+	}
+	defer rows.Close()
+
+	inserted := make(map[[2]string]bool)
+	for rows.Next() {
+		var original, alias string
+		if err = rows.Scan(&original, &alias); err != nil {
+			return nil, fmt.Errorf("URLRepo.CreateURLs - rows.Scan - insert: %v", err)
+		}
+		inserted[[2]string{original, alias}] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - rows.Err - insert: %v", err)
+	}
+
+	return inserted, nil
+}
+
+func (r *URLRepo) existingOriginals(ctx context.Context, tx pgx.Tx, originals []string) (map[string]bool, error) {
+	sql, args, _ := r.Builder.
+		Select("original").
+		From(constant.URLSTable).
+		Where(squirrel.Eq{"original": originals}).
+		ToSql()
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - tx.Query - originals: %v", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool, len(originals))
+	for rows.Next() {
+		var original string
+		if err = rows.Scan(&original); err != nil {
+			return nil, fmt.Errorf("URLRepo.CreateURLs - rows.Scan - originals: %v", err)
+		}
+		existing[original] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - rows.Err - originals: %v", err)
+	}
+
+	return existing, nil
+}
@@ -0,0 +1,101 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestURLRepo_CreateURLs(t *testing.T) {
+	urls := []entity.URL{
+		{Original: "https://google.com", Alias: "testtest11"},
+		{Original: "https://ya.ru", Alias: "testtest12"},
+		{Original: "https://go.dev", Alias: "testtest11"},
+		{Original: "https://google.com", Alias: "testtest13"},
+	}
+	batchColumns := []string{"original", "alias", "input", "expires_at", "position"}
+	storageErr := errors.New("connection refused")
+
+	testCases := []struct {
+		name           string
+		mockBehaviour  func(m pgxmock.PgxPoolIface, db *postgres.Postgres)
+		expectedErrs   []error
+		expectedFailed bool
+	}{
+		{
+			name: "OK",
+			mockBehaviour: func(m pgxmock.PgxPoolIface, db *postgres.Postgres) {
+				m.ExpectBegin()
+				m.ExpectExec("CREATE TEMP TABLE urls_batch").WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				m.ExpectCopyFrom(pgx.Identifier{"urls_batch"}, batchColumns).WillReturnResult(4)
+				m.ExpectExec(regexp.QuoteMeta("WITH expired AS (DELETE FROM urls u USING urls_batch b")).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+				m.ExpectQuery("INSERT INTO urls \\(original, alias, input, expires_at\\) SELECT").
+					WillReturnRows(pgxmock.NewRows([]string{"original", "alias"}).
+						AddRow("https://google.com", "testtest11"))
+
+				sql, args, _ := db.Builder.
+					Select("original").
+					From(constant.URLSTable).
+					Where(squirrel.Eq{"original": []string{"https://ya.ru", "https://go.dev", "https://google.com"}}).
+					ToSql()
+				m.ExpectQuery(regexp.QuoteMeta(sql)).
+					WithArgs(args...).
+					WillReturnRows(pgxmock.NewRows([]string{"original"}).
+						AddRow("https://ya.ru").
+						AddRow("https://google.com"))
+				m.ExpectCommit()
+			},
+			expectedErrs: []error{
+				nil,
+				storageerrors.ErrOriginalURLExists,
+				storageerrors.ErrURLAliasExists,
+				storageerrors.ErrOriginalURLExists,
+			},
+		},
+		{
+			name: "copy error",
+			mockBehaviour: func(m pgxmock.PgxPoolIface, db *postgres.Postgres) {
+				m.ExpectBegin()
+				m.ExpectExec("CREATE TEMP TABLE urls_batch").WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				m.ExpectCopyFrom(pgx.Identifier{"urls_batch"}, batchColumns).WillReturnError(storageErr)
+				m.ExpectRollback()
+			},
+			expectedFailed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			db := postgres.Postgres{
+				Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+				Pool:    mock,
+			}
+
+			tc.mockBehaviour(mock, &db)
+
+			errs, err := NewURLRepo(&db).CreateURLs(context.Background(), urls)
+			if tc.expectedFailed {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedErrs, errs)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
@@ -13,13 +13,16 @@ import (
 	"github.com/stretchr/testify/require"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestURLRepo_CreateURL(t *testing.T) {
 	type input struct {
-		sql   string
-		args  []any
-		error *pgconn.PgError
+		sql        string
+		args       []any
+		error      *pgconn.PgError
+		deleteSQL  string
+		deleteArgs []any
 	}
 
 	type mockBehaviour func(m pgxmock.PgxPoolIface, input input)
@@ -34,8 +37,9 @@ func TestURLRepo_CreateURL(t *testing.T) {
 		{
 			name: "OK",
 			url: entity.URL{
-				Original: "http://test.com",
+				Original: "http://test.com/",
 				Alias:    "testtest11",
+				Input:    "HTTP://Test.com:80",
 			},
 			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
 				m.ExpectExec(regexp.QuoteMeta(input.sql)).
@@ -53,6 +57,9 @@ func TestURLRepo_CreateURL(t *testing.T) {
 				m.ExpectExec(regexp.QuoteMeta(input.sql)).
 					WithArgs(input.args...).
 					WillReturnError(input.error)
+				m.ExpectExec(regexp.QuoteMeta(input.deleteSQL)).
+					WithArgs(input.deleteArgs...).
+					WillReturnResult(pgxmock.NewResult("delete", 0))
 			},
 			expectedExecError: &pgconn.PgError{
 				Code:   "23505",
@@ -70,6 +77,9 @@ func TestURLRepo_CreateURL(t *testing.T) {
 				m.ExpectExec(regexp.QuoteMeta(input.sql)).
 					WithArgs(input.args...).
 					WillReturnError(input.error)
+				m.ExpectExec(regexp.QuoteMeta(input.deleteSQL)).
+					WithArgs(input.deleteArgs...).
+					WillReturnResult(pgxmock.NewResult("delete", 0))
 			},
 			expectedExecError: &pgconn.PgError{
 				Code:   "23505",
@@ -77,6 +87,41 @@ func TestURLRepo_CreateURL(t *testing.T) {
 			},
 			expectedError: storageerrors.ErrURLAliasExists,
 		},
+		{
+			name: "expired url is replaced",
+			url: entity.URL{
+				Original: "http://test.com",
+				Alias:    "testtest11",
+			},
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectExec(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnError(input.error)
+				m.ExpectExec(regexp.QuoteMeta(input.deleteSQL)).
+					WithArgs(input.deleteArgs...).
+					WillReturnResult(pgxmock.NewResult("delete", 1))
+				m.ExpectExec(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnResult(pgxmock.NewResult("insert", 1))
+			},
+			expectedExecError: &pgconn.PgError{
+				Code:   "23505",
+				Detail: "Key (alias)=(testtest11) already exists.",
+			},
+		},
+		{
+			name: "OK with expiration",
+			url: entity.URL{
+				Original:  "http://test.com",
+				Alias:     "testtest11",
+				ExpiresAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectExec(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnResult(pgxmock.NewResult("insert", 1))
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -91,18 +136,30 @@ func TestURLRepo_CreateURL(t *testing.T) {
 				Pool:    mock,
 			}
 
+			var expiresAt *time.Time
+			if !tc.url.ExpiresAt.IsZero() {
+				expiresAt = &tc.url.ExpiresAt
+			}
+
 			sql, args, _ := db.Builder.
 				Insert(constant.URLSTable).
-				Columns("original", "alias").
-				Values(tc.url.Original, tc.url.Alias).
+				Columns("original", "alias", "input", "expires_at").
+				Values(tc.url.Original, tc.url.Alias, nullable(tc.url.Input), expiresAt).
 				ToSql()
 
+			deleteSQL, deleteArgs := deleteExpiredSQL(db, squirrel.Or{
+				squirrel.Eq{"original": tc.url.Original},
+				squirrel.Eq{"alias": tc.url.Alias},
+			})
+
 			ctx := context.Background()
 
 			in := input{
-				sql:   sql,
-				args:  args,
-				error: tc.expectedExecError,
+				sql:        sql,
+				args:       args,
+				error:      tc.expectedExecError,
+				deleteSQL:  deleteSQL,
+				deleteArgs: deleteArgs,
 			}
 
 			tc.mockBehaviour(mock, in)
@@ -139,7 +196,19 @@ func TestURLRepo_GetOriginalByAlias(t *testing.T) {
 		{
 			name:       "OK",
 			inputAlias: "testtest11",
-			rows:       pgxmock.NewRows([]string{"original"}).AddRow("http://google.com/"),
+			rows:       pgxmock.NewRows([]string{"original", "input", "expires_at"}).AddRow("http://google.com/", nil, nil),
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectQuery(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnRows(input.rows)
+			},
+			expectedOriginal: "http://google.com/",
+		},
+		{
+			name:       "OK not expired yet",
+			inputAlias: "testtest11",
+			rows: pgxmock.NewRows([]string{"original", "input", "expires_at"}).
+				AddRow("http://google.com/", nil, ptr(time.Now().Add(time.Hour))),
 			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
 				m.ExpectQuery(regexp.QuoteMeta(input.sql)).
 					WithArgs(input.args...).
@@ -147,10 +216,21 @@ func TestURLRepo_GetOriginalByAlias(t *testing.T) {
 			},
 			expectedOriginal: "http://google.com/",
 		},
+		{
+			name:       "alias is expired",
+			inputAlias: "testtest11",
+			rows: pgxmock.NewRows([]string{"original", "input", "expires_at"}).
+				AddRow("http://google.com/", nil, ptr(time.Now().Add(-time.Hour))),
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectQuery(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnRows(input.rows)
+			},
+			expectedError: storageerrors.ErrURLExpired,
+		},
 		{
 			name:       "alias is not found",
 			inputAlias: "testtest11",
-			rows:       pgxmock.NewRows([]string{"original"}).AddRow("http://google.com/"),
 			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
 				m.ExpectQuery(regexp.QuoteMeta(input.sql)).
 					WithArgs(input.args...).
@@ -174,7 +254,7 @@ func TestURLRepo_GetOriginalByAlias(t *testing.T) {
 			}
 
 			sql, args, _ := db.Builder.
-				Select("original").
+				Select("original", "input", "expires_at").
 				From(constant.URLSTable).
 				Where(squirrel.Eq{"alias": tc.inputAlias}).
 				ToSql()
@@ -200,3 +280,387 @@ func TestURLRepo_GetOriginalByAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestURLRepo_GetURLByAlias(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	db := postgres.Postgres{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		Pool:    mock,
+	}
+
+	sql, args, _ := db.Builder.
+		Select("original", "input", "expires_at").
+		From(constant.URLSTable).
+		Where(squirrel.Eq{"alias": "testtest11"}).
+		ToSql()
+
+	expiresAt := time.Now().Add(-time.Hour)
+	mock.ExpectQuery(regexp.QuoteMeta(sql)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"original", "input", "expires_at"}).
+			AddRow("http://google.com/", ptr("HTTP://Google.com"), &expiresAt))
+	mock.ExpectQuery(regexp.QuoteMeta(sql)).
+		WithArgs(args...).
+		WillReturnError(pgx.ErrNoRows)
+
+	urlStorage := NewURLRepo(&db)
+
+	// expired urls are returned with their expiration
+	url, err := urlStorage.GetURLByAlias(context.Background(), "testtest11")
+	require.NoError(t, err)
+	require.Equal(t, entity.URL{
+		Original:  "http://google.com/",
+		Alias:     "testtest11",
+		Input:     "HTTP://Google.com",
+		ExpiresAt: expiresAt,
+	}, url)
+
+	_, err = urlStorage.GetURLByAlias(context.Background(), "testtest11")
+	require.ErrorIs(t, err, storageerrors.ErrURLAliasNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+func TestURLRepo_GetURLs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	db := postgres.Postgres{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		Pool:    mock,
+	}
+
+	query := db.Builder.
+		Select("original", "alias", "input", "expires_at").
+		From(constant.URLSTable).
+		Where("(expires_at IS NULL OR expires_at > now())").
+		OrderBy("alias").
+		Limit(2)
+	firstSQL, _, _ := query.ToSql()
+	nextSQL, nextArgs, _ := query.Where(squirrel.Gt{"alias": "testtest12"}).ToSql()
+
+	expiresAt := time.Now().Add(time.Hour)
+	mock.ExpectQuery(regexp.QuoteMeta(firstSQL)).
+		WillReturnRows(pgxmock.NewRows([]string{"original", "alias", "input", "expires_at"}).
+			AddRow("http://google.com/", "testtest11", ptr("HTTP://Google.com"), nil).
+			AddRow("http://ya.ru/", "testtest12", nil, &expiresAt))
+	mock.ExpectQuery(regexp.QuoteMeta(nextSQL)).
+		WithArgs(nextArgs...).
+		WillReturnRows(pgxmock.NewRows([]string{"original", "alias", "input", "expires_at"}).
+			AddRow("http://go.dev/", "testtest13", nil, nil))
+
+	urlStorage := NewURLRepo(&db)
+
+	urls, next, err := urlStorage.GetURLs(context.Background(), "", 2)
+	require.NoError(t, err)
+	require.Equal(t, []entity.URL{
+		{Original: "http://google.com/", Alias: "testtest11", Input: "HTTP://Google.com"},
+		{Original: "http://ya.ru/", Alias: "testtest12", ExpiresAt: expiresAt},
+	}, urls)
+	require.Equal(t, "testtest12", next)
+
+	urls, next, err = urlStorage.GetURLs(context.Background(), next, 2)
+	require.NoError(t, err)
+	require.Equal(t, []entity.URL{{Original: "http://go.dev/", Alias: "testtest13"}}, urls)
+	require.Empty(t, next)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+func TestURLRepo_GetAliasByOriginal(t *testing.T) {
+	type input struct {
+		sql           string
+		args          []any
+		rows          *pgxmock.Rows
+		expectedError error
+	}
+
+	type mockBehaviour func(m pgxmock.PgxPoolIface, input input)
+
+	testCases := []struct {
+		name               string
+		inputOriginal      string
+		rows               *pgxmock.Rows
+		mockBehaviour      mockBehaviour
+		expectedAlias      string
+		expectedQueryError error
+		expectedError      error
+	}{
+		{
+			name:          "OK",
+			inputOriginal: "http://google.com/",
+			rows:          pgxmock.NewRows([]string{"alias"}).AddRow("testtest11"),
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectQuery(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnRows(input.rows)
+			},
+			expectedAlias: "testtest11",
+		},
+		{
+			name:          "original url is not found",
+			inputOriginal: "http://google.com/",
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectQuery(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnError(input.expectedError)
+			},
+			expectedQueryError: pgx.ErrNoRows,
+			expectedError:      storageerrors.ErrOriginalNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			db := postgres.Postgres{
+				Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+				Pool:    mock,
+			}
+
+			sql, args, _ := db.Builder.
+				Select("alias").
+				From(constant.URLSTable).
+				Where(squirrel.Eq{"original": tc.inputOriginal}).
+				ToSql()
+
+			ctx := context.Background()
+
+			in := input{
+				sql:           sql,
+				args:          args,
+				rows:          tc.rows,
+				expectedError: tc.expectedQueryError,
+			}
+
+			tc.mockBehaviour(mock, in)
+
+			urlStorage := NewURLRepo(&db)
+
+			alias, err := urlStorage.GetAliasByOriginal(ctx, tc.inputOriginal)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedAlias, alias)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
+
+func TestURLRepo_DeleteExpired(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	db := postgres.Postgres{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		Pool:    mock,
+	}
+
+	sql, _ := deleteExpiredSQL(db, nil)
+
+	mock.ExpectExec(regexp.QuoteMeta(sql)).
+		WillReturnResult(pgxmock.NewResult("delete", 3))
+
+	urlStorage := NewURLRepo(&db)
+
+	deleted, err := urlStorage.DeleteExpired(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+// deleteExpiredSQL builds the statement deleting expired urls matching where with their clicks.
+func deleteExpiredSQL(db postgres.Postgres, where squirrel.Sqlizer) (string, []any) {
+	expired := squirrel.Select("alias").From(constant.URLSTable).Where("expires_at <= now()")
+	query := db.Builder.Delete(constant.URLSTable).Where("expires_at <= now()")
+	if where != nil {
+		expired = expired.Where(where)
+		query = query.Where(where)
+	}
+	clicks := squirrel.Delete(constant.ClicksTable).Where(squirrel.Expr("alias IN (?)", expired))
+
+	sql, args, _ := query.PrefixExpr(squirrel.Expr("WITH deleted_clicks AS (?)", clicks)).ToSql()
+	return sql, args
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestURLRepo_DeleteByAlias(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rowsAffected  int64
+		expectedError error
+	}{
+		{
+			name:         "OK",
+			rowsAffected: 1,
+		},
+		{
+			name:          "alias is not found",
+			expectedError: storageerrors.ErrURLAliasNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			db := postgres.Postgres{
+				Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+				Pool:    mock,
+			}
+
+			sql, args, _ := db.Builder.
+				Delete(constant.URLSTable).
+				Where(squirrel.Eq{"alias": "testtest11"}).
+				ToSql()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(sql)).
+				WithArgs(args...).
+				WillReturnResult(pgxmock.NewResult("delete", tc.rowsAffected))
+			if tc.rowsAffected > 0 {
+				// clicks of the deleted url are deleted in the same transaction
+				sql, args, _ = db.Builder.
+					Delete(constant.ClicksTable).
+					Where(squirrel.Eq{"alias": "testtest11"}).
+					ToSql()
+				mock.ExpectExec(regexp.QuoteMeta(sql)).
+					WithArgs(args...).
+					WillReturnResult(pgxmock.NewResult("delete", 3))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			urlStorage := NewURLRepo(&db)
+
+			err = urlStorage.DeleteByAlias(context.Background(), "testtest11")
+			require.ErrorIs(t, err, tc.expectedError)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
+
+func TestURLRepo_UpdateOriginal(t *testing.T) {
+	type input struct {
+		sql        string
+		args       []any
+		deleteSQL  string
+		deleteArgs []any
+	}
+
+	type mockBehaviour func(m pgxmock.PgxPoolIface, input input)
+
+	uniqueViolation := &pgconn.PgError{
+		Code:   "23505",
+		Detail: "Key (original)=(http://test2.com) already exists.",
+	}
+
+	testCases := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectExec(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnResult(pgxmock.NewResult("update", 1))
+			},
+		},
+		{
+			name: "alias is not found",
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectExec(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnResult(pgxmock.NewResult("update", 0))
+			},
+			expectedError: storageerrors.ErrURLAliasNotFound,
+		},
+		{
+			name: "original url already exists",
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectExec(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnError(uniqueViolation)
+				m.ExpectExec(regexp.QuoteMeta(input.deleteSQL)).
+					WithArgs(input.deleteArgs...).
+					WillReturnResult(pgxmock.NewResult("delete", 0))
+			},
+			expectedError: storageerrors.ErrOriginalURLExists,
+		},
+		{
+			name: "original url of expired url is reused",
+			mockBehaviour: func(m pgxmock.PgxPoolIface, input input) {
+				m.ExpectExec(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnError(uniqueViolation)
+				m.ExpectExec(regexp.QuoteMeta(input.deleteSQL)).
+					WithArgs(input.deleteArgs...).
+					WillReturnResult(pgxmock.NewResult("delete", 1))
+				m.ExpectExec(regexp.QuoteMeta(input.sql)).
+					WithArgs(input.args...).
+					WillReturnResult(pgxmock.NewResult("update", 1))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			db := postgres.Postgres{
+				Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+				Pool:    mock,
+			}
+
+			sql, args, _ := db.Builder.
+				Update(constant.URLSTable).
+				Set("original", "http://test2.com/").
+				Set("input", ptr("HTTP://test2.com")).
+				Where(squirrel.Eq{"alias": "testtest11"}).
+				Where("(expires_at IS NULL OR expires_at > now())").
+				ToSql()
+
+			deleteSQL, deleteArgs := deleteExpiredSQL(db, squirrel.Eq{"original": "http://test2.com/"})
+
+			tc.mockBehaviour(mock, input{
+				sql:        sql,
+				args:       args,
+				deleteSQL:  deleteSQL,
+				deleteArgs: deleteArgs,
+			})
+
+			urlStorage := NewURLRepo(&db)
+
+			err = urlStorage.UpdateOriginal(context.Background(), entity.URL{
+				Original: "http://test2.com/",
+				Alias:    "testtest11",
+				Input:    "HTTP://test2.com",
+			})
+			require.ErrorIs(t, err, tc.expectedError)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package redisstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"sort"
+	"time"
+)
+
+// APIKeyRepo keeps keys as json in a hash by name
+// and names in a hash by hash of the key.
+type APIKeyRepo struct {
+	*redisdb.Redis
+}
+
+func NewAPIKeyRepo(client *redisdb.Redis) *APIKeyRepo {
+	return &APIKeyRepo{client}
+}
+
+type apiKey struct {
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *APIKeyRepo) CreateAPIKey(ctx context.Context, key entity.APIKey) error {
+	value, err := json.Marshal(apiKey(key))
+	if err != nil {
+		return fmt.Errorf("APIKeyRepo.CreateAPIKey - json.Marshal: %v", err)
+	}
+
+	err = r.Client.Watch(ctx, func(tx *redis.Tx) error {
+		nameExists, err := tx.HExists(ctx, constant.APIKeysByNameKey, key.Name).Result()
+		if err != nil {
+			return fmt.Errorf("APIKeyRepo.CreateAPIKey - tx.HExists: %v", err)
+		}
+		hashExists, err := tx.HExists(ctx, constant.APIKeysByHashKey, key.Hash).Result()
+		if err != nil {
+			return fmt.Errorf("APIKeyRepo.CreateAPIKey - tx.HExists: %v", err)
+		}
+		if nameExists || hashExists {
+			return storageerrors.ErrAPIKeyExists
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, constant.APIKeysByNameKey, key.Name, value)
+			pipe.HSet(ctx, constant.APIKeysByHashKey, key.Hash, key.Name)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("APIKeyRepo.CreateAPIKey - tx.TxPipelined: %v", err)
+		}
+
+		return nil
+	}, constant.APIKeysByNameKey, constant.APIKeysByHashKey)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrAPIKeyExists) {
+			return err
+		}
+		return fmt.Errorf("APIKeyRepo.CreateAPIKey - r.Client.Watch: %v", err)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepo) GetAPIKeyByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	name, err := r.Client.HGet(ctx, constant.APIKeysByHashKey, hash).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entity.APIKey{}, storageerrors.ErrAPIKeyNotFound
+		}
+		return entity.APIKey{}, fmt.Errorf("APIKeyRepo.GetAPIKeyByHash - r.Client.HGet: %v", err)
+	}
+
+	value, err := r.Client.HGet(ctx, constant.APIKeysByNameKey, name).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entity.APIKey{}, storageerrors.ErrAPIKeyNotFound
+		}
+		return entity.APIKey{}, fmt.Errorf("APIKeyRepo.GetAPIKeyByHash - r.Client.HGet: %v", err)
+	}
+
+	var key apiKey
+	if err = json.Unmarshal([]byte(value), &key); err != nil {
+		return entity.APIKey{}, fmt.Errorf("APIKeyRepo.GetAPIKeyByHash - json.Unmarshal: %v", err)
+	}
+
+	return entity.APIKey(key), nil
+}
+
+func (r *APIKeyRepo) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	values, err := r.Client.HVals(ctx, constant.APIKeysByNameKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo.GetAPIKeys - r.Client.HVals: %v", err)
+	}
+
+	keys := make([]entity.APIKey, 0, len(values))
+	for _, value := range values {
+		var key apiKey
+		if err = json.Unmarshal([]byte(value), &key); err != nil {
+			return nil, fmt.Errorf("APIKeyRepo.GetAPIKeys - json.Unmarshal: %v", err)
+		}
+		keys = append(keys, entity.APIKey(key))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+
+	return keys, nil
+}
+
+func (r *APIKeyRepo) DeleteAPIKey(ctx context.Context, name string) error {
+	err := r.Client.Watch(ctx, func(tx *redis.Tx) error {
+		value, err := tx.HGet(ctx, constant.APIKeysByNameKey, name).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				return storageerrors.ErrAPIKeyNotFound
+			}
+			return fmt.Errorf("APIKeyRepo.DeleteAPIKey - tx.HGet: %v", err)
+		}
+
+		var key apiKey
+		if err = json.Unmarshal([]byte(value), &key); err != nil {
+			return fmt.Errorf("APIKeyRepo.DeleteAPIKey - json.Unmarshal: %v", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HDel(ctx, constant.APIKeysByNameKey, name)
+			pipe.HDel(ctx, constant.APIKeysByHashKey, key.Hash)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("APIKeyRepo.DeleteAPIKey - tx.TxPipelined: %v", err)
+		}
+
+		return nil
+	}, constant.APIKeysByNameKey)
+	if err != nil {
+		if errors.Is(err, storageerrors.ErrAPIKeyNotFound) {
+			return err
+		}
+		return fmt.Errorf("APIKeyRepo.DeleteAPIKey - r.Client.Watch: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package redisstorage
+
+import (
+	"context"
+	"github.com/go-redis/redismock/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyRepo_GetAPIKeyByHash(t *testing.T) {
+	key := entity.APIKey{
+		Name:      "ci",
+		Hash:      "hash",
+		CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		name          string
+		found         bool
+		expectedKey   entity.APIKey
+		expectedError error
+	}{
+		{
+			name:        "OK",
+			found:       true,
+			expectedKey: key,
+		},
+		{
+			name:          "key not found",
+			expectedError: storageerrors.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+
+			if tc.found {
+				mock.ExpectHGet(constant.APIKeysByHashKey, key.Hash).SetVal(key.Name)
+				mock.ExpectHGet(constant.APIKeysByNameKey, key.Name).
+					SetVal(`{"name":"ci","hash":"hash","created_at":"2030-01-01T00:00:00Z"}`)
+			} else {
+				mock.ExpectHGet(constant.APIKeysByHashKey, key.Hash).RedisNil()
+			}
+
+			actualKey, err := NewAPIKeyRepo(&redisdb.Redis{Client: db}).GetAPIKeyByHash(context.Background(), key.Hash)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedKey, actualKey)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
+
+func TestAPIKeyRepo_GetAPIKeys(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	mock.ExpectHVals(constant.APIKeysByNameKey).SetVal([]string{
+		`{"name":"ci","hash":"hash2","created_at":"2030-01-01T00:00:00Z"}`,
+		`{"name":"admin","hash":"hash1","created_at":"2030-01-01T00:00:00Z"}`,
+	})
+
+	createdAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys, err := NewAPIKeyRepo(&redisdb.Redis{Client: db}).GetAPIKeys(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []entity.APIKey{
+		{Name: "admin", Hash: "hash1", CreatedAt: createdAt},
+		{Name: "ci", Hash: "hash2", CreatedAt: createdAt},
+	}, keys)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+func TestAPIKeyRepo_DeleteAPIKey(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	mock.ExpectWatch(constant.APIKeysByNameKey)
+	mock.ExpectHGet(constant.APIKeysByNameKey, "ci").RedisNil()
+
+	err := NewAPIKeyRepo(&redisdb.Redis{Client: db}).DeleteAPIKey(context.Background(), "ci")
+	require.ErrorIs(t, err, storageerrors.ErrAPIKeyNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
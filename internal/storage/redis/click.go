@@ -0,0 +1,82 @@
+package redisstorage
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ClickRepo keeps per-day click counters of each alias in a hash
+// and click details in a capped stream.
+type ClickRepo struct {
+	*redisdb.Redis
+}
+
+func NewClickRepo(client *redisdb.Redis) *ClickRepo {
+	return &ClickRepo{client}
+}
+
+func clicksKey(alias string) string {
+	return constant.ClicksKeyPrefix + alias
+}
+
+func (r *ClickRepo) CreateClicks(ctx context.Context, clicks []entity.Click) error {
+	_, err := r.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, click := range clicks {
+			pipe.HIncrBy(ctx, clicksKey(click.Alias), click.ClickedAt.UTC().Format(constant.DayLayout), 1)
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: constant.ClicksStream,
+				MaxLen: constant.ClicksStreamMaxLen,
+				Approx: true,
+				Values: []string{
+					"alias", click.Alias,
+					"clicked_at", click.ClickedAt.UTC().Format(time.RFC3339Nano),
+					"referrer", click.Referrer,
+					"user_agent", click.UserAgent,
+					"ip_hash", click.IPHash,
+				},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ClickRepo.CreateClicks - r.Client.Pipelined: %v", err)
+	}
+
+	return nil
+}
+
+func (r *ClickRepo) GetClickStats(ctx context.Context, alias string) (entity.ClickStats, error) {
+	var stats entity.ClickStats
+
+	counters, err := r.Client.HGetAll(ctx, clicksKey(alias)).Result()
+	if err != nil {
+		return stats, fmt.Errorf("ClickRepo.GetClickStats - r.Client.HGetAll: %v", err)
+	}
+
+	for day, value := range counters {
+		date, err := time.Parse(constant.DayLayout, day)
+		if err != nil {
+			return stats, fmt.Errorf("ClickRepo.GetClickStats - time.Parse: %v", err)
+		}
+		clicks, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return stats, fmt.Errorf("ClickRepo.GetClickStats - strconv.ParseInt: %v", err)
+		}
+
+		stats.Total += clicks
+		stats.Daily = append(stats.Daily, entity.DailyClicks{Day: date, Clicks: clicks})
+	}
+
+	sort.Slice(stats.Daily, func(i, j int) bool {
+		return stats.Daily[i].Day.Before(stats.Daily[j].Day)
+	})
+
+	return stats, nil
+}
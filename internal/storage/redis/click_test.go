@@ -0,0 +1,110 @@
+package redisstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestClickRepo_CreateClicks(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	clickedAt := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	click := entity.Click{
+		Alias:     "testtest11",
+		ClickedAt: clickedAt,
+		Referrer:  "http://ref.com",
+		UserAgent: "curl",
+		IPHash:    "hash",
+	}
+
+	mock.ExpectHIncrBy("clicks:testtest11", "2030-01-01", 1).SetVal(1)
+	mock.ExpectXAdd(&redis.XAddArgs{
+		Stream: constant.ClicksStream,
+		MaxLen: constant.ClicksStreamMaxLen,
+		Approx: true,
+		Values: []string{
+			"alias", "testtest11",
+			"clicked_at", "2030-01-01T10:00:00Z",
+			"referrer", "http://ref.com",
+			"user_agent", "curl",
+			"ip_hash", "hash",
+		},
+	}).SetVal("1-0")
+
+	clickStorage := NewClickRepo(&redisdb.Redis{Client: db})
+
+	err := clickStorage.CreateClicks(context.Background(), []entity.Click{click})
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+func TestClickRepo_GetClickStats(t *testing.T) {
+	testCases := []struct {
+		name          string
+		counters      map[string]string
+		error         error
+		expectedStats entity.ClickStats
+		expectedError bool
+	}{
+		{
+			name: "OK",
+			counters: map[string]string{
+				"2030-01-03": "2",
+				"2030-01-01": "3",
+			},
+			expectedStats: entity.ClickStats{
+				Total: 5,
+				Daily: []entity.DailyClicks{
+					{Day: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Clicks: 3},
+					{Day: time.Date(2030, 1, 3, 0, 0, 0, 0, time.UTC), Clicks: 2},
+				},
+			},
+		},
+		{
+			name:     "no clicks",
+			counters: map[string]string{},
+		},
+		{
+			name:          "redis error",
+			error:         errors.New("connection refused"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+
+			expect := mock.ExpectHGetAll("clicks:testtest11")
+			if tc.error != nil {
+				expect.SetErr(tc.error)
+			} else {
+				expect.SetVal(tc.counters)
+			}
+
+			clickStorage := NewClickRepo(&redisdb.Redis{Client: db})
+
+			stats, err := clickStorage.GetClickStats(context.Background(), "testtest11")
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedStats, stats)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package redisstorage
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"sort"
+)
+
+// DomainRepo keeps patterns in one set per list.
+type DomainRepo struct {
+	*redisdb.Redis
+}
+
+func NewDomainRepo(client *redisdb.Redis) *DomainRepo {
+	return &DomainRepo{client}
+}
+
+// domainLists are lists of rules in order of reading with sets of their patterns.
+var domainLists = []struct {
+	list entity.DomainList
+	key  string
+}{
+	{list: entity.DomainBlock, key: constant.DomainBlockKey},
+	{list: entity.DomainAllow, key: constant.DomainAllowKey},
+}
+
+func (r *DomainRepo) SaveDomainRule(ctx context.Context, rule entity.DomainRule) error {
+	var key, other string
+	switch rule.List {
+	case entity.DomainBlock:
+		key, other = constant.DomainBlockKey, constant.DomainAllowKey
+	case entity.DomainAllow:
+		key, other = constant.DomainAllowKey, constant.DomainBlockKey
+	default:
+		return fmt.Errorf("DomainRepo.SaveDomainRule: unknown list %q", rule.List)
+	}
+
+	// the pattern is moved from the other list in one transaction
+	_, err := r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SRem(ctx, other, rule.Pattern)
+		pipe.SAdd(ctx, key, rule.Pattern)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("DomainRepo.SaveDomainRule - r.Client.TxPipelined: %v", err)
+	}
+
+	return nil
+}
+
+func (r *DomainRepo) GetDomainRules(ctx context.Context) ([]entity.DomainRule, error) {
+	var rules []entity.DomainRule
+	for _, domainList := range domainLists {
+		patterns, err := r.Client.SMembers(ctx, domainList.key).Result()
+		if err != nil {
+			return nil, fmt.Errorf("DomainRepo.GetDomainRules - r.Client.SMembers: %v", err)
+		}
+		for _, pattern := range patterns {
+			rules = append(rules, entity.DomainRule{Pattern: pattern, List: domainList.list})
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Pattern < rules[j].Pattern
+	})
+
+	return rules, nil
+}
+
+func (r *DomainRepo) DeleteDomainRule(ctx context.Context, pattern string) error {
+	removed := make([]*redis.IntCmd, 0, len(domainLists))
+	_, err := r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, domainList := range domainLists {
+			removed = append(removed, pipe.SRem(ctx, domainList.key, pattern))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("DomainRepo.DeleteDomainRule - r.Client.TxPipelined: %v", err)
+	}
+
+	for _, cmd := range removed {
+		if cmd.Val() > 0 {
+			return nil
+		}
+	}
+
+	return storageerrors.ErrDomainRuleNotFound
+}
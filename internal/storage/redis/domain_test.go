@@ -0,0 +1,111 @@
+package redisstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redismock/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDomainRepo_SaveDomainRule(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	mock.ExpectTxPipeline()
+	mock.ExpectSRem(constant.DomainBlockKey, "safe.evil.com").SetVal(1)
+	mock.ExpectSAdd(constant.DomainAllowKey, "safe.evil.com").SetVal(1)
+	mock.ExpectTxPipelineExec()
+
+	repo := NewDomainRepo(&redisdb.Redis{Client: db})
+	err := repo.SaveDomainRule(context.Background(), entity.DomainRule{Pattern: "safe.evil.com", List: entity.DomainAllow})
+	require.NoError(t, err)
+
+	err = repo.SaveDomainRule(context.Background(), entity.DomainRule{Pattern: "evil.com", List: "deny"})
+	require.Error(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+func TestDomainRepo_GetDomainRules(t *testing.T) {
+	testCases := []struct {
+		name          string
+		error         error
+		expectedRules []entity.DomainRule
+		expectError   bool
+	}{
+		{
+			name: "OK",
+			expectedRules: []entity.DomainRule{
+				{Pattern: "*.evil.com", List: entity.DomainBlock},
+				{Pattern: "phishing.org", List: entity.DomainBlock},
+				{Pattern: "safe.evil.com", List: entity.DomainAllow},
+			},
+		},
+		{
+			name:        "redis error",
+			error:       errors.New("connection refused"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+
+			if tc.error != nil {
+				mock.ExpectSMembers(constant.DomainBlockKey).SetErr(tc.error)
+			} else {
+				mock.ExpectSMembers(constant.DomainBlockKey).SetVal([]string{"phishing.org", "*.evil.com"})
+				mock.ExpectSMembers(constant.DomainAllowKey).SetVal([]string{"safe.evil.com"})
+			}
+
+			rules, err := NewDomainRepo(&redisdb.Redis{Client: db}).GetDomainRules(context.Background())
+			require.Equal(t, tc.expectError, err != nil)
+			require.Equal(t, tc.expectedRules, rules)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
+
+func TestDomainRepo_DeleteDomainRule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		removed       int64
+		expectedError error
+	}{
+		{
+			name:    "OK",
+			removed: 1,
+		},
+		{
+			name:          "rule not found",
+			expectedError: storageerrors.ErrDomainRuleNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+
+			mock.ExpectTxPipeline()
+			mock.ExpectSRem(constant.DomainBlockKey, "evil.com").SetVal(0)
+			mock.ExpectSRem(constant.DomainAllowKey, "evil.com").SetVal(tc.removed)
+			mock.ExpectTxPipelineExec()
+
+			err := NewDomainRepo(&redisdb.Redis{Client: db}).DeleteDomainRule(context.Background(), "evil.com")
+			require.ErrorIs(t, err, tc.expectedError)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package redisstorage
+
+import (
+	"context"
+	"fmt"
+	"github.com/romandnk/shortener/internal/constant"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+)
+
+type SequenceRepo struct {
+	*redisdb.Redis
+}
+
+func NewSequenceRepo(client *redisdb.Redis) *SequenceRepo {
+	return &SequenceRepo{client}
+}
+
+func (r *SequenceRepo) NextAliasID(ctx context.Context) (uint64, error) {
+	id, err := r.Client.Incr(ctx, constant.AliasSequence).Uint64()
+	if err != nil {
+		return 0, fmt.Errorf("SequenceRepo.NextAliasID - r.Client.Incr: %v", err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,30 @@
+package redisstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redismock/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSequenceRepo_NextAliasID(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	mock.ExpectIncr(constant.AliasSequence).SetVal(42)
+	mock.ExpectIncr(constant.AliasSequence).SetErr(errors.New("connection refused"))
+
+	repo := NewSequenceRepo(&redisdb.Redis{Client: db})
+
+	id, err := repo.NextAliasID(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), id)
+
+	_, err = repo.NextAliasID(context.Background())
+	require.Error(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/romandnk/shortener/internal/entity"
 	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
 	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type URLRepo struct {
@@ -19,43 +22,234 @@ func NewURLRepo(client *redisdb.Redis) *URLRepo {
 	return &URLRepo{client}
 }
 
+const (
+	expiryKeyPrefix = "expiry:"
+	inputKeyPrefix  = "input:"
+)
+
+// expiryKey keeps expiration time of alias after the alias itself expires,
+// so that expired aliases can be told apart from unknown ones.
+func expiryKey(alias string) string {
+	return expiryKeyPrefix + alias
+}
+
+// inputKey keeps the url as it was submitted if it differs from the stored original, it expires with the alias.
+func inputKey(alias string) string {
+	return inputKeyPrefix + alias
+}
+
+// CreateURL runs the create script, so that nothing is left behind if either the original or the alias exists.
 func (r *URLRepo) CreateURL(ctx context.Context, url entity.URL) error {
-	err := r.Client.Watch(ctx, func(tx *redis.Tx) error {
-		originExists, err := tx.SetNX(ctx, url.Original, url.Alias, constant.ZeroTTL).Result()
+	res, err := createURL.Run(ctx, r.Client, createURLKeys(url), createURLArgs(url)...).Int()
+	if err != nil {
+		return fmt.Errorf("URLRepo.CreateURL - createURL.Run: %v", err)
+	}
+
+	switch res {
+	case originalExists:
+		return storageerrors.ErrOriginalURLExists
+	case aliasExists:
+		return storageerrors.ErrURLAliasExists
+	}
+
+	return nil
+}
+
+func (r *URLRepo) GetOriginalByAlias(ctx context.Context, alias string) (string, error) {
+	original, err := r.Client.Get(ctx, alias).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", r.missingAliasError(ctx, alias)
+		}
+		return "", fmt.Errorf("URLRepo.GetOriginalByAlias - r.client.Get: %v", err)
+	}
+	return original, nil
+}
+
+func (r *URLRepo) missingAliasError(ctx context.Context, alias string) error {
+	expired, err := r.Client.Exists(ctx, expiryKey(alias)).Result()
+	if err != nil {
+		return fmt.Errorf("URLRepo.GetOriginalByAlias - r.client.Exists: %v", err)
+	}
+	if expired > 0 {
+		return storageerrors.ErrURLExpired
+	}
+	return storageerrors.ErrURLAliasNotFound
+}
+
+// GetURLs scans string keys for aliases, the cursor is the scan cursor of redis.
+// The limit is a hint of the scan, so pages may have any number of urls.
+func (r *URLRepo) GetURLs(ctx context.Context, cursor string, limit int) ([]entity.URL, string, error) {
+	var scanCursor uint64
+	if cursor != "" {
+		var err error
+		scanCursor, err = strconv.ParseUint(cursor, 10, 64)
 		if err != nil {
-			return fmt.Errorf("URLRepo.CreateURL - tx.SetNX - 1: %v", err)
+			return nil, "", fmt.Errorf("URLRepo.GetURLs - invalid cursor %q: %v", cursor, err)
 		}
-		if !originExists {
-			return storageerrors.ErrOriginalURLExists
+	}
+
+	keys, nextCursor, err := r.Client.ScanType(ctx, scanCursor, "", int64(limit), "string").Result()
+	if err != nil {
+		return nil, "", fmt.Errorf("URLRepo.GetURLs - r.Client.ScanType: %v", err)
+	}
+
+	var next string
+	if nextCursor != 0 {
+		next = strconv.FormatUint(nextCursor, 10)
+	}
+
+	// original urls are keys of their aliases as well, they are told apart by the scheme
+	aliases := make([]string, 0, len(keys))
+	expiryKeys := make([]string, 0, len(keys))
+	inputKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.Contains(key, "://") ||
+			strings.HasPrefix(key, expiryKeyPrefix) ||
+			strings.HasPrefix(key, inputKeyPrefix) ||
+			strings.HasPrefix(key, constant.URLCacheKeyPrefix) {
+			continue
 		}
+		aliases = append(aliases, key)
+		expiryKeys = append(expiryKeys, expiryKey(key))
+		inputKeys = append(inputKeys, inputKey(key))
+	}
+	if len(aliases) == 0 {
+		return nil, next, nil
+	}
+
+	var originals, expiries, inputs *redis.SliceCmd
+	_, err = r.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		originals = pipe.MGet(ctx, aliases...)
+		expiries = pipe.MGet(ctx, expiryKeys...)
+		inputs = pipe.MGet(ctx, inputKeys...)
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("URLRepo.GetURLs - r.Client.Pipelined: %v", err)
+	}
 
-		aliasExists, err := tx.SetNX(ctx, url.Alias, url.Original, constant.ZeroTTL).Result()
+	urls := make([]entity.URL, 0, len(aliases))
+	for i, value := range originals.Val() {
+		// the alias expired or was deleted since the scan
+		original, ok := value.(string)
+		if !ok || !strings.Contains(original, "://") {
+			continue
+		}
+
+		url := entity.URL{Original: original, Alias: aliases[i]}
+		if input, ok := inputs.Val()[i].(string); ok {
+			url.Input = input
+		}
+		if expiry, ok := expiries.Val()[i].(string); ok {
+			url.ExpiresAt, err = time.Parse(time.RFC3339, expiry)
+			if err != nil {
+				return nil, "", fmt.Errorf("URLRepo.GetURLs - time.Parse: %v", err)
+			}
+		}
+		urls = append(urls, url)
+	}
+
+	return urls, next, nil
+}
+
+func (r *URLRepo) GetAliasByOriginal(ctx context.Context, original string) (string, error) {
+	alias, err := r.Client.Get(ctx, original).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", storageerrors.ErrOriginalNotFound
+		}
+		return "", fmt.Errorf("URLRepo.GetAliasByOriginal - r.client.Get: %v", err)
+	}
+	return alias, nil
+}
+
+// DeleteByAlias deletes the url together with its click counters, so the alias created again starts with no stats.
+func (r *URLRepo) DeleteByAlias(ctx context.Context, alias string) error {
+	err := r.Client.Watch(ctx, func(tx *redis.Tx) error {
+		original, err := tx.Get(ctx, alias).Result()
 		if err != nil {
-			return fmt.Errorf("URLRepo.CreateURL - tx.SetNX - 2: %v", err)
+			if errors.Is(err, redis.Nil) {
+				return storageerrors.ErrURLAliasNotFound
+			}
+			return fmt.Errorf("URLRepo.DeleteByAlias - tx.Get: %v", err)
 		}
-		if !aliasExists {
-			return storageerrors.ErrURLAliasExists
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, alias, original, expiryKey(alias), inputKey(alias), clicksKey(alias))
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("URLRepo.DeleteByAlias - tx.TxPipelined: %v", err)
 		}
 
 		return nil
-	})
+	}, alias)
 	if err != nil {
-		if errors.Is(err, storageerrors.ErrOriginalURLExists) || errors.Is(err, storageerrors.ErrURLAliasExists) {
+		if errors.Is(err, storageerrors.ErrURLAliasNotFound) {
 			return err
 		}
-		return fmt.Errorf("URLRepo.CreateURL - r.client.Watch: %v", err)
+		return fmt.Errorf("URLRepo.DeleteByAlias - r.client.Watch: %v", err)
 	}
 
 	return nil
 }
 
-func (r *URLRepo) GetOriginalByAlias(ctx context.Context, alias string) (string, error) {
-	original, err := r.Client.Get(ctx, alias).Result()
+// UpdateOriginal points the alias to another original url keeping expiration of the alias.
+// The transaction fails if the alias or the new original url are changed concurrently.
+func (r *URLRepo) UpdateOriginal(ctx context.Context, url entity.URL) error {
+	alias, original := url.Alias, url.Original
+	err := r.Client.Watch(ctx, func(tx *redis.Tx) error {
+		oldOriginal, err := tx.Get(ctx, alias).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				return storageerrors.ErrURLAliasNotFound
+			}
+			return fmt.Errorf("URLRepo.UpdateOriginal - tx.Get: %v", err)
+		}
+		if oldOriginal == original {
+			return nil
+		}
+
+		exists, err := tx.Exists(ctx, original).Result()
+		if err != nil {
+			return fmt.Errorf("URLRepo.UpdateOriginal - tx.Exists: %v", err)
+		}
+		if exists > 0 {
+			return storageerrors.ErrOriginalURLExists
+		}
+
+		ttl, err := tx.PTTL(ctx, alias).Result()
+		if err != nil {
+			return fmt.Errorf("URLRepo.UpdateOriginal - tx.PTTL: %v", err)
+		}
+		if ttl < 0 {
+			ttl = constant.ZeroTTL
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, oldOriginal)
+			pipe.Set(ctx, original, alias, ttl)
+			pipe.Set(ctx, alias, original, redis.KeepTTL)
+			if url.Input != "" {
+				pipe.Set(ctx, inputKey(alias), url.Input, ttl)
+			} else {
+				pipe.Del(ctx, inputKey(alias))
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("URLRepo.UpdateOriginal - tx.TxPipelined: %v", err)
+		}
+
+		return nil
+	}, alias, original)
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return "", storageerrors.ErrURLAliasNotFound
+		if errors.Is(err, storageerrors.ErrURLAliasNotFound) || errors.Is(err, storageerrors.ErrOriginalURLExists) {
+			return err
 		}
-		return "", fmt.Errorf("URLRepo.GetOriginalByAlias - r.client.Get: %v", err)
+		return fmt.Errorf("URLRepo.UpdateOriginal - r.client.Watch: %v", err)
 	}
-	return original, nil
+
+	return nil
 }
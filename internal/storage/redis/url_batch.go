@@ -0,0 +1,113 @@
+package redisstorage
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"strconv"
+	"time"
+)
+
+// results of createURL script besides success
+const (
+	originalExists = 1
+	aliasExists    = 2
+)
+
+// createURLScript creates the url atomically if neither its original nor alias exist.
+// Original and alias keys point to each other. Clicks left by an expired url with the
+// same alias are deleted, so that the new url does not inherit them, and so are its expiry and
+// input keys outliving it when the new url does not set them. ARGV are expiration of the url and
+// of its expiry key in unix milliseconds, zero if the url never expires, the
+// expiration time to keep in the expiry key and the input to keep in the input key.
+const createURLScript = `
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 1
+end
+if redis.call("EXISTS", KEYS[2]) == 1 then
+	return 2
+end
+
+if ARGV[1] == "0" then
+	redis.call("SET", KEYS[1], KEYS[2])
+	redis.call("SET", KEYS[2], KEYS[1])
+	redis.call("DEL", KEYS[3])
+else
+	redis.call("SET", KEYS[1], KEYS[2], "PXAT", ARGV[1])
+	redis.call("SET", KEYS[2], KEYS[1], "PXAT", ARGV[1])
+	redis.call("SET", KEYS[3], ARGV[3], "PXAT", ARGV[2])
+end
+redis.call("DEL", KEYS[5])
+
+if ARGV[4] ~= "" then
+	if ARGV[1] == "0" then
+		redis.call("SET", KEYS[4], ARGV[4])
+	else
+		redis.call("SET", KEYS[4], ARGV[4], "PXAT", ARGV[1])
+	end
+else
+	redis.call("DEL", KEYS[4])
+end
+
+return 0
+`
+
+var createURL = redis.NewScript(createURLScript)
+
+// CreateURLs runs the create script for every url in one pipeline.
+func (r *URLRepo) CreateURLs(ctx context.Context, urls []entity.URL) ([]error, error) {
+	errs := make([]error, len(urls))
+	if len(urls) == 0 {
+		return errs, nil
+	}
+
+	// scripts in a pipeline cannot fall back to EVAL, so the script is loaded beforehand
+	if err := createURL.Load(ctx, r.Client).Err(); err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - createURL.Load: %v", err)
+	}
+
+	cmds := make([]*redis.Cmd, len(urls))
+	_, err := r.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, url := range urls {
+			cmds[i] = createURL.EvalSha(ctx, pipe, createURLKeys(url), createURLArgs(url)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("URLRepo.CreateURLs - r.Client.Pipelined: %v", err)
+	}
+
+	for i, cmd := range cmds {
+		res, err := cmd.Int()
+		if err != nil {
+			return nil, fmt.Errorf("URLRepo.CreateURLs - cmd.Int: %v", err)
+		}
+		switch res {
+		case originalExists:
+			errs[i] = storageerrors.ErrOriginalURLExists
+		case aliasExists:
+			errs[i] = storageerrors.ErrURLAliasExists
+		}
+	}
+
+	return errs, nil
+}
+
+func createURLKeys(url entity.URL) []string {
+	return []string{url.Original, url.Alias, expiryKey(url.Alias), inputKey(url.Alias), clicksKey(url.Alias)}
+}
+
+func createURLArgs(url entity.URL) []any {
+	if url.ExpiresAt.IsZero() {
+		return []any{"0", "0", "", url.Input}
+	}
+	return []any{
+		strconv.FormatInt(url.ExpiresAt.UnixMilli(), 10),
+		strconv.FormatInt(url.ExpiresAt.Add(constant.ExpiredAliasRetention).UnixMilli(), 10),
+		url.ExpiresAt.Format(time.RFC3339),
+		url.Input,
+	}
+}
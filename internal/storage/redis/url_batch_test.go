@@ -0,0 +1,79 @@
+package redisstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redismock/v9"
+	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestURLRepo_CreateURLs(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	urls := []entity.URL{
+		{Original: "https://google.com", Alias: "testtest11"},
+		{Original: "https://ya.ru/", Alias: "testtest12", Input: "HTTPS://Ya.ru", ExpiresAt: expiresAt},
+		{Original: "https://go.dev", Alias: "testtest11"},
+		{Original: "https://google.com", Alias: "testtest13"},
+	}
+
+	testCases := []struct {
+		name           string
+		mockBehaviour  func(m redismock.ClientMock)
+		expectedErrs   []error
+		expectedFailed bool
+	}{
+		{
+			name: "OK",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectScriptLoad(createURLScript).SetVal(createURL.Hash())
+				m.ExpectEvalSha(createURL.Hash(), []string{"https://google.com", "testtest11", "expiry:testtest11", "input:testtest11", "clicks:testtest11"}, "0", "0", "", "").
+					SetVal(int64(0))
+				m.ExpectEvalSha(createURL.Hash(), []string{"https://ya.ru/", "testtest12", "expiry:testtest12", "input:testtest12", "clicks:testtest12"},
+					"1893456000000", "1894060800000", "2030-01-01T00:00:00Z", "HTTPS://Ya.ru").
+					SetVal(int64(0))
+				m.ExpectEvalSha(createURL.Hash(), []string{"https://go.dev", "testtest11", "expiry:testtest11", "input:testtest11", "clicks:testtest11"}, "0", "0", "", "").
+					SetVal(int64(2))
+				m.ExpectEvalSha(createURL.Hash(), []string{"https://google.com", "testtest13", "expiry:testtest13", "input:testtest13", "clicks:testtest13"}, "0", "0", "", "").
+					SetVal(int64(1))
+			},
+			expectedErrs: []error{
+				nil,
+				nil,
+				storageerrors.ErrURLAliasExists,
+				storageerrors.ErrOriginalURLExists,
+			},
+		},
+		{
+			name: "script load error",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectScriptLoad(createURLScript).SetErr(errors.New("connection refused"))
+			},
+			expectedFailed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+
+			tc.mockBehaviour(mock)
+
+			errs, err := NewURLRepo(&redisdb.Redis{Client: db}).CreateURLs(context.Background(), urls)
+			if tc.expectedFailed {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedErrs, errs)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package redisstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"time"
+)
+
+// missingOriginal marks aliases which are known to be absent, originals are never empty.
+const missingOriginal = ""
+
+// deletedOriginal marks recently deleted aliases, which are read as not cached
+// and not filled until it expires. Originals always have a scheme.
+const deletedOriginal = "-"
+
+// URLCacheRepo caches originals by alias in front of another storage.
+type URLCacheRepo struct {
+	*redisdb.Redis
+}
+
+func NewURLCacheRepo(client *redisdb.Redis) *URLCacheRepo {
+	return &URLCacheRepo{client}
+}
+
+func urlCacheKey(alias string) string {
+	return constant.URLCacheKeyPrefix + alias
+}
+
+// GetOriginal returns false if the alias is not cached or was deleted recently
+// and ErrURLAliasNotFound if the alias is cached as missing.
+func (r *URLCacheRepo) GetOriginal(ctx context.Context, alias string) (string, bool, error) {
+	original, err := r.Client.Get(ctx, urlCacheKey(alias)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("URLCacheRepo.GetOriginal - r.Client.Get: %v", err)
+	}
+	switch original {
+	case missingOriginal:
+		return "", true, storageerrors.ErrURLAliasNotFound
+	case deletedOriginal:
+		return "", false, nil
+	}
+	return original, true, nil
+}
+
+func (r *URLCacheRepo) SetOriginal(ctx context.Context, alias, original string, ttl time.Duration) error {
+	err := r.Client.Set(ctx, urlCacheKey(alias), original, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("URLCacheRepo.SetOriginal - r.Client.Set: %v", err)
+	}
+	return nil
+}
+
+// AddOriginal caches the original unless the alias is cached or was deleted recently.
+func (r *URLCacheRepo) AddOriginal(ctx context.Context, alias, original string, ttl time.Duration) error {
+	err := r.Client.SetNX(ctx, urlCacheKey(alias), original, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("URLCacheRepo.AddOriginal - r.Client.SetNX: %v", err)
+	}
+	return nil
+}
+
+// SetMissing caches the alias as missing unless the alias is cached or was deleted recently.
+func (r *URLCacheRepo) SetMissing(ctx context.Context, alias string, ttl time.Duration) error {
+	err := r.Client.SetNX(ctx, urlCacheKey(alias), missingOriginal, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("URLCacheRepo.SetMissing - r.Client.SetNX: %v", err)
+	}
+	return nil
+}
+
+// Delete replaces cached aliases with deletion marks, which keep reads that got the source
+// before the change from caching it. SetOriginal overwrites the marks.
+func (r *URLCacheRepo) Delete(ctx context.Context, aliases ...string) error {
+	_, err := r.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, alias := range aliases {
+			pipe.Set(ctx, urlCacheKey(alias), deletedOriginal, constant.URLCacheInvalidationTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("URLCacheRepo.Delete - r.Client.Pipelined: %v", err)
+	}
+	return nil
+}
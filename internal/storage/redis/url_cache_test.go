@@ -0,0 +1,60 @@
+package redisstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redismock/v9"
+	"github.com/romandnk/shortener/internal/constant"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestURLCacheRepo(t *testing.T) {
+	ctx := context.Background()
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	cache := NewURLCacheRepo(&redisdb.Redis{Client: db})
+
+	mock.ExpectSet("url_cache:testtest11", "https://google.com", time.Hour).SetVal("OK")
+	mock.ExpectGet("url_cache:testtest11").SetVal("https://google.com")
+	mock.ExpectSetNX("url_cache:testtest12", "", time.Minute).SetVal(true)
+	mock.ExpectGet("url_cache:testtest12").SetVal("")
+	mock.ExpectGet("url_cache:testtest13").RedisNil()
+	mock.ExpectGet("url_cache:testtest13").SetErr(errors.New("connection refused"))
+	mock.ExpectSet("url_cache:testtest11", "-", constant.URLCacheInvalidationTTL).SetVal("OK")
+	mock.ExpectSet("url_cache:testtest12", "-", constant.URLCacheInvalidationTTL).SetVal("OK")
+	mock.ExpectGet("url_cache:testtest11").SetVal("-")
+	// reads that got the source before the deletion do not cache it
+	mock.ExpectSetNX("url_cache:testtest11", "https://google.com", time.Hour).SetVal(false)
+
+	require.NoError(t, cache.SetOriginal(ctx, "testtest11", "https://google.com", time.Hour))
+	original, ok, err := cache.GetOriginal(ctx, "testtest11")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "https://google.com", original)
+
+	require.NoError(t, cache.SetMissing(ctx, "testtest12", time.Minute))
+	_, ok, err = cache.GetOriginal(ctx, "testtest12")
+	require.ErrorIs(t, err, storageerrors.ErrURLAliasNotFound)
+	require.True(t, ok)
+
+	_, ok, err = cache.GetOriginal(ctx, "testtest13")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok, err = cache.GetOriginal(ctx, "testtest13")
+	require.Error(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, cache.Delete(ctx, "testtest11", "testtest12"))
+	_, ok, err = cache.GetOriginal(ctx, "testtest11")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, cache.AddOriginal(ctx, "testtest11", "https://google.com", time.Hour))
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
@@ -2,33 +2,30 @@ package redisstorage
 
 import (
 	"context"
+	"errors"
 	"github.com/go-redis/redismock/v9"
 	"github.com/redis/go-redis/v9"
 	"github.com/romandnk/shortener/internal/constant"
 	"github.com/romandnk/shortener/internal/entity"
 	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestURLRepo_CreateURL(t *testing.T) {
-	type input struct {
-		keyOne           string
-		valueOne         string
-		expectedErrorOne error
-		keyTwo           string
-		valueTwo         string
-		expectedErrorTwo error
-	}
+	type mockBehaviour func(m redismock.ClientMock)
 
-	type mockBehaviour func(m redismock.ClientMock, input input)
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys := []string{"http://test.com", "testtest11", "expiry:testtest11", "input:testtest11", "clicks:testtest11"}
 
 	testCases := []struct {
-		name          string
-		url           entity.URL
-		input         input
-		mockBehaviour mockBehaviour
-		expectedError error
+		name           string
+		url            entity.URL
+		mockBehaviour  mockBehaviour
+		expectedError  error
+		expectedFailed bool
 	}{
 		{
 			name: "OK",
@@ -36,29 +33,52 @@ func TestURLRepo_CreateURL(t *testing.T) {
 				Original: "http://test.com",
 				Alias:    "testtest11",
 			},
-			input: input{
-				keyOne:   "http://test.com",
-				valueOne: "testtest11",
-				keyTwo:   "testtest11",
-				valueTwo: "http://test.com",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectEvalSha(createURL.Hash(), keys, "0", "0", "", "").SetVal(int64(0))
 			},
-			mockBehaviour: func(m redismock.ClientMock, input input) {
-				m.ExpectSetNX(input.keyOne, input.valueOne, constant.ZeroTTL).SetVal(true)
-				m.ExpectSetNX(input.keyTwo, input.valueTwo, constant.ZeroTTL).SetVal(true)
+		},
+		{
+			name: "OK with input",
+			url: entity.URL{
+				Original: "http://test.com",
+				Alias:    "testtest11",
+				Input:    "HTTP://Test.com",
+			},
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectEvalSha(createURL.Hash(), keys, "0", "0", "", "HTTP://Test.com").SetVal(int64(0))
 			},
 		},
 		{
-			name: "original url already exists",
+			name: "OK with expiration",
+			url: entity.URL{
+				Original:  "http://test.com",
+				Alias:     "testtest11",
+				ExpiresAt: expiresAt,
+			},
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectEvalSha(createURL.Hash(), keys, "1893456000000", "1894060800000", "2030-01-01T00:00:00Z", "").
+					SetVal(int64(0))
+			},
+		},
+		{
+			name: "redis error",
 			url: entity.URL{
 				Original: "http://test.com",
 				Alias:    "testtest11",
 			},
-			input: input{
-				keyOne:   "http://test.com",
-				valueOne: "testtest11",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectEvalSha(createURL.Hash(), keys, "0", "0", "", "").SetErr(errors.New("connection refused"))
 			},
-			mockBehaviour: func(m redismock.ClientMock, input input) {
-				m.ExpectSetNX(input.keyOne, input.valueOne, constant.ZeroTTL).SetVal(false)
+			expectedFailed: true,
+		},
+		{
+			name: "original url already exists",
+			url: entity.URL{
+				Original: "http://test.com",
+				Alias:    "testtest11",
+			},
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectEvalSha(createURL.Hash(), keys, "0", "0", "", "").SetVal(int64(1))
 			},
 			expectedError: storageerrors.ErrOriginalURLExists,
 		},
@@ -68,15 +88,8 @@ func TestURLRepo_CreateURL(t *testing.T) {
 				Original: "http://test.com",
 				Alias:    "testtest11",
 			},
-			input: input{
-				keyOne:   "http://test.com",
-				valueOne: "testtest11",
-				keyTwo:   "testtest11",
-				valueTwo: "http://test.com",
-			},
-			mockBehaviour: func(m redismock.ClientMock, input input) {
-				m.ExpectSetNX(input.keyOne, input.valueOne, constant.ZeroTTL).SetVal(true)
-				m.ExpectSetNX(input.keyTwo, input.valueTwo, constant.ZeroTTL).SetVal(false)
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectEvalSha(createURL.Hash(), keys, "0", "0", "", "").SetVal(int64(2))
 			},
 			expectedError: storageerrors.ErrURLAliasExists,
 		},
@@ -90,18 +103,79 @@ func TestURLRepo_CreateURL(t *testing.T) {
 
 			ctx := context.Background()
 
-			tc.mockBehaviour(mock, tc.input)
+			tc.mockBehaviour(mock)
 
-			urlStorage := NewURLRepo(db)
+			urlStorage := NewURLRepo(&redisdb.Redis{Client: db})
 
 			err := urlStorage.CreateURL(ctx, tc.url)
-			require.ErrorIs(t, err, tc.expectedError)
+			if tc.expectedFailed {
+				require.Error(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.expectedError)
+			}
 
 			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
 		})
 	}
 }
 
+// TestURLRepo_CreateURL_AliasCollision checks that a collided alias leaves nothing behind,
+// so that the retry with another alias succeeds and the original points to the new alias.
+func TestURLRepo_CreateURL_AliasCollision(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	ctx := context.Background()
+	urlStorage := NewURLRepo(&redisdb.Redis{Client: db})
+
+	mock.ExpectEvalSha(createURL.Hash(), []string{"http://test.com", "testtest11", "expiry:testtest11", "input:testtest11", "clicks:testtest11"}, "0", "0", "", "").
+		SetVal(int64(2))
+	mock.ExpectEvalSha(createURL.Hash(), []string{"http://test.com", "testtest12", "expiry:testtest12", "input:testtest12", "clicks:testtest12"}, "0", "0", "", "").
+		SetVal(int64(0))
+	mock.ExpectGet("http://test.com").SetVal("testtest12")
+
+	err := urlStorage.CreateURL(ctx, entity.URL{Original: "http://test.com", Alias: "testtest11"})
+	require.ErrorIs(t, err, storageerrors.ErrURLAliasExists)
+
+	err = urlStorage.CreateURL(ctx, entity.URL{Original: "http://test.com", Alias: "testtest12"})
+	require.NoError(t, err)
+
+	alias, err := urlStorage.GetAliasByOriginal(ctx, "http://test.com")
+	require.NoError(t, err)
+	require.Equal(t, "testtest12", alias)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+// TestURLRepo_CreateURL_NonExpiringReusesAlias checks that a url without expiration and input
+// created on the alias of an expired url is not listed with the expiration and input of the expired one.
+func TestURLRepo_CreateURL_NonExpiringReusesAlias(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	ctx := context.Background()
+	urlStorage := NewURLRepo(&redisdb.Redis{Client: db})
+
+	// the expiry and input keys of the expired url are deleted by the script
+	require.Contains(t, createURLScript, "redis.call(\"SET\", KEYS[2], KEYS[1])\n\tredis.call(\"DEL\", KEYS[3])")
+	require.Contains(t, createURLScript, "else\n\tredis.call(\"DEL\", KEYS[4])")
+
+	mock.ExpectEvalSha(createURL.Hash(), []string{"http://test.com", "testtest11", "expiry:testtest11", "input:testtest11", "clicks:testtest11"}, "0", "0", "", "").
+		SetVal(int64(0))
+	mock.ExpectScanType(0, "", 10, "string").SetVal([]string{"testtest11", "http://test.com"}, 0)
+	mock.ExpectMGet("testtest11").SetVal([]any{"http://test.com"})
+	mock.ExpectMGet("expiry:testtest11").SetVal([]any{nil})
+	mock.ExpectMGet("input:testtest11").SetVal([]any{nil})
+
+	require.NoError(t, urlStorage.CreateURL(ctx, entity.URL{Original: "http://test.com", Alias: "testtest11"}))
+
+	urls, _, err := urlStorage.GetURLs(ctx, "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.URL{{Original: "http://test.com", Alias: "testtest11"}}, urls)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
 func TestURLRepo_GetOriginalByAlias(t *testing.T) {
 	type input struct {
 		key           string
@@ -132,7 +206,7 @@ func TestURLRepo_GetOriginalByAlias(t *testing.T) {
 			expectedOriginal: "http://test.com",
 		},
 		{
-			name:       "OK",
+			name:       "url alias is not found",
 			inputAlias: "testtest11",
 			input: input{
 				key:           "testtest11",
@@ -140,9 +214,23 @@ func TestURLRepo_GetOriginalByAlias(t *testing.T) {
 			},
 			mockBehaviour: func(m redismock.ClientMock, input input) {
 				m.ExpectGet(input.key).SetErr(input.expectedError)
+				m.ExpectExists(expiryKey(input.key)).SetVal(0)
 			},
 			expectedError: storageerrors.ErrURLAliasNotFound,
 		},
+		{
+			name:       "url alias is expired",
+			inputAlias: "testtest11",
+			input: input{
+				key:           "testtest11",
+				expectedError: redis.Nil,
+			},
+			mockBehaviour: func(m redismock.ClientMock, input input) {
+				m.ExpectGet(input.key).SetErr(input.expectedError)
+				m.ExpectExists(expiryKey(input.key)).SetVal(1)
+			},
+			expectedError: storageerrors.ErrURLExpired,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -155,7 +243,7 @@ func TestURLRepo_GetOriginalByAlias(t *testing.T) {
 
 			tc.mockBehaviour(mock, tc.input)
 
-			urlStorage := NewURLRepo(db)
+			urlStorage := NewURLRepo(&redisdb.Redis{Client: db})
 
 			original, err := urlStorage.GetOriginalByAlias(ctx, tc.inputAlias)
 			require.ErrorIs(t, err, tc.expectedError)
@@ -165,3 +253,234 @@ func TestURLRepo_GetOriginalByAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestURLRepo_GetURLs(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectScanType(0, "", 10, "string").SetVal([]string{
+		"testtest11",
+		"http://test.com",
+		"expiry:testtest12",
+		"input:testtest11",
+		constant.URLCacheKeyPrefix + "testtest13",
+		"testtest12",
+		"testtest14",
+	}, 17)
+	mock.ExpectMGet("testtest11", "testtest12", "testtest14").
+		SetVal([]any{"http://test.com", "http://ya.ru", nil})
+	mock.ExpectMGet("expiry:testtest11", "expiry:testtest12", "expiry:testtest14").
+		SetVal([]any{nil, expiresAt.Format(time.RFC3339), nil})
+	mock.ExpectMGet("input:testtest11", "input:testtest12", "input:testtest14").
+		SetVal([]any{"HTTP://Test.com", nil, nil})
+	mock.ExpectScanType(17, "", 10, "string").SetVal([]string{"http://ya.ru"}, 0)
+
+	urlStorage := NewURLRepo(&redisdb.Redis{Client: db})
+
+	urls, next, err := urlStorage.GetURLs(ctx, "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.URL{
+		{Original: "http://test.com", Alias: "testtest11", Input: "HTTP://Test.com"},
+		{Original: "http://ya.ru", Alias: "testtest12", ExpiresAt: expiresAt},
+	}, urls)
+	require.Equal(t, "17", next)
+
+	urls, next, err = urlStorage.GetURLs(ctx, next, 10)
+	require.NoError(t, err)
+	require.Empty(t, urls)
+	require.Empty(t, next)
+
+	require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+}
+
+func TestURLRepo_GetAliasByOriginal(t *testing.T) {
+	type input struct {
+		key           string
+		value         string
+		expectedError error
+	}
+
+	type mockBehaviour func(m redismock.ClientMock, input input)
+
+	testCases := []struct {
+		name          string
+		inputOriginal string
+		input         input
+		mockBehaviour mockBehaviour
+		expectedAlias string
+		expectedError error
+	}{
+		{
+			name:          "OK",
+			inputOriginal: "http://test.com",
+			input: input{
+				key:   "http://test.com",
+				value: "testtest11",
+			},
+			mockBehaviour: func(m redismock.ClientMock, input input) {
+				m.ExpectGet(input.key).SetVal(input.value)
+			},
+			expectedAlias: "testtest11",
+		},
+		{
+			name:          "original url is not found",
+			inputOriginal: "http://test.com",
+			input: input{
+				key:           "http://test.com",
+				expectedError: redis.Nil,
+			},
+			mockBehaviour: func(m redismock.ClientMock, input input) {
+				m.ExpectGet(input.key).SetErr(input.expectedError)
+			},
+			expectedError: storageerrors.ErrOriginalNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+
+			ctx := context.Background()
+
+			tc.mockBehaviour(mock, tc.input)
+
+			urlStorage := NewURLRepo(&redisdb.Redis{Client: db})
+
+			alias, err := urlStorage.GetAliasByOriginal(ctx, tc.inputOriginal)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedAlias, alias)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
+
+func TestURLRepo_DeleteByAlias(t *testing.T) {
+	type mockBehaviour func(m redismock.ClientMock)
+
+	testCases := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectWatch("testtest11")
+				m.ExpectGet("testtest11").SetVal("http://test.com")
+				m.ExpectTxPipeline()
+				m.ExpectDel("testtest11", "http://test.com", expiryKey("testtest11"), inputKey("testtest11"), clicksKey("testtest11")).SetVal(3)
+				m.ExpectTxPipelineExec()
+			},
+		},
+		{
+			name: "url alias is not found",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectWatch("testtest11")
+				m.ExpectGet("testtest11").RedisNil()
+			},
+			expectedError: storageerrors.ErrURLAliasNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+
+			tc.mockBehaviour(mock)
+
+			urlStorage := NewURLRepo(&redisdb.Redis{Client: db})
+
+			err := urlStorage.DeleteByAlias(context.Background(), "testtest11")
+			require.ErrorIs(t, err, tc.expectedError)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
+
+func TestURLRepo_UpdateOriginal(t *testing.T) {
+	type mockBehaviour func(m redismock.ClientMock)
+
+	testCases := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectWatch("testtest11", "http://test2.com")
+				m.ExpectGet("testtest11").SetVal("http://test.com")
+				m.ExpectExists("http://test2.com").SetVal(0)
+				m.ExpectPTTL("testtest11").SetVal(time.Hour)
+				m.ExpectTxPipeline()
+				m.ExpectDel("http://test.com").SetVal(1)
+				m.ExpectSet("http://test2.com", "testtest11", time.Hour).SetVal("OK")
+				m.ExpectSet("testtest11", "http://test2.com", redis.KeepTTL).SetVal("OK")
+				m.ExpectSet("input:testtest11", "HTTP://Test2.com", time.Hour).SetVal("OK")
+				m.ExpectTxPipelineExec()
+			},
+		},
+		{
+			name: "OK url never expires",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectWatch("testtest11", "http://test2.com")
+				m.ExpectGet("testtest11").SetVal("http://test.com")
+				m.ExpectExists("http://test2.com").SetVal(0)
+				m.ExpectPTTL("testtest11").SetVal(-1)
+				m.ExpectTxPipeline()
+				m.ExpectDel("http://test.com").SetVal(1)
+				m.ExpectSet("http://test2.com", "testtest11", constant.ZeroTTL).SetVal("OK")
+				m.ExpectSet("testtest11", "http://test2.com", redis.KeepTTL).SetVal("OK")
+				m.ExpectSet("input:testtest11", "HTTP://Test2.com", constant.ZeroTTL).SetVal("OK")
+				m.ExpectTxPipelineExec()
+			},
+		},
+		{
+			name: "url alias is not found",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectWatch("testtest11", "http://test2.com")
+				m.ExpectGet("testtest11").RedisNil()
+			},
+			expectedError: storageerrors.ErrURLAliasNotFound,
+		},
+		{
+			name: "original url already exists",
+			mockBehaviour: func(m redismock.ClientMock) {
+				m.ExpectWatch("testtest11", "http://test2.com")
+				m.ExpectGet("testtest11").SetVal("http://test.com")
+				m.ExpectExists("http://test2.com").SetVal(1)
+			},
+			expectedError: storageerrors.ErrOriginalURLExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+
+			tc.mockBehaviour(mock)
+
+			urlStorage := NewURLRepo(&redisdb.Redis{Client: db})
+
+			err := urlStorage.UpdateOriginal(context.Background(), entity.URL{
+				Original: "http://test2.com",
+				Alias:    "testtest11",
+				Input:    "HTTP://Test2.com",
+			})
+			require.ErrorIs(t, err, tc.expectedError)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
@@ -2,40 +2,228 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"github.com/romandnk/shortener/internal/constant"
 	"github.com/romandnk/shortener/internal/entity"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	memorystorage "github.com/romandnk/shortener/internal/storage/memory"
 	postgresstorage "github.com/romandnk/shortener/internal/storage/postgres"
+	redisstorage "github.com/romandnk/shortener/internal/storage/redis"
+	"github.com/romandnk/shortener/migrations"
+	"github.com/romandnk/shortener/pkg/logger"
 	"github.com/romandnk/shortener/pkg/storage/postgres"
+	"github.com/romandnk/shortener/pkg/storage/redis"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"time"
 )
 
 //go:generate mockgen -source=storage.go -destination=mock/mock.go storage
 
-var Module = fx.Module("storage", fx.Provide(NewStorage))
-
 type URL interface {
 	CreateURL(ctx context.Context, url entity.URL) error
+	// CreateURLs creates urls in a fixed number of round trips and returns errors of urls in their positions,
+	// which are ErrOriginalURLExists or ErrURLAliasExists. The second error means the storage failed.
+	CreateURLs(ctx context.Context, urls []entity.URL) ([]error, error)
 	GetOriginalByAlias(ctx context.Context, alias string) (string, error)
+	GetAliasByOriginal(ctx context.Context, original string) (string, error)
+	// GetURLs returns a page of not expired urls and the cursor of the next page, which is empty after the last page.
+	// The empty cursor starts from the first page.
+	GetURLs(ctx context.Context, cursor string, limit int) ([]entity.URL, string, error)
+	// DeleteByAlias deletes the url together with its clicks.
+	DeleteByAlias(ctx context.Context, alias string) error
+	// UpdateOriginal points url.Alias to url.Original and url.Input keeping the expiration.
+	UpdateOriginal(ctx context.Context, url entity.URL) error
+}
+
+// URLSource is a URL storage behind the cache, it tells when urls expire.
+type URLSource interface {
+	URL
+	GetURLByAlias(ctx context.Context, alias string) (entity.URL, error)
+}
+
+// URLCache keeps originals by alias, a missing alias is cached as ErrURLAliasNotFound.
+// AddOriginal and SetMissing fill the cache after reads of the source, they do nothing if the alias
+// is cached or was deleted recently, so that a read racing with a change does not cache the old state.
+type URLCache interface {
+	GetOriginal(ctx context.Context, alias string) (string, bool, error)
+	SetOriginal(ctx context.Context, alias, original string, ttl time.Duration) error
+	AddOriginal(ctx context.Context, alias, original string, ttl time.Duration) error
+	SetMissing(ctx context.Context, alias string, ttl time.Duration) error
+	Delete(ctx context.Context, aliases ...string) error
+}
+
+type Click interface {
+	CreateClicks(ctx context.Context, clicks []entity.Click) error
+	GetClickStats(ctx context.Context, alias string) (entity.ClickStats, error)
+}
+
+type APIKey interface {
+	CreateAPIKey(ctx context.Context, key entity.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, hash string) (entity.APIKey, error)
+	GetAPIKeys(ctx context.Context) ([]entity.APIKey, error)
+	DeleteAPIKey(ctx context.Context, name string) error
+}
+
+// Domain keeps rules blocking and allowing domains of original urls.
+type Domain interface {
+	// SaveDomainRule adds the rule or moves its pattern to another list.
+	SaveDomainRule(ctx context.Context, rule entity.DomainRule) error
+	GetDomainRules(ctx context.Context) ([]entity.DomainRule, error)
+	DeleteDomainRule(ctx context.Context, pattern string) error
+}
+
+// Sequence hands out increasing numbers for sequential aliases, starting from 1.
+type Sequence interface {
+	NextAliasID(ctx context.Context) (uint64, error)
+}
+
+// Purger removes expired urls from storages which do not expire them natively.
+type Purger interface {
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// Config is filled from the application config by the app, the storage can not import
+// the config package since it imports routes depending on the storage through services.
+type Config struct {
+	DBType   string
+	Postgres postgres.Config
+	Redis    redis.Config
+	Cache    CacheConfig
 }
 
 type Storage struct {
-	URL URL
+	URL      URL
+	Click    Click
+	APIKey   APIKey
+	Domain   Domain
+	Sequence Sequence
+	// Purger is nil when the storage expires urls by itself.
+	Purger Purger
+
+	// Postgres and Redis are connections of the chosen database, others are nil.
+	Postgres *postgres.Postgres
+	Redis    *redis.Redis
 }
 
-func NewStorage(db *postgres.Postgres) (*Storage, error) {
+// NewStorage connects only to the database chosen by db_type
+// and closes the connection when the application stops.
+// Postgres is migrated first if migrate is set and its urls are cached in Redis if the cache is enabled.
+func NewStorage(ctx context.Context, lc fx.Lifecycle, cfg Config, logger logger.Logger) (*Storage, error) {
 	var storage Storage
 
-	//switch v := db.(type) {
-	//case *postgres.Postgres:
-	storage = Storage{
-		URL: postgresstorage.NewURLRepo(db),
+	switch cfg.DBType {
+	case constant.POSTGRES:
+		db, err := postgres.New(ctx, cfg.Postgres)
+		if err != nil {
+			return &storage, err
+		}
+		lc.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				db.Close()
+				return nil
+			},
+		})
+
+		if cfg.Postgres.Migrate {
+			if err := migrate(db, logger); err != nil {
+				return &storage, err
+			}
+		}
+
+		repo := postgresstorage.NewURLRepo(db)
+		storage = Storage{
+			URL:      repo,
+			Click:    postgresstorage.NewClickRepo(db),
+			APIKey:   postgresstorage.NewAPIKeyRepo(db),
+			Domain:   postgresstorage.NewDomainRepo(db),
+			Sequence: postgresstorage.NewSequenceRepo(db),
+			Purger:   repo,
+			Postgres: db,
+		}
+
+		if cfg.Cache.Enabled {
+			cache, err := redis.New(ctx, cfg.Redis)
+			if err != nil {
+				return &storage, fmt.Errorf("error connecting to cache: %w", err)
+			}
+			lc.Append(fx.Hook{
+				OnStop: func(ctx context.Context) error {
+					return cache.Close()
+				},
+			})
+
+			storage.URL = NewCachedURL(repo, redisstorage.NewURLCacheRepo(cache), cfg.Cache, logger)
+		}
+	case constant.REDIS:
+		db, err := redis.New(ctx, cfg.Redis)
+		if err != nil {
+			return &storage, err
+		}
+		lc.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				return db.Close()
+			},
+		})
+
+		storage = Storage{
+			URL:      redisstorage.NewURLRepo(db),
+			Click:    redisstorage.NewClickRepo(db),
+			APIKey:   redisstorage.NewAPIKeyRepo(db),
+			Domain:   redisstorage.NewDomainRepo(db),
+			Sequence: redisstorage.NewSequenceRepo(db),
+			Redis:    db,
+		}
+	case constant.MEMORY:
+		clicks := memorystorage.NewClickRepo()
+		repo := memorystorage.NewURLRepo(clicks)
+		storage = Storage{
+			URL:      repo,
+			Click:    clicks,
+			APIKey:   memorystorage.NewAPIKeyRepo(),
+			Domain:   memorystorage.NewDomainRepo(),
+			Sequence: memorystorage.NewSequenceRepo(),
+			Purger:   repo,
+		}
+	default:
+		return &storage, fmt.Errorf("%w: %q", storageerrors.ErrInvalidDB, cfg.DBType)
 	}
-	//case *redis.Redis:
-	//	storage = Storage{
-	//		URL: redisstorage.NewURLRepo(v),
-	//	}
-	//default:
-	//	return &storage, storageerrors.ErrInvalidDB
-	//}
 
 	return &storage, nil
 }
+
+// migrate applies the embedded migrations, so the schema always matches the binary.
+func migrate(db *postgres.Postgres, logger logger.Logger) error {
+	migrator, err := postgres.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if err = migrator.Up(); err != nil {
+		return fmt.Errorf("error migrating postgres: %w", err)
+	}
+
+	version, _, err := migrator.Version()
+	if err != nil {
+		return fmt.Errorf("error reading postgres schema version: %w", err)
+	}
+	logger.Info("postgres migrations applied",
+		zap.Uint64("version", version),
+		zap.Uint64("latest", migrator.Latest()),
+	)
+
+	return nil
+}
+
+// Ping checks the connection of the chosen database, the memory storage is always available.
+func (s *Storage) Ping(ctx context.Context) error {
+	switch {
+	case s.Postgres != nil:
+		return s.Postgres.Pool.Ping(ctx)
+	case s.Redis != nil:
+		return s.Redis.Client.Ping(ctx).Err()
+	default:
+		return nil
+	}
+}
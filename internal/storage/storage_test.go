@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redismock/v9"
+	"github.com/pashagolub/pgxmock/v3"
+	storageerrors "github.com/romandnk/shortener/internal/storage/errors"
+	"github.com/romandnk/shortener/pkg/storage/postgres"
+	"github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+	"testing"
+)
+
+func TestNewStorage_InvalidDB(t *testing.T) {
+	cfg := Config{DBType: "mysql"}
+
+	_, err := NewStorage(context.Background(), fxtest.NewLifecycle(t), cfg, nil)
+	require.ErrorIs(t, err, storageerrors.ErrInvalidDB)
+}
+
+func TestStorage_Ping(t *testing.T) {
+	ctx := context.Background()
+	pingErr := errors.New("connection refused")
+
+	t.Run("postgres", func(t *testing.T) {
+		pool, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer pool.Close()
+
+		pool.ExpectPing()
+		pool.ExpectPing().WillReturnError(pingErr)
+
+		s := &Storage{Postgres: &postgres.Postgres{Pool: pool}}
+		require.NoError(t, s.Ping(ctx))
+		require.ErrorIs(t, s.Ping(ctx), pingErr)
+		require.NoError(t, pool.ExpectationsWereMet())
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+
+		mock.ExpectPing().SetVal("PONG")
+		mock.ExpectPing().SetErr(pingErr)
+
+		s := &Storage{Redis: &redis.Redis{Client: db}}
+		require.NoError(t, s.Ping(ctx))
+		require.ErrorIs(t, s.Ping(ctx), pingErr)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		require.NoError(t, (&Storage{}).Ping(ctx))
+	})
+}
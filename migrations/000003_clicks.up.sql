@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS clicks (
+    alias VARCHAR(128) NOT NULL,
+    clicked_at TIMESTAMPTZ NOT NULL,
+    referrer TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_hash VARCHAR(64) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_clicks_alias_clicked_at ON clicks (alias, clicked_at);
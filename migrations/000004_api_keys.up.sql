@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    name VARCHAR(128) UNIQUE NOT NULL,
+    key_hash CHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
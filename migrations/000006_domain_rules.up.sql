@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS domain_rules (
+    pattern VARCHAR(255) PRIMARY KEY,
+    list VARCHAR(5) NOT NULL CHECK (list IN ('block', 'allow')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1 @@
+CREATE SEQUENCE IF NOT EXISTS alias_seq AS BIGINT START WITH 1;
@@ -0,0 +1,9 @@
+// Package migrations embeds the Postgres schema migrations, so the binary always carries the schema it expects.
+package migrations
+
+import "embed"
+
+// FS holds migrations named {version}_{title}.up.sql and {version}_{title}.down.sql.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFS(t *testing.T) {
+	source, err := iofs.New(FS, ".")
+	require.NoError(t, err)
+	defer source.Close()
+
+	version, err := source.First()
+	require.NoError(t, err)
+	require.Equal(t, uint(1), version)
+
+	for {
+		up, _, err := source.ReadUp(version)
+		require.NoError(t, err, "version %d has no up migration", version)
+		require.NoError(t, up.Close())
+
+		down, _, err := source.ReadDown(version)
+		require.NoError(t, err, "version %d has no down migration", version)
+		require.NoError(t, down.Close())
+
+		next, err := source.Next(version)
+		if err != nil {
+			break
+		}
+		require.Equal(t, version+1, next, "migration versions must have no gaps")
+		version = next
+	}
+}
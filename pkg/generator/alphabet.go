@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Alphabet is a set of symbols aliases are made of, the order of symbols sets digits of encoded numbers.
+type Alphabet string
+
+const (
+	// AlphabetDefault has "_" last, so that encoded numbers are padded with "0".
+	AlphabetDefault Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ_"
+	AlphabetBase62  Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// AlphabetHuman excludes symbols easily confused when read or typed: 0 and O, 1, l and I.
+	AlphabetHuman Alphabet = "23456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+	// AlphabetURLSafe has all symbols alphabets can consist of.
+	AlphabetURLSafe Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ_-"
+)
+
+// presets are alphabets which can be configured by name.
+var presets = map[string]Alphabet{
+	"default": AlphabetDefault,
+	"base62":  AlphabetBase62,
+	"human":   AlphabetHuman,
+}
+
+var ErrInvalidAlphabet = errors.New("alphabet must be a preset or at least 2 unique letters, digits, _ or -")
+
+// ParseAlphabet returns the preset of the name, default, base62 or human,
+// or the alphabet of the symbols otherwise. An empty string is the default alphabet.
+func ParseAlphabet(s string) (Alphabet, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return AlphabetDefault, nil
+	}
+	if preset, ok := presets[strings.ToLower(s)]; ok {
+		return preset, nil
+	}
+
+	if len(s) < 2 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAlphabet, s)
+	}
+	for i, r := range s {
+		if !strings.ContainsRune(string(AlphabetURLSafe), r) || strings.ContainsRune(s[i+1:], r) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidAlphabet, s)
+		}
+	}
+
+	return Alphabet(s), nil
+}
+
+// Contains reports whether s consists only of symbols of the alphabet.
+func (a Alphabet) Contains(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune(string(a), r) {
+			return false
+		}
+	}
+	return true
+}
+
+// symbols returns symbols of the alphabet, the zero alphabet is the default one.
+func (a Alphabet) symbols() string {
+	if a == "" {
+		return string(AlphabetDefault)
+	}
+	return string(a)
+}
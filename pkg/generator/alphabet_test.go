@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseAlphabet(t *testing.T) {
+	testCases := []struct {
+		input         string
+		expected      Alphabet
+		expectedError error
+	}{
+		{input: "", expected: AlphabetDefault},
+		{input: "default", expected: AlphabetDefault},
+		{input: " Human ", expected: AlphabetHuman},
+		{input: "base62", expected: AlphabetBase62},
+		{input: "abc-_123", expected: "abc-_123"},
+		{input: "a", expectedError: ErrInvalidAlphabet},
+		{input: "abca", expectedError: ErrInvalidAlphabet},
+		{input: "abc.", expectedError: ErrInvalidAlphabet},
+		{input: "абв", expectedError: ErrInvalidAlphabet},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			alphabet, err := ParseAlphabet(tc.input)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expected, alphabet)
+		})
+	}
+}
+
+func TestAlphabetHuman(t *testing.T) {
+	for _, ambiguous := range []string{"0", "O", "1", "l", "I"} {
+		require.False(t, AlphabetHuman.Contains(ambiguous), ambiguous)
+	}
+	require.True(t, AlphabetURLSafe.Contains(string(AlphabetDefault)))
+	require.True(t, AlphabetURLSafe.Contains(string(AlphabetHuman)))
+
+	alias, err := NewGen(AlphabetHuman).Random(1000)
+	require.NoError(t, err)
+	require.True(t, AlphabetHuman.Contains(alias))
+}
+
+func TestAlphabet_Contains(t *testing.T) {
+	require.True(t, AlphabetDefault.Contains("abc_XYZ_09"))
+	require.False(t, AlphabetDefault.Contains("abc-xyz"))
+	require.False(t, AlphabetDefault.Contains("abc/xyz"))
+	require.False(t, AlphabetDefault.Contains("ябв"))
+}
@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Strategies of alias generation.
+const (
+	// StrategyRandom generates crypto-random aliases.
+	StrategyRandom = "random"
+	// StrategySequence encodes numbers of a counter with the alphabet, aliases are short but predictable.
+	StrategySequence = "sequence"
+	// StrategyHash derives aliases from the hash of the normalized original url.
+	StrategyHash = "hash"
+	// StrategyHashids encodes numbers of a counter with an alphabet shuffled by the salt.
+	StrategyHashids = "hashids"
+	// StrategyTime starts aliases with the creation time, so they sort in order of creation.
+	StrategyTime = "time"
+)
+
+// MinLength is the shortest length of generated aliases.
+const MinLength = 4
+
+var (
+	ErrUnknownStrategy = errors.New("unknown alias generation strategy")
+	ErrNoCounter       = errors.New("alias generation strategy requires a counter")
+	ErrInvalidLength   = fmt.Errorf("alias length must be at least %d", MinLength)
+	ErrLengthTooShort  = errors.New("alias length is too short for the strategy and alphabet")
+	ErrAliasTooLong    = errors.New("aliases of the strategy and alphabet can exceed the max alias length")
+)
+
+type Config struct {
+	Strategy string `yaml:"strategy" env:"ALIAS_GENERATOR_STRATEGY" env-default:"random"`
+	// Alphabet is a preset, default, base62 or human, or symbols of generated aliases.
+	// Aliases generated with other alphabets stay reachable after it is changed.
+	Alphabet string `yaml:"alphabet" env:"ALIAS_ALPHABET" env-default:"default"`
+	// Length is the initial length of generated aliases, it grows while collisions are frequent.
+	Length int `yaml:"length" env:"ALIAS_LENGTH" env-default:"10"`
+	// Salt shuffles the alphabet of the hashids strategy, changing it changes all further aliases.
+	Salt string `yaml:"salt" env:"ALIAS_GENERATOR_SALT"`
+	// CounterTimeout bounds requests of counter based strategies to the storage.
+	CounterTimeout time.Duration `yaml:"counter_timeout" env-default:"1s"`
+	// MaxLength is the longest alias accepted by the service, zero is unbounded. Counter based strategies
+	// outgrow the requested length once numbers do not fit it, so they are rejected if the largest
+	// number does not fit MaxLength. It is set from the url service configuration.
+	MaxLength int `yaml:"-"`
+}
+
+// Validate checks the alphabet and the length and returns the parsed alphabet.
+func (c Config) Validate() (Alphabet, error) {
+	alphabet, err := ParseAlphabet(c.Alphabet)
+	if err != nil {
+		return "", err
+	}
+	if c.Length < MinLength {
+		return "", fmt.Errorf("%w: %d", ErrInvalidLength, c.Length)
+	}
+	return alphabet, nil
+}
+
+// New returns the generator of the configured strategy,
+// counter is required by the sequence and hashids strategies only.
+func New(cfg Config, counter Counter) (Generator, error) {
+	alphabet, err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Strategy {
+	case StrategyRandom, "":
+		return NewGen(alphabet), nil
+	case StrategyHash:
+		return NewHash(alphabet), nil
+	case StrategyTime:
+		gen := NewTime(alphabet)
+		if minLength := gen.width + minRandomWidth; cfg.Length < minLength {
+			return nil, fmt.Errorf("%w: %q needs at least %d symbols", ErrLengthTooShort, cfg.Strategy, minLength)
+		}
+		return gen, nil
+	case StrategySequence, StrategyHashids:
+		if counter == nil {
+			return nil, fmt.Errorf("%w: %q", ErrNoCounter, cfg.Strategy)
+		}
+		// hashids takes one more symbol for the lottery
+		width := counterWidth(alphabet)
+		if cfg.Strategy == StrategyHashids {
+			width++
+		}
+		if cfg.MaxLength > 0 && width > max(cfg.MaxLength, cfg.Length) {
+			return nil, fmt.Errorf("%w: %q takes up to %d symbols", ErrAliasTooLong, cfg.Strategy, width)
+		}
+		if cfg.Strategy == StrategySequence {
+			return NewSequence(counter, alphabet, cfg.CounterTimeout), nil
+		}
+		return NewHashids(counter, alphabet, cfg.Salt, cfg.CounterTimeout), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, cfg.Strategy)
+	}
+}
@@ -3,38 +3,79 @@ package generator
 //go:generate mockgen -source=generate.go -destination=mock/mock.go generate
 
 import (
+	"context"
 	"crypto/rand"
+	"math"
 	"math/big"
 )
 
-const (
-	alphabet string = "_0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-)
-
+// Generator generates aliases of the given length for the normalized original url. Attempt starts from 1
+// and is increased after collisions, so that strategies deriving aliases from urls give colliding urls
+// other aliases. Other strategies ignore the url and the attempt.
 type Generator interface {
-	Random() (string, error)
+	Generate(original string, attempt, length int) (string, error)
 }
 
+// Counter returns unique increasing numbers, e.g. from a database sequence.
+type Counter interface {
+	Next(ctx context.Context) (uint64, error)
+}
+
+// CounterFunc adapts a function to the Counter interface.
+type CounterFunc func(ctx context.Context) (uint64, error)
+
+func (f CounterFunc) Next(ctx context.Context) (uint64, error) {
+	return f(ctx)
+}
+
+// Gen generates crypto-random aliases.
 type Gen struct {
-	Length int
+	alphabet Alphabet
 }
 
-func NewGen(length int) *Gen {
-	return &Gen{Length: length}
+func NewGen(alphabet Alphabet) *Gen {
+	return &Gen{alphabet: alphabet}
 }
 
-func (g *Gen) Random() (string, error) {
-	charsetLength := big.NewInt(int64(len(alphabet)))
-	result := make([]byte, g.Length)
+func (g *Gen) Generate(_ string, _, length int) (string, error) {
+	return g.Random(length)
+}
 
-	for i := 0; i < g.Length; i++ {
+func (g *Gen) Random(length int) (string, error) {
+	return random(g.alphabet.symbols(), length)
+}
+
+func random(digits string, length int) (string, error) {
+	charsetLength := big.NewInt(int64(len(digits)))
+	result := make([]byte, length)
+
+	for i := 0; i < length; i++ {
 		randomIndex, err := rand.Int(rand.Reader, charsetLength)
 		if err != nil {
 			return "", err
 		}
 
-		result[i] = alphabet[randomIndex.Int64()]
+		result[i] = digits[randomIndex.Int64()]
 	}
 
 	return string(result), nil
 }
+
+// encode writes n in the base of digits, left padded with the first digit to at least width digits.
+// counterWidth is the number of symbols of the largest counter number encoded with the alphabet.
+func counterWidth(alphabet Alphabet) int {
+	return len(encode(math.MaxUint64, alphabet.symbols(), 0))
+}
+
+func encode(n uint64, digits string, width int) string {
+	base := uint64(len(digits))
+	result := make([]byte, 0, max(width, 11))
+	for n > 0 || len(result) < width {
+		result = append(result, digits[n%base])
+		n /= base
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return string(result)
+}
@@ -7,12 +7,12 @@ import (
 )
 
 func TestGen_Random(t *testing.T) {
-	gen := Gen{Length: constant.AliasLength}
+	gen := Gen{}
 
 	uniqueStrings := make(map[string]struct{})
 
 	for i := 0; i < 10_000_000; i++ {
-		randomString, err := gen.Random()
+		randomString, err := gen.Random(constant.AliasLength)
 		require.NoError(t, err)
 
 		_, exists := uniqueStrings[randomString]
@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"strconv"
+)
+
+// Hash derives aliases from the sha256 hash of the original url, so equal urls get equal aliases
+// on every instance. Other attempts hash the url with the attempt number.
+type Hash struct {
+	alphabet Alphabet
+}
+
+func NewHash(alphabet Alphabet) *Hash {
+	return &Hash{alphabet: alphabet}
+}
+
+func (g *Hash) Generate(original string, attempt, length int) (string, error) {
+	data := original
+	if attempt > 1 {
+		data += "\x00" + strconv.Itoa(attempt)
+	}
+	sum := sha256.Sum256([]byte(data))
+
+	digits := g.alphabet.symbols()
+	n := new(big.Int).SetBytes(sum[:])
+	base := big.NewInt(int64(len(digits)))
+	digit := new(big.Int)
+
+	result := make([]byte, length)
+	for i := range result {
+		n.DivMod(n, base, digit)
+		result[i] = digits[digit.Int64()]
+	}
+
+	return string(result), nil
+}
@@ -0,0 +1,60 @@
+package generator
+
+import "time"
+
+// Hashids encodes numbers of the counter like Hashids does: the first symbol is taken by the number
+// from the alphabet shuffled by the salt, the rest encodes the number with the alphabet shuffled again
+// by that symbol and the salt. Consecutive numbers look unrelated, while aliases stay unique.
+type Hashids struct {
+	counter  Counter
+	salt     string
+	alphabet string
+	timeout  time.Duration
+}
+
+func NewHashids(counter Counter, alphabet Alphabet, salt string, timeout time.Duration) *Hashids {
+	return &Hashids{
+		counter:  counter,
+		salt:     salt,
+		alphabet: shuffle(alphabet.symbols(), salt),
+		timeout:  timeout,
+	}
+}
+
+func (g *Hashids) Generate(_ string, _, length int) (string, error) {
+	return g.Random(length)
+}
+
+func (g *Hashids) Random(length int) (string, error) {
+	n, err := next(g.counter, g.timeout)
+	if err != nil {
+		return "", err
+	}
+
+	return g.encode(n, length), nil
+}
+
+func (g *Hashids) encode(n uint64, length int) string {
+	lottery := g.alphabet[n%uint64(len(g.alphabet))]
+	digits := shuffle(g.alphabet, string(lottery)+g.salt)
+
+	return string(lottery) + encode(n, digits, length-1)
+}
+
+// shuffle is the consistent shuffle of Hashids, equal salts give equal permutations.
+func shuffle(alphabet, salt string) string {
+	if salt == "" {
+		return alphabet
+	}
+
+	result := []byte(alphabet)
+	for i, v, p := len(result)-1, 0, 0; i > 0; i, v = i-1, v+1 {
+		v %= len(salt)
+		c := int(salt[v])
+		p += c
+		j := (c + v + p) % i
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return string(result)
+}
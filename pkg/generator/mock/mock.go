@@ -5,10 +5,11 @@
 //
 //	mockgen -source=generate.go -destination=mock/mock.go generate
 //
-// Package mock_generate is a generated GoMock package.
-package mock_generate
+// Package mock_generator is a generated GoMock package.
+package mock_generator
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -37,17 +38,55 @@ func (m *MockGenerator) EXPECT() *MockGeneratorMockRecorder {
 	return m.recorder
 }
 
-// Random mocks base method.
-func (m *MockGenerator) Random() (string, error) {
+// Generate mocks base method.
+func (m *MockGenerator) Generate(original string, attempt, length int) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Random")
+	ret := m.ctrl.Call(m, "Generate", original, attempt, length)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Random indicates an expected call of Random.
-func (mr *MockGeneratorMockRecorder) Random() *gomock.Call {
+// Generate indicates an expected call of Generate.
+func (mr *MockGeneratorMockRecorder) Generate(original, attempt, length any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Random", reflect.TypeOf((*MockGenerator)(nil).Random))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockGenerator)(nil).Generate), original, attempt, length)
+}
+
+// MockCounter is a mock of Counter interface.
+type MockCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCounterMockRecorder
+}
+
+// MockCounterMockRecorder is the mock recorder for MockCounter.
+type MockCounterMockRecorder struct {
+	mock *MockCounter
+}
+
+// NewMockCounter creates a new mock instance.
+func NewMockCounter(ctrl *gomock.Controller) *MockCounter {
+	mock := &MockCounter{ctrl: ctrl}
+	mock.recorder = &MockCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCounter) EXPECT() *MockCounterMockRecorder {
+	return m.recorder
+}
+
+// Next mocks base method.
+func (m *MockCounter) Next(ctx context.Context) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next", ctx)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockCounterMockRecorder) Next(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockCounter)(nil).Next), ctx)
 }
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"context"
+	"time"
+)
+
+// Sequence encodes numbers of the counter with the alphabet, left padded to the requested length.
+// Numbers which do not fit the length take up to counterWidth symbols.
+// Aliases never collide while the counter is not reset.
+type Sequence struct {
+	counter  Counter
+	alphabet Alphabet
+	timeout  time.Duration
+}
+
+func NewSequence(counter Counter, alphabet Alphabet, timeout time.Duration) *Sequence {
+	return &Sequence{
+		counter:  counter,
+		alphabet: alphabet,
+		timeout:  timeout,
+	}
+}
+
+func (g *Sequence) Generate(_ string, _, length int) (string, error) {
+	return g.Random(length)
+}
+
+func (g *Sequence) Random(length int) (string, error) {
+	n, err := next(g.counter, g.timeout)
+	if err != nil {
+		return "", err
+	}
+
+	return encode(n, g.alphabet.symbols(), length), nil
+}
+
+// next takes the number of the counter, the interface has no context, so the timeout bounds the call.
+func next(counter Counter, timeout time.Duration) (uint64, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return counter.Next(ctx)
+}
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeCounter struct {
+	last atomic.Uint64
+}
+
+func (c *fakeCounter) Next(context.Context) (uint64, error) {
+	return c.last.Add(1), nil
+}
+
+func TestNew(t *testing.T) {
+	counter := &fakeCounter{}
+
+	testCases := []struct {
+		strategy      string
+		alphabet      string
+		length        int
+		maxLength     int
+		counter       Counter
+		expected      Generator
+		expectedError error
+	}{
+		{strategy: "", expected: &Gen{}},
+		{strategy: StrategyRandom, expected: &Gen{}},
+		{strategy: StrategyHash, expected: &Hash{}},
+		{strategy: StrategyTime, expected: &Time{}},
+		{strategy: StrategySequence, counter: counter, expected: &Sequence{}},
+		{strategy: StrategyHashids, counter: counter, expected: &Hashids{}},
+		{strategy: StrategySequence, expectedError: ErrNoCounter},
+		{strategy: "uuid", expectedError: ErrUnknownStrategy},
+		{strategy: StrategyRandom, alphabet: "human", expected: &Gen{}},
+		{strategy: StrategyRandom, alphabet: "a/b", expectedError: ErrInvalidAlphabet},
+		{strategy: StrategyRandom, length: 3, expectedError: ErrInvalidLength},
+		{strategy: StrategyTime, length: 8, expectedError: ErrLengthTooShort},
+		{strategy: StrategyTime, alphabet: "01", expectedError: ErrLengthTooShort},
+		{strategy: StrategySequence, maxLength: 16, counter: counter, expected: &Sequence{}},
+		{strategy: StrategySequence, alphabet: "01", maxLength: 16, counter: counter, expectedError: ErrAliasTooLong},
+		{strategy: StrategySequence, alphabet: "01", maxLength: 64, counter: counter, expected: &Sequence{}},
+		{strategy: StrategyHashids, alphabet: "01", maxLength: 64, counter: counter, expectedError: ErrAliasTooLong},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.strategy, func(t *testing.T) {
+			length := tc.length
+			if length == 0 {
+				length = 10
+			}
+			gen, err := New(Config{Strategy: tc.strategy, Alphabet: tc.alphabet, Length: length, MaxLength: tc.maxLength}, tc.counter)
+			require.ErrorIs(t, err, tc.expectedError)
+			if tc.expected != nil {
+				require.IsType(t, tc.expected, gen)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	require.Equal(t, "0000000000", encode(0, string(AlphabetBase62), 10))
+	require.Equal(t, "000000000Z", encode(61, string(AlphabetBase62), 10))
+	require.Equal(t, "0000000010", encode(62, string(AlphabetBase62), 10))
+	require.Equal(t, "lYGhA16ahyf", encode(1<<64-1, string(AlphabetBase62), 10))
+
+	require.Equal(t, 11, counterWidth(AlphabetBase62))
+	require.Equal(t, 64, counterWidth("01"))
+}
+
+func TestSequence_Random(t *testing.T) {
+	gen := NewSequence(&fakeCounter{}, AlphabetDefault, time.Second)
+
+	for _, expected := range []string{"0000000001", "0000000002", "0000000003"} {
+		alias, err := gen.Random(10)
+		require.NoError(t, err)
+		require.Equal(t, expected, alias)
+	}
+
+	gen = NewSequence(CounterFunc(func(context.Context) (uint64, error) {
+		return 0, errors.New("connection refused")
+	}), AlphabetDefault, time.Second)
+	_, err := gen.Random(10)
+	require.Error(t, err)
+}
+
+func TestHashids_Random(t *testing.T) {
+	gen := NewHashids(&fakeCounter{}, AlphabetDefault, "salt", time.Second)
+
+	aliases := make(map[string]struct{})
+	var previous string
+	for i := 0; i < 100_000; i++ {
+		alias, err := gen.Random(10)
+		require.NoError(t, err)
+		require.Len(t, alias, 10)
+		require.True(t, AlphabetDefault.Contains(alias))
+		require.NotEqual(t, previous[:min(len(previous), 9)], alias[:9])
+
+		_, exists := aliases[alias]
+		require.False(t, exists)
+		aliases[alias] = struct{}{}
+		previous = alias
+	}
+
+	// the salt changes aliases of the same numbers
+	other, err := NewHashids(&fakeCounter{}, AlphabetDefault, "other", time.Second).Random(10)
+	require.NoError(t, err)
+	first, err := NewHashids(&fakeCounter{}, AlphabetDefault, "salt", time.Second).Random(10)
+	require.NoError(t, err)
+	require.NotEqual(t, first, other)
+}
+
+func TestHash_Generate(t *testing.T) {
+	gen := NewHash(AlphabetDefault)
+
+	alias, err := gen.Generate("https://google.com/", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, alias, 10)
+	require.True(t, AlphabetDefault.Contains(alias))
+
+	again, err := gen.Generate("https://google.com/", 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, alias, again)
+
+	retry, err := gen.Generate("https://google.com/", 2, 10)
+	require.NoError(t, err)
+	require.NotEqual(t, alias, retry)
+
+	other, err := gen.Generate("https://google.com/a", 1, 10)
+	require.NoError(t, err)
+	require.NotEqual(t, alias, other)
+}
+
+func TestTime_Random(t *testing.T) {
+	gen := NewTime(AlphabetDefault)
+	now := timeEpoch.Add(time.Hour)
+	gen.now = func() time.Time { return now }
+
+	var aliases []string
+	for i := 0; i < 100; i++ {
+		alias, err := gen.Random(10)
+		require.NoError(t, err)
+		require.Len(t, alias, 10)
+		require.True(t, AlphabetDefault.Contains(alias))
+		aliases = append(aliases, alias)
+		now = now.Add(time.Millisecond)
+	}
+	require.True(t, sort.StringsAreSorted(aliases))
+
+	alias, err := gen.Random(7 + minRandomWidth)
+	require.NoError(t, err)
+	require.Len(t, alias, 7+minRandomWidth)
+
+	_, err = gen.Random(8)
+	require.ErrorIs(t, err, ErrLengthTooShort)
+
+	// short alphabets take more symbols for the time
+	require.Equal(t, 8, NewTime(AlphabetHuman).width)
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// minRandomWidth symbols follow the time even in short aliases.
+const minRandomWidth = 2
+
+var timeEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// timeRange is how many milliseconds after timeEpoch fit the time part of aliases.
+const timeRange = 100 * 365 * 24 * uint64(time.Hour/time.Millisecond)
+
+// Time starts aliases with milliseconds of creation encoded with sorted symbols,
+// so aliases sort in order of creation, the rest of the alias is random. Aliases
+// have at least minRandomWidth random symbols, shorter lengths are rejected.
+type Time struct {
+	digits string
+	// width is the number of symbols of the time, 7 for 62 symbols
+	width int
+	now   func() time.Time
+}
+
+func NewTime(alphabet Alphabet) *Time {
+	digits := []byte(alphabet.symbols())
+	sort.Slice(digits, func(i, j int) bool {
+		return digits[i] < digits[j]
+	})
+
+	width := 1
+	for n := timeRange / uint64(len(digits)); n > 0; n /= uint64(len(digits)) {
+		width++
+	}
+
+	return &Time{
+		digits: string(digits),
+		width:  width,
+		now:    time.Now,
+	}
+}
+
+func (g *Time) Generate(_ string, _, length int) (string, error) {
+	return g.Random(length)
+}
+
+func (g *Time) Random(length int) (string, error) {
+	millis := g.now().Sub(timeEpoch).Milliseconds()
+	if millis < 0 {
+		millis = 0
+	}
+
+	if length < g.width+minRandomWidth {
+		return "", fmt.Errorf("%w: %d symbols, %d at least", ErrLengthTooShort, length, g.width+minRandomWidth)
+	}
+
+	suffix, err := random(g.digits, length-g.width)
+	if err != nil {
+		return "", err
+	}
+
+	return encode(uint64(millis), g.digits, g.width) + suffix, nil
+}
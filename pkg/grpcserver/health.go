@@ -0,0 +1,68 @@
+package grpcserver
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"time"
+)
+
+// HealthCheckMethod is called by probes, it must not require authentication.
+const HealthCheckMethod = healthpb.Health_Check_FullMethodName
+
+// CheckFunc reports whether the server is ready to serve requests.
+type CheckFunc func(ctx context.Context) bool
+
+// HealthServer implements grpc.health.v1 with statuses driven by CheckFunc.
+// Check refreshes statuses on every call, Watch sees changes found by Run.
+type HealthServer struct {
+	*health.Server
+	srv   *grpc.Server
+	check CheckFunc
+}
+
+func newHealthServer(srv *grpc.Server) *HealthServer {
+	return &HealthServer{
+		Server: health.NewServer(),
+		srv:    srv,
+	}
+}
+
+// SetCheck sets the readiness check, without it the server is always serving.
+func (h *HealthServer) SetCheck(check CheckFunc) {
+	h.check = check
+}
+
+func (h *HealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	h.Update(ctx)
+	return h.Server.Check(ctx, req)
+}
+
+// Update sets the status of the server and all registered services. Statuses are not changed after Shutdown.
+func (h *HealthServer) Update(ctx context.Context) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if h.check != nil && !h.check(ctx) {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	h.SetServingStatus("", status)
+	for service := range h.srv.GetServiceInfo() {
+		h.SetServingStatus(service, status)
+	}
+}
+
+// Run updates statuses every interval until ctx is done.
+func (h *HealthServer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Update(ctx)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package grpcserver
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"sync/atomic"
+	"testing"
+)
+
+func TestServer_Health(t *testing.T) {
+	ctx := context.Background()
+
+	srv := NewServer(Config{})
+
+	var ready atomic.Bool
+	ready.Store(true)
+	srv.Health.SetCheck(func(context.Context) bool {
+		return ready.Load()
+	})
+
+	lsn := bufconn.Listen(1 << 20)
+	go func() {
+		_ = srv.Srv.Serve(lsn)
+	}()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lsn.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return resp.GetStatus()
+	}
+
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, check(""))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, check(healthpb.Health_ServiceDesc.ServiceName))
+
+	ready.Store(false)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(""))
+
+	ready.Store(true)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, check(""))
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.NoError(t, stream.CloseSend())
+
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	require.Contains(t, services, healthpb.Health_ServiceDesc.ServiceName)
+
+	srv.Health.Shutdown()
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(""))
+
+	srv.Stop()
+}
@@ -3,7 +3,9 @@ package grpcserver
 import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 	"net"
 	"strconv"
 	"time"
@@ -19,8 +21,9 @@ type Config struct {
 }
 
 type Server struct {
-	Srv *grpc.Server
-	Cfg Config
+	Srv    *grpc.Server
+	Cfg    Config
+	Health *HealthServer
 }
 
 func NewServer(cfg Config, opts ...grpc.ServerOption) *Server {
@@ -38,9 +41,14 @@ func NewServer(cfg Config, opts ...grpc.ServerOption) *Server {
 
 	srv := grpc.NewServer(serverOptions...)
 
+	healthSrv := newHealthServer(srv)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	reflection.Register(srv)
+
 	return &Server{
-		Srv: srv,
-		Cfg: cfg,
+		Srv:    srv,
+		Cfg:    cfg,
+		Health: healthSrv,
 	}
 }
 
@@ -53,6 +61,8 @@ func (s *Server) Start() error {
 	return s.Srv.Serve(lsn)
 }
 
+// Stop reports NOT_SERVING to health checks before waiting for running calls.
 func (s *Server) Stop() {
+	s.Health.Shutdown()
 	s.Srv.GracefulStop()
 }
@@ -0,0 +1,155 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK           = "ok"
+	StatusUnavailable  = "unavailable"
+	StatusShuttingDown = "shutting_down"
+)
+
+type Config struct {
+	// Timeout bounds every component check.
+	Timeout time.Duration `yaml:"timeout" env:"HEALTH_TIMEOUT" env-default:"1s"`
+	// CacheTTL is how long results are reused, so frequent probes do not load dependencies.
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"HEALTH_CACHE_TTL" env-default:"1s"`
+	// ShutdownDelay is how long the service reports shutting down before servers stop,
+	// so load balancers have time to drain traffic.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"HEALTH_SHUTDOWN_DELAY"`
+}
+
+// Checker reports whether a dependency is available.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckFunc adapts a function to the Checker.
+type CheckFunc func(ctx context.Context) error
+
+func (f CheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type ComponentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Health checks registered components and caches the results.
+type Health struct {
+	cfg Config
+	now func() time.Time
+
+	mu         sync.Mutex
+	components map[string]Checker
+	report     Report
+	checkedAt  time.Time
+
+	shuttingDown atomic.Bool
+}
+
+func New(cfg Config) *Health {
+	return &Health{
+		cfg:        cfg,
+		now:        time.Now,
+		components: make(map[string]Checker),
+	}
+}
+
+// Register adds the component to checks, a component with the same name is replaced.
+func (h *Health) Register(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.components[name] = checker
+	h.checkedAt = time.Time{}
+}
+
+// Shutdown makes all following checks report shutting down.
+func (h *Health) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Check returns cached results if they are fresh, otherwise checks all components in parallel.
+// Concurrent callers wait for one check instead of starting their own.
+func (h *Health) Check(ctx context.Context) Report {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.checkedAt.IsZero() || h.now().Sub(h.checkedAt) >= h.cfg.CacheTTL {
+		h.report = h.check(ctx)
+		h.checkedAt = h.now()
+	}
+
+	report := Report{
+		Status:     h.report.Status,
+		Components: make(map[string]ComponentStatus, len(h.report.Components)),
+	}
+	for name, status := range h.report.Components {
+		report.Components[name] = status
+	}
+	if h.shuttingDown.Load() {
+		report.Status = StatusShuttingDown
+	}
+
+	return report
+}
+
+func (h *Health) check(ctx context.Context) Report {
+	names := make([]string, 0, len(h.components))
+	for name := range h.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	statuses := make([]ComponentStatus, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+
+			checkCtx := ctx
+			if h.cfg.Timeout > 0 {
+				var cancel context.CancelFunc
+				checkCtx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+				defer cancel()
+			}
+
+			statuses[i] = ComponentStatus{Status: StatusOK}
+			if err := checker.Check(checkCtx); err != nil {
+				statuses[i] = ComponentStatus{Status: StatusUnavailable, Error: err.Error()}
+			}
+		}(i, h.components[name])
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:     StatusOK,
+		Components: make(map[string]ComponentStatus, len(names)),
+	}
+	for i, name := range names {
+		report.Components[name] = statuses[i]
+		if statuses[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+
+	return report
+}
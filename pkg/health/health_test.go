@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealth_Check(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	h := New(Config{Timeout: 50 * time.Millisecond, CacheTTL: time.Second})
+	h.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	var storageErr error
+	h.Register("postgres", CheckFunc(func(context.Context) error {
+		calls.Add(1)
+		return storageErr
+	}))
+	h.Register("slow", CheckFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	report := h.Check(ctx)
+	require.Equal(t, Report{
+		Status: StatusUnavailable,
+		Components: map[string]ComponentStatus{
+			"postgres": {Status: StatusOK},
+			"slow":     {Status: StatusUnavailable, Error: context.DeadlineExceeded.Error()},
+		},
+	}, report)
+	require.False(t, report.OK())
+
+	h.Register("slow", CheckFunc(func(context.Context) error { return nil }))
+	require.True(t, h.Check(ctx).OK())
+	require.EqualValues(t, 2, calls.Load())
+
+	// results are cached
+	storageErr = errors.New("connection refused")
+	require.True(t, h.Check(ctx).OK())
+	require.EqualValues(t, 2, calls.Load())
+
+	now = now.Add(time.Second)
+	report = h.Check(ctx)
+	require.Equal(t, StatusUnavailable, report.Status)
+	require.Equal(t, ComponentStatus{Status: StatusUnavailable, Error: "connection refused"}, report.Components["postgres"])
+
+	storageErr = nil
+	now = now.Add(time.Second)
+	h.Shutdown()
+	report = h.Check(ctx)
+	require.Equal(t, StatusShuttingDown, report.Status)
+	require.Equal(t, StatusOK, report.Components["postgres"].Status)
+}
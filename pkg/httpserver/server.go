@@ -14,6 +14,9 @@ type Config struct {
 	ReadTimeout     time.Duration `yaml:"read_timeout" env-default:"3s"`
 	WriteTimeout    time.Duration `yaml:"write_timeout" env-default:"5s"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"5s"`
+	// TrustedProxies are addresses or CIDRs whose X-Forwarded-For and X-Real-IP headers are trusted,
+	// the client ip of other requests is the remote address.
+	TrustedProxies []string `yaml:"trusted_proxies" env:"HTTP_SERVER_TRUSTED_PROXIES"`
 }
 
 type Server struct {
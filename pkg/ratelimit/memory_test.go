@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	limiter := NewMemoryLimiter(2, 3)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		res, err := limiter.Allow(ctx, "ip:127.0.0.1")
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	}
+
+	res, err := limiter.Allow(ctx, "ip:127.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, Result{RetryAfter: 500 * time.Millisecond}, res)
+
+	// buckets of other clients are independent
+	res, err = limiter.Allow(ctx, "ip:127.0.0.2")
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	now = now.Add(500 * time.Millisecond)
+	res, err = limiter.Allow(ctx, "ip:127.0.0.1")
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	now = now.Add(sweepInterval)
+	_, err = limiter.Allow(ctx, "ip:127.0.0.3")
+	require.NoError(t, err)
+	require.Len(t, limiter.buckets, 1)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.NoError(t, Config{}.Validate())
+	require.NoError(t, Config{Enabled: true, Backend: BackendRedis, Rate: 1, Burst: 1}.Validate())
+	require.ErrorIs(t, Config{Enabled: true, Backend: "etcd", Rate: 1, Burst: 1}.Validate(), ErrInvalidBackend)
+	require.Error(t, Config{Enabled: true, Backend: BackendMemory, Burst: 1}.Validate())
+	require.Error(t, Config{Enabled: true, Backend: BackendMemory, Rate: 1}.Validate())
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+//go:generate mockgen -source=ratelimit.go -destination=mock/mock.go ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+var ErrInvalidBackend = errors.New("invalid rate limit backend")
+
+type Config struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	// Backend keeps buckets in process memory or in redis,
+	// redis buckets are shared between replicas.
+	Backend string `yaml:"backend" env:"RATE_LIMIT_BACKEND" env-default:"memory"`
+	// Rate is how many requests per second a client makes in the long run.
+	Rate float64 `yaml:"rate" env:"RATE_LIMIT_RATE" env-default:"10"`
+	// Burst is how many requests a client makes at once.
+	Burst int `yaml:"burst" env:"RATE_LIMIT_BURST" env-default:"20"`
+}
+
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Backend != BackendMemory && c.Backend != BackendRedis {
+		return fmt.Errorf("%w: %q", ErrInvalidBackend, c.Backend)
+	}
+	if c.Rate <= 0 {
+		return fmt.Errorf("rate limit rate must be positive, got %v", c.Rate)
+	}
+	if c.Burst < 1 {
+		return fmt.Errorf("rate limit burst must be at least 1, got %d", c.Burst)
+	}
+	return nil
+}
+
+type Result struct {
+	Allowed bool
+	// RetryAfter is how long to wait until the next request is allowed.
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	// Allow takes a token from the bucket of the key.
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Unlimited allows every request, it is used when rate limiting is disabled.
+type Unlimited struct{}
+
+func (Unlimited) Allow(context.Context, string) (Result, error) {
+	return Result{Allowed: true}, nil
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"time"
+)
+
+const redisKeyPrefix = "rate_limit:"
+
+// tokenBucket refills and takes a token atomically using redis server time,
+// so clocks of replicas do not matter. It returns whether the request is allowed
+// and microseconds to wait otherwise.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000000 * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate * 1000000)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, retry}
+`)
+
+// RedisLimiter keeps token buckets in redis, so limits hold across replicas.
+type RedisLimiter struct {
+	*redisdb.Redis
+	rate  float64
+	burst int
+}
+
+func NewRedisLimiter(db *redisdb.Redis, rate float64, burst int) *RedisLimiter {
+	return &RedisLimiter{
+		Redis: db,
+		rate:  rate,
+		burst: burst,
+	}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	res, err := tokenBucket.Run(ctx, l.Client, []string{redisKeyPrefix + key}, l.rate, l.burst).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("RedisLimiter.Allow - tokenBucket.Run: %v", err)
+	}
+	if len(res) != 2 {
+		return Result{}, fmt.Errorf("RedisLimiter.Allow - unexpected script result: %v", res)
+	}
+
+	if res[0] == 1 {
+		return Result{Allowed: true}, nil
+	}
+
+	return Result{RetryAfter: time.Duration(res[1]) * time.Microsecond}, nil
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redismock/v9"
+	redisdb "github.com/romandnk/shortener/pkg/storage/redis"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRedisLimiter_Allow(t *testing.T) {
+	testCases := []struct {
+		name           string
+		scriptResult   []interface{}
+		error          error
+		expectedResult Result
+		expectedError  bool
+	}{
+		{
+			name:           "allowed",
+			scriptResult:   []interface{}{int64(1), int64(0)},
+			expectedResult: Result{Allowed: true},
+		},
+		{
+			name:           "limited",
+			scriptResult:   []interface{}{int64(0), int64(250000)},
+			expectedResult: Result{RetryAfter: 250 * time.Millisecond},
+		},
+		{
+			name:          "redis error",
+			error:         errors.New("connection refused"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+
+			expect := mock.ExpectEvalSha(tokenBucket.Hash(), []string{"rate_limit:ip:127.0.0.1"}, 2.0, 3)
+			if tc.error != nil {
+				expect.SetErr(tc.error)
+			} else {
+				expect.SetVal(tc.scriptResult)
+			}
+
+			limiter := NewRedisLimiter(&redisdb.Redis{Client: db}, 2, 3)
+
+			res, err := limiter.Allow(context.Background(), "ip:127.0.0.1")
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedResult, res)
+
+			require.NoError(t, mock.ExpectationsWereMet(), "there was unexpected result")
+		})
+	}
+}
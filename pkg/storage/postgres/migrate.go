@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+var ErrUnknownVersion = errors.New("unknown schema version")
+
+// Migrator applies and rolls back migrations named {version}_{title}.up.sql and {version}_{title}.down.sql
+// with golang-migrate, so the schema is shared with the migrate cli. golang-migrate holds an advisory lock
+// while migrating, so replicas starting at once apply each migration only once.
+type Migrator struct {
+	m        *migrate.Migrate
+	versions []uint
+}
+
+func NewMigrator(db *Postgres, fsys fs.FS) (*Migrator, error) {
+	source, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	versions, err := Versions(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, ok := db.Pool.(*pgxpool.Pool)
+	if !ok {
+		return nil, errors.New("migrations need a pgx pool")
+	}
+
+	driver, err := pgxmigrate.WithInstance(stdlib.OpenDBFromPool(pool), &pgxmigrate.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("error opening migrations driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "pgx5", driver)
+	if err != nil {
+		return nil, fmt.Errorf("error creating migrator: %w", err)
+	}
+
+	return &Migrator{
+		m:        m,
+		versions: versions,
+	}, nil
+}
+
+// Versions returns versions of the migrations in fsys in ascending order.
+func Versions(fsys fs.FS) ([]uint, error) {
+	source, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+	defer source.Close()
+
+	var versions []uint
+	version, err := source.First()
+	for err == nil {
+		versions = append(versions, version)
+		version, err = source.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	return versions, nil
+}
+
+// Latest returns the version of the last known migration, 0 if there are none.
+func (m *Migrator) Latest() uint64 {
+	if len(m.versions) == 0 {
+		return 0
+	}
+	return uint64(m.versions[len(m.versions)-1])
+}
+
+// Version returns the schema version, 0 means no migration is applied.
+func (m *Migrator) Version() (uint64, bool, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil {
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	return uint64(version), dirty, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.m.Up())
+}
+
+// Down rolls back at most steps migrations, steps less than 1 rolls back all of them.
+func (m *Migrator) Down(steps int) error {
+	if steps < 1 {
+		return ignoreNoChange(m.m.Down())
+	}
+
+	err := m.m.Steps(-steps)
+	// fewer migrations than steps are applied, all of them are rolled back
+	if errors.As(err, &migrate.ErrShortLimit{}) {
+		return nil
+	}
+	return ignoreNoChange(err)
+}
+
+// Force sets the schema version and clears the dirty flag without running migrations.
+func (m *Migrator) Force(version uint64) error {
+	if version == 0 {
+		return m.m.Force(database.NilVersion)
+	}
+	if !slices.Contains(m.versions, uint(version)) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.m.Force(int(version))
+}
+
+// Close releases the connection taken from the pool, the pool itself stays open.
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.m.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"testing/fstest"
+)
+
+func TestVersions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_urls_expires_at.up.sql":   {Data: []byte("ALTER TABLE urls ADD COLUMN expires_at TIMESTAMPTZ;")},
+		"000002_urls_expires_at.down.sql": {Data: []byte("ALTER TABLE urls DROP COLUMN expires_at;")},
+		"000001_urls.up.sql":              {Data: []byte("CREATE TABLE urls (alias TEXT);")},
+		"000001_urls.down.sql":            {Data: []byte("DROP TABLE urls;")},
+	}
+
+	versions, err := Versions(fsys)
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 2}, versions)
+
+	versions, err = Versions(fstest.MapFS{})
+	require.NoError(t, err)
+	require.Empty(t, versions)
+}
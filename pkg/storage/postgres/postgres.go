@@ -20,6 +20,7 @@ type PgxPool interface {
 	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
 	Ping(ctx context.Context) error
+	Stat() *pgxpool.Stat
 }
 
 type Config struct {
@@ -31,6 +32,8 @@ type Config struct {
 	SSLMode  string `yaml:"ssl_mode" env:"POSTGRES_SSLMODE"`
 	MaxConns int32  `yaml:"max_conns"`
 	MinConns int32  `yaml:"min_conns"`
+	// Migrate applies pending migrations on start.
+	Migrate bool `yaml:"migrate" env:"POSTGRES_MIGRATE"`
 }
 
 type Postgres struct {
@@ -59,6 +62,7 @@ func New(ctx context.Context, cfg Config) (*Postgres, error) {
 
 	pgxConf.MaxConns = cfg.MaxConns
 	pgxConf.MinConns = cfg.MinConns
+	pgxConf.ConnConfig.Tracer = Tracer{}
 
 	db, err := pgxpool.NewWithConfig(ctx, pgxConf)
 	if err != nil {
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+func TestTracer_TraceQueryEnd(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode codes.Code
+	}{
+		{
+			name:         "OK",
+			expectedCode: codes.Unset,
+		},
+		{
+			name:         "no rows",
+			err:          pgx.ErrNoRows,
+			expectedCode: codes.Unset,
+		},
+		{
+			name:         "wrapped no rows",
+			err:          fmt.Errorf("scan: %w", pgx.ErrNoRows),
+			expectedCode: codes.Unset,
+		},
+		{
+			name:         "error",
+			err:          errors.New("connection refused"),
+			expectedCode: codes.Error,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			ctx := Tracer{}.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "select 1"})
+			Tracer{}.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: tc.err})
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			require.Equal(t, "postgres SELECT", spans[0].Name())
+			require.Equal(t, tc.expectedCode, spans[0].Status().Code)
+		})
+	}
+}
@@ -26,6 +26,8 @@ func New(ctx context.Context, cfg Config) (*Redis, error) {
 		DB:       0,
 	})
 
+	rdb.AddHook(TracingHook{})
+
 	ping := rdb.Ping(ctx)
 	if err := ping.Err(); err != nil {
 		return r, err
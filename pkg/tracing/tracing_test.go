@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+
+	shutdown, err := New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(ctx))
+	require.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields())
+
+	_, err = New(ctx, Config{Enabled: true, Exporter: "jaeger"})
+	require.ErrorIs(t, err, ErrInvalidExporter)
+
+	shutdown, err = New(ctx, Config{Enabled: true, Exporter: ExporterStdout, ServiceName: "shortener", SampleRatio: 1})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(ctx))
+}
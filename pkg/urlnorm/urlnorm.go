@@ -0,0 +1,193 @@
+// Package urlnorm brings urls to a canonical form, so that equal urls written differently are stored once.
+package urlnorm
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/net/idna"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidURL = errors.New("invalid url")
+
+// defaultPorts are removed from hosts of urls with these schemes.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+type Config struct {
+	// StripParams are query parameters removed from urls, a name ending with * matches by prefix.
+	// An empty list keeps all parameters.
+	StripParams []string `yaml:"strip_params" env-default:"utm_*,fbclid,gclid,yclid,dclid,msclkid,mc_cid,mc_eid,_openstat"`
+	// SortQuery sorts query parameters by name, values of one name keep their order.
+	SortQuery bool `yaml:"sort_query" env:"URL_NORMALIZE_SORT_QUERY"`
+}
+
+type Normalizer struct {
+	exact    map[string]struct{}
+	prefixes []string
+	sort     bool
+}
+
+func New(cfg Config) *Normalizer {
+	n := Normalizer{
+		exact: make(map[string]struct{}),
+		sort:  cfg.SortQuery,
+	}
+	for _, param := range cfg.StripParams {
+		param = strings.ToLower(strings.TrimSpace(param))
+		switch {
+		case param == "":
+		case strings.HasSuffix(param, "*"):
+			n.prefixes = append(n.prefixes, strings.TrimSuffix(param, "*"))
+		default:
+			n.exact[param] = struct{}{}
+		}
+	}
+
+	return &n
+}
+
+// Normalize lowercases the scheme and the host, converts international domain names to punycode,
+// removes the default port, resolves dot segments of the path and strips and sorts query parameters.
+// Userinfo and the fragment are kept as is, opaque urls only get the scheme lowercased.
+func (n *Normalizer) Normalize(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Opaque != "" {
+		return u.String(), nil
+	}
+
+	if u.Host != "" {
+		u.Host, err = normalizeHost(u.Scheme, u.Host)
+		if err != nil {
+			return "", err
+		}
+		if u.Path == "" {
+			u.Path = "/"
+		}
+	}
+
+	if err = cleanPath(u); err != nil {
+		return "", err
+	}
+
+	u.RawQuery = n.normalizeQuery(u.RawQuery)
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+func normalizeHost(scheme, hostport string) (string, error) {
+	host, port := hostport, ""
+	if i := strings.LastIndexByte(hostport, ':'); i >= 0 && !strings.HasSuffix(hostport, "]") {
+		host, port = hostport[:i], hostport[i+1:]
+	}
+	if port == defaultPorts[scheme] {
+		port = ""
+	}
+
+	if strings.HasPrefix(host, "[") {
+		// ipv6 literals are only lowercased
+		host = strings.ToLower(host)
+	} else if net.ParseIP(host) == nil {
+		ascii, err := idna.Lookup.ToASCII(strings.TrimSuffix(host, "."))
+		if err != nil {
+			return "", fmt.Errorf("%w: host %q: %v", ErrInvalidURL, host, err)
+		}
+		host = ascii
+	}
+
+	if port == "" {
+		return host, nil
+	}
+	return host + ":" + port, nil
+}
+
+// cleanPath resolves dot segments and duplicate slashes keeping the trailing slash and the escaping of the path.
+func cleanPath(u *url.URL) error {
+	escaped := u.EscapedPath()
+	if escaped == "" {
+		return nil
+	}
+
+	cleaned := path.Clean(escaped)
+	if strings.HasSuffix(escaped, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+
+	unescaped, err := url.PathUnescape(cleaned)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	u.Path, u.RawPath = unescaped, cleaned
+
+	return nil
+}
+
+// normalizeQuery works on the raw query, so parameters keep their escaping and, unless sorted, their order.
+func (n *Normalizer) normalizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	type param struct {
+		name string
+		raw  string
+	}
+
+	var params []param
+	for _, raw := range strings.Split(rawQuery, "&") {
+		if raw == "" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(raw, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if n.strip(name) {
+			continue
+		}
+
+		params = append(params, param{name: name, raw: raw})
+	}
+
+	if n.sort {
+		sort.SliceStable(params, func(i, j int) bool {
+			return params[i].name < params[j].name
+		})
+	}
+
+	raws := make([]string, len(params))
+	for i, p := range params {
+		raws[i] = p.raw
+	}
+
+	return strings.Join(raws, "&")
+}
+
+func (n *Normalizer) strip(name string) bool {
+	name = strings.ToLower(name)
+	if _, ok := n.exact[name]; ok {
+		return true
+	}
+	for _, prefix := range n.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}